@@ -0,0 +1,93 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"events-system/user"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// commonAvailabilityRequest asks when all of the users are free for duration_hours within [from, to),
+// given in epoch seconds.
+type commonAvailabilityRequest struct {
+	UserIDs       []string `json:"user_ids"`
+	DurationHours int      `json:"duration_hours"`
+	From          int64    `json:"from"`
+	To            int64    `json:"to"`
+}
+
+type commonAvailabilityResponse struct {
+	Windows []user.Slot `json:"windows"`
+}
+
+func (a *API) getCommonAvailability(w http.ResponseWriter, r *http.Request) {
+	var req commonAvailabilityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		a.invalidBody(w, err)
+		return
+	}
+	if len(req.UserIDs) == 0 {
+		a.Response(w, http.StatusBadRequest, "at least one user ID is required")
+		return
+	}
+	if req.DurationHours <= 0 {
+		a.invalidPayload(w, errors.New("duration hours must be greater than 0"))
+		return
+	}
+	from, to := time.Unix(req.From, 0).UTC(), time.Unix(req.To, 0).UTC()
+	if err := validateSlotBounds(from, to); err != nil {
+		a.invalidPayload(w, err)
+		return
+	}
+	if !to.After(from) {
+		a.invalidPayload(w, errors.New("to must be after from"))
+		return
+	}
+
+	userIDs := make([]uuid.UUID, 0, len(req.UserIDs))
+	for _, rawID := range req.UserIDs {
+		userID, err := uuid.Parse(rawID)
+		if err != nil {
+			a.Response(w, http.StatusBadRequest, fmt.Sprintf("invalid user ID %q", rawID))
+			return
+		}
+		userIDs = append(userIDs, userID)
+	}
+	if _, ok := a.lookupUsers(w, r, userIDs); !ok {
+		return
+	}
+
+	slots, err := a.userAccessor().GetUsersSlots(r.Context(), userIDs)
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+	slotsByUser := make([][]user.Slot, len(userIDs))
+	for i, userID := range userIDs {
+		slotsByUser[i] = slots[userID]
+	}
+
+	duration := time.Duration(req.DurationHours) * time.Hour
+	a.Response(w, http.StatusOK, commonAvailabilityResponse{Windows: user.CommonAvailability(slotsByUser, from, to, duration)})
+}
+
+// deleteAvailability purges the availability slots of all users within the ?from= and ?to= window,
+// both required so that a bare DELETE cannot wipe everything.
+func (a *API) deleteAvailability(w http.ResponseWriter, r *http.Request) {
+	from, to, err := parseWindow(r)
+	if err != nil {
+		a.Response(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	deleted, err := a.userAccessor().DeleteSlotsInRange(r.Context(), from, to)
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+	a.Response(w, http.StatusOK, map[string]int64{"deleted": deleted})
+}
@@ -0,0 +1,126 @@
+package api_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommonAvailabilityAPI(t *testing.T) {
+	t.Parallel()
+
+	from := time.Date(2030, 1, 1, 9, 0, 0, 0, time.UTC)
+	to := from.Add(8 * time.Hour)
+
+	t.Run("common windows", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupUsersAPI(t)
+
+		aliceID, bobID := uuid.New(), uuid.New()
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = ANY($1)`)).
+			WillReturnRows(sqlmock.NewRows(userColumns).
+				AddRow(aliceID, "Alice", "alice@example.com", userCreatedAt, userCreatedAt).
+				AddRow(bobID, "Bob", "bob@example.com", userCreatedAt, userCreatedAt))
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT user_id, start_time, end_time FROM users_availability WHERE user_id = ANY($1)`)).
+			WillReturnRows(sqlmock.NewRows([]string{"user_id", "start_time", "end_time"}).
+				AddRow(aliceID, from, from.Add(4*time.Hour)).
+				AddRow(bobID, from.Add(time.Hour), from.Add(6*time.Hour)))
+
+		body := fmt.Sprintf(`{"user_ids":[%q,%q],"duration_hours":2,"from":%d,"to":%d}`, aliceID, bobID, from.Unix(), to.Unix())
+		rec := httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, jsonRequest(http.MethodPost, "/api/availability/common", strings.NewReader(body)))
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, fmt.Sprintf(`{"status":200,"response":{"windows":[{"start_time":%d,"end_time":%d}]}}`,
+			from.Add(time.Hour).Unix(), from.Add(4*time.Hour).Unix()), rec.Body.String())
+		require.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupUsersAPI(t)
+
+		// Only Alice exists, Bob is reported missing.
+		aliceID, bobID := uuid.New(), uuid.New()
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = ANY($1)`)).
+			WillReturnRows(sqlmock.NewRows(userColumns).AddRow(aliceID, "Alice", "alice@example.com", userCreatedAt, userCreatedAt))
+
+		body := fmt.Sprintf(`{"user_ids":[%q,%q],"duration_hours":2,"from":%d,"to":%d}`, aliceID, bobID, from.Unix(), to.Unix())
+		rec := httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, jsonRequest(http.MethodPost, "/api/availability/common", strings.NewReader(body)))
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.JSONEq(t, fmt.Sprintf(`{"status":404,"response":"user %s not found"}`, bobID), rec.Body.String())
+		require.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		t.Parallel()
+		a, _ := setupUsersAPI(t)
+
+		for _, tc := range []struct {
+			body   string
+			status int
+		}{
+			{body: `{"user_ids":[],"duration_hours":2,"from":1893488400,"to":1893517200}`, status: http.StatusBadRequest},
+			{body: `{"user_ids":["nope"],"duration_hours":2,"from":1893488400,"to":1893517200}`, status: http.StatusBadRequest},
+			// Bodies that decode but ask for an impossible window fail validation.
+			{body: fmt.Sprintf(`{"user_ids":[%q],"duration_hours":0,"from":1893488400,"to":1893517200}`, uuid.New()), status: http.StatusUnprocessableEntity},
+			{body: fmt.Sprintf(`{"user_ids":[%q],"duration_hours":2,"from":1893517200,"to":1893488400}`, uuid.New()), status: http.StatusUnprocessableEntity},
+			{body: fmt.Sprintf(`{"user_ids":[%q],"duration_hours":2,"from":0,"to":1893488400}`, uuid.New()), status: http.StatusUnprocessableEntity},
+		} {
+			rec := httptest.NewRecorder()
+			a.Router().ServeHTTP(rec, jsonRequest(http.MethodPost, "/api/availability/common", strings.NewReader(tc.body)))
+			assert.Equal(t, tc.status, rec.Code, tc.body)
+		}
+	})
+}
+
+func TestDeleteAvailabilityAPI(t *testing.T) {
+	t.Parallel()
+
+	from := time.Date(2030, 12, 25, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+
+	t.Run("deletes slots in the window", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupUsersAPI(t)
+
+		dbMock.ExpectExec(regexp.QuoteMeta(`DELETE FROM users_availability WHERE start_time >= $1 AND end_time <= $2`)).
+			WithArgs(from, to).
+			WillReturnResult(sqlmock.NewResult(0, 4))
+
+		rec := httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/api/availability?from=%d&to=%d", from.Unix(), to.Unix()), nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"status":200,"response":{"deleted":4}}`, rec.Body.String())
+		require.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("both bounds are required", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupUsersAPI(t)
+
+		for _, query := range []string{
+			"",
+			fmt.Sprintf("?from=%d", from.Unix()),
+			fmt.Sprintf("?to=%d", to.Unix()),
+			fmt.Sprintf("?from=%d&to=%d", to.Unix(), from.Unix()),
+		} {
+			rec := httptest.NewRecorder()
+			a.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/api/availability"+query, nil))
+			assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+		}
+		require.NoError(t, dbMock.ExpectationsWereMet())
+	})
+}
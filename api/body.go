@@ -0,0 +1,57 @@
+package api
+
+import (
+	"errors"
+	"events-system/validation"
+	"net/http"
+)
+
+const defaultMaxBodyBytes = 1 << 20
+
+// WithMaxBodyBytes overrides the maximum request body size, non-positive values keep the 1MB default.
+func WithMaxBodyBytes(n int64) Option {
+	return func(a *API) {
+		if n > 0 {
+			a.maxBodyBytes = n
+		}
+	}
+}
+
+// limitBody caps how much of a request body handlers can read, so that a huge payload cannot
+// exhaust memory. Reading past the cap fails with *http.MaxBytesError, see invalidBody.
+func (a *API) limitBody(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, a.maxBodyBytes)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// invalidBody answers a request whose body failed to decode: 413 when it exceeded the size cap,
+// 400 otherwise.
+func (a *API) invalidBody(w http.ResponseWriter, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		a.Response(w, http.StatusRequestEntityTooLarge, "request body too large")
+		return
+	}
+	a.Response(w, http.StatusBadRequest, "invalid request body")
+}
+
+// validationErrorResponse lists every invalid field of a rejected body, so that a form can
+// highlight all of them rather than only the first.
+type validationErrorResponse struct {
+	Error  string                  `json:"error"`
+	Errors []validation.FieldError `json:"errors"`
+}
+
+// invalidPayload answers 422 for a body that decoded but failed validation, keeping 400 for bodies
+// that do not decode at all, see invalidBody. Failures reported per field get a
+// validationErrorResponse, any other error is answered as a plain message.
+func (a *API) invalidPayload(w http.ResponseWriter, err error) {
+	var fields validation.Errors
+	if errors.As(err, &fields) {
+		a.Response(w, http.StatusUnprocessableEntity, validationErrorResponse{Error: err.Error(), Errors: fields.FieldErrors()})
+		return
+	}
+	a.Response(w, http.StatusUnprocessableEntity, err.Error())
+}
@@ -0,0 +1,20 @@
+package api
+
+import "time"
+
+// Clock tells the API the time. Every handler reads it per request, and hands it on to the
+// accessors that stamp or filter by time, so that a test or a replay can run at a pinned time.
+type Clock interface {
+	Now() time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+// WithClock makes the API read the time from clock instead of the system clock.
+func WithClock(clock Clock) Option {
+	return func(a *API) {
+		a.clock = clock
+	}
+}
@@ -0,0 +1,129 @@
+package api_test
+
+import (
+	"encoding/json"
+	"events-system/api"
+	"events-system/event"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pinnedClock is a clock that only moves when told to.
+type pinnedClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *pinnedClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *pinnedClock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = now
+}
+
+func TestClock(t *testing.T) {
+	t.Parallel()
+
+	// Every slot below is in 2020, which the tests only treat as the future by pinning the clock.
+	pinned := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	tomorrow := pinned.Add(24 * time.Hour)
+
+	setup := func(t *testing.T, clock api.Clock) (*api.API, sqlmock.Sqlmock) {
+		db, dbMock, err := sqlmock.New()
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = db.Close() })
+
+		a := api.NewAPI(db, api.WithClock(clock))
+		a.RegisterRoutes()
+		return a, dbMock
+	}
+
+	t.Run("eligible events are found at the pinned time", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setup(t, &pinnedClock{now: pinned})
+
+		userID := uuid.New()
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+			WithArgs(userID).
+			WillReturnRows(sqlmock.NewRows(userColumns).AddRow(userID, "Alice", "alice@example.com", userCreatedAt, userCreatedAt))
+		dbMock.ExpectQuery(regexp.QuoteMeta(`CROSS JOIN LATERAL jsonb_array_elements(events.slots) AS slot(value)`)).
+			WithArgs(userID, pinned).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "id", "name", "email", "eligible_slots"}))
+
+		rec := httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/users/"+userID.String()+"/eligible-events", nil))
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("tomorrow's slots are upcoming until the clock moves past them", func(t *testing.T) {
+		t.Parallel()
+		clock := &pinnedClock{now: pinned}
+		a, dbMock := setup(t, clock)
+
+		eventID, organizerID := uuid.New(), uuid.New()
+		slotsJSON, _ := event.SlotsColumn([]event.Slot{{StartTime: tomorrow, EndTime: tomorrow.Add(time.Hour)}}).Value()
+		for range 2 {
+			dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1`)).
+				WithArgs(eventID).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}).
+					AddRow(eventID, "Standup", 1, organizerID, slotsJSON, "UTC", pinned, "public"))
+		}
+
+		upcoming := func() []any {
+			rec := httptest.NewRecorder()
+			a.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/events/"+eventID.String()+"?only_future=true&fields=slots", nil))
+			require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+			var res api.Response
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+			return res.Response.(map[string]any)["slots"].([]any)
+		}
+
+		assert.Len(t, upcoming(), 1)
+		// The clock is read per request, not once when the API is built.
+		clock.Set(tomorrow.Add(2 * time.Hour))
+		assert.Empty(t, upcoming())
+		require.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("created events are stamped and warned at the pinned time", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setup(t, &pinnedClock{now: pinned})
+
+		organizerID := uuid.New()
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+			WithArgs(organizerID).
+			WillReturnRows(sqlmock.NewRows(userColumns).AddRow(organizerID, "Organizer", "organizer@example.com", userCreatedAt, userCreatedAt))
+		dbMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO events`)).
+			WithArgs(sqlmock.AnyArg(), "Standup", 1, organizerID, sqlmock.AnyArg(), "UTC", pinned, "public").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		body := fmt.Sprintf(`{"title":"Standup","duration_hours":1,"organizer_id":%q,"slots":[{"start_time":%d,"end_time":%d}]}`,
+			organizerID, tomorrow.Unix(), tomorrow.Add(time.Hour).Unix())
+		rec := httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, jsonRequest(http.MethodPost, "/api/events", strings.NewReader(body)))
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+		var res api.Response
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+		assert.NotContains(t, res.Response.(map[string]any), "warnings")
+	})
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"math"
+	"net"
+	"net/smtp"
+	"time"
+)
+
+// Config is the API's part of the server configuration, as loaded at startup. Zero values keep
+// the defaults of the matching options.
+type Config struct {
+	CORSAllowedOrigins []string
+	CORSAllowedMethods []string
+	CORSAllowedHeaders []string
+
+	// WebhookURL, when set, receives event.created and event.updated notifications.
+	WebhookURL string
+
+	// SMTPAddr, when set, is the host:port the notify endpoint sends its emails through, from
+	// SMTPFrom. SMTPUsername and SMTPPassword are only used when SMTPUsername is set.
+	SMTPAddr     string
+	SMTPFrom     string
+	SMTPUsername string
+	SMTPPassword string
+
+	// RateLimitRPS, when positive, rate limits each client IP. RateLimitBurst defaults to the
+	// rounded-up rate.
+	RateLimitRPS   float64
+	RateLimitBurst int
+
+	MaxEventSlots    int
+	MaxDurationHours int
+	SlotGranularity  time.Duration
+	MaxBodyBytes     int64
+
+	PossibleSlotLock bool
+}
+
+// WithConfig applies every setting of cfg through the matching option.
+func WithConfig(cfg Config) Option {
+	return func(a *API) {
+		opts := []Option{
+			WithCORS(cfg.CORSAllowedOrigins, cfg.CORSAllowedMethods, cfg.CORSAllowedHeaders),
+			WithEventLimits(cfg.MaxEventSlots, cfg.MaxDurationHours),
+			WithSlotGranularity(cfg.SlotGranularity),
+			WithMaxBodyBytes(cfg.MaxBodyBytes),
+		}
+		if cfg.WebhookURL != "" {
+			opts = append(opts, WithNotifier(NewWebhookNotifier(cfg.WebhookURL)))
+		}
+		if cfg.SMTPAddr != "" {
+			var auth smtp.Auth
+			if cfg.SMTPUsername != "" {
+				// The address was checked when the config was loaded.
+				host, _, _ := net.SplitHostPort(cfg.SMTPAddr)
+				auth = smtp.PlainAuth("", cfg.SMTPUsername, cfg.SMTPPassword, host)
+			}
+			opts = append(opts, WithMailer(NewSMTPMailer(cfg.SMTPAddr, cfg.SMTPFrom, auth)))
+		}
+		if cfg.RateLimitRPS > 0 {
+			burst := cfg.RateLimitBurst
+			if burst <= 0 {
+				burst = int(math.Ceil(cfg.RateLimitRPS))
+			}
+			opts = append(opts, WithRateLimiter(NewTokenBucketLimiter(cfg.RateLimitRPS, burst, nil)))
+		}
+		if cfg.PossibleSlotLock {
+			opts = append(opts, WithPossibleSlotLock())
+		}
+		for _, opt := range opts {
+			opt(a)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// bareWriter marks a response whose payload Response writes as is, without the {status, response}
+// envelope. The HTTP status code still carries the status.
+type bareWriter struct {
+	http.ResponseWriter
+}
+
+// negotiateEnvelope lets GET requests ask for the bare payload with ?envelope=false. The envelope stays
+// the default, and other methods always get it.
+func (a *API) negotiateEnvelope(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := r.URL.Query().Get("envelope")
+		if r.Method != http.MethodGet || v == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		enveloped, err := strconv.ParseBool(v)
+		if err != nil {
+			a.Response(w, http.StatusBadRequest, "invalid envelope")
+			return
+		}
+		if !enveloped {
+			w = bareWriter{w}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
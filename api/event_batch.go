@@ -0,0 +1,84 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"events-system/event"
+	"events-system/user"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// maxBatchEvents bounds the events of one batch possible-slot request.
+const maxBatchEvents = 50
+
+type batchPossibleSlotRequest struct {
+	EventIDs []string `json:"event_ids"`
+}
+
+// batchPossibleSlotResult is one event's possible slot, Slot is null when no slot suits anyone.
+type batchPossibleSlotResult struct {
+	EventID         uuid.UUID   `json:"event_id"`
+	Slot            *event.Slot `json:"slot"`
+	Users           []user.User `json:"users"`
+	NotWorkingUsers []user.User `json:"not_working_users"`
+}
+
+type batchPossibleSlotResponse struct {
+	Results []batchPossibleSlotResult `json:"results"`
+}
+
+// batchPossibleSlot answers the possible slot of several events in one call, in the order asked,
+// for planners scheduling a series of meetings. Users and their availability are looked up once
+// for all the events.
+func (a *API) batchPossibleSlot(w http.ResponseWriter, r *http.Request) {
+	var req batchPossibleSlotRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		a.invalidBody(w, err)
+		return
+	}
+	if len(req.EventIDs) == 0 {
+		a.invalidPayload(w, errors.New("at least one event ID is required"))
+		return
+	}
+	if len(req.EventIDs) > maxBatchEvents {
+		a.invalidPayload(w, fmt.Errorf("at most %d event IDs are allowed", maxBatchEvents))
+		return
+	}
+	ids := make([]uuid.UUID, len(req.EventIDs))
+	for i, raw := range req.EventIDs {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			a.Response(w, http.StatusBadRequest, fmt.Sprintf("invalid event ID %q", raw))
+			return
+		}
+		ids[i] = id
+	}
+
+	eventAccessor, ok := a.possibleSlotAccessor(w, r)
+	if !ok {
+		return
+	}
+	possible, err := eventAccessor.GetPossibleEventSlots(r.Context(), ids)
+	if errors.Is(err, event.ErrNotFound) {
+		a.Response(w, http.StatusNotFound, "event not found")
+		return
+	}
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+
+	res := batchPossibleSlotResponse{Results: make([]batchPossibleSlotResult, len(ids))}
+	for i, p := range possible {
+		res.Results[i] = batchPossibleSlotResult{EventID: ids[i], Users: []user.User{}, NotWorkingUsers: []user.User{}}
+		if p != nil {
+			res.Results[i].Slot = &p.Slot
+			res.Results[i].Users = p.Users
+			res.Results[i].NotWorkingUsers = p.NotWorkingUsers
+		}
+	}
+	a.Response(w, http.StatusOK, res)
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"errors"
+	"events-system/event"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// notifyFailure is a recipient the notification could not be sent to.
+type notifyFailure struct {
+	UserID uuid.UUID `json:"user_id"`
+	Email  string    `json:"email"`
+	Error  string    `json:"error"`
+}
+
+type notifyResponse struct {
+	Slot       event.Slot      `json:"slot"`
+	Recipients int             `json:"recipients"`
+	Failed     []notifyFailure `json:"failed"`
+}
+
+// notifyEvent emails the users available for the event's best slot, as picked by possible-slot.
+// Nothing is recorded, so notifying twice emails everyone twice. A failed delivery does not stop
+// the others and is reported per recipient.
+func (a *API) notifyEvent(w http.ResponseWriter, r *http.Request) {
+	eventID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		a.Response(w, http.StatusBadRequest, "invalid event ID")
+		return
+	}
+
+	eventAccessor := a.eventAccessor(a.userAccessor())
+	evt, err := eventAccessor.GetEvent(r.Context(), eventID)
+	if errors.Is(err, event.ErrNotFound) {
+		a.Response(w, http.StatusNotFound, "event not found")
+		return
+	}
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+
+	possible, err := eventAccessor.GetPossibleEventSlot(r.Context(), eventID)
+	if errors.Is(err, event.ErrNotFound) {
+		a.Response(w, http.StatusNotFound, "event not found")
+		return
+	}
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+	if possible == nil || len(possible.Users) == 0 {
+		a.Response(w, http.StatusNotFound, "no possible event slot found")
+		return
+	}
+
+	subject := "Scheduled: " + evt.Title
+	body := fmt.Sprintf("%s is scheduled from %s to %s.\n", evt.Title,
+		possible.Slot.StartTime.In(evt.Location()).Format(time.RFC1123),
+		possible.Slot.EndTime.In(evt.Location()).Format(time.RFC1123))
+
+	res := notifyResponse{Slot: possible.Slot, Recipients: len(possible.Users), Failed: []notifyFailure{}}
+	for _, u := range possible.Users {
+		if err := a.mailer.Send(r.Context(), u.Email, subject, body); err != nil {
+			res.Failed = append(res.Failed, notifyFailure{UserID: u.ID, Email: u.Email, Error: err.Error()})
+		}
+	}
+	a.Response(w, http.StatusOK, res)
+}
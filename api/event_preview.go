@@ -0,0 +1,97 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"events-system/event"
+	"events-system/user"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// previewPossibleSlotRequest is the part of an event a possible-slot search needs.
+type previewPossibleSlotRequest struct {
+	DurationHours int          `json:"duration_hours"`
+	Slots         []event.Slot `json:"slots"`
+	// OrganizerID, when set, tries slots clashing with the organizer's events last, as for a stored event.
+	OrganizerID string `json:"organizer_id,omitempty"`
+	// UserIDs restricts the search to the given users, everyone when empty.
+	UserIDs []string `json:"user_ids,omitempty"`
+}
+
+// previewPossibleSlot answers which of the proposed slots would win possible-slot, before the
+// event is created. Nothing is stored.
+func (a *API) previewPossibleSlot(w http.ResponseWriter, r *http.Request) {
+	var req previewPossibleSlotRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		a.invalidBody(w, err)
+		return
+	}
+
+	eventAccessor, ok := a.possibleSlotAccessor(w, r)
+	if !ok {
+		return
+	}
+
+	if len(req.Slots) == 0 {
+		a.invalidPayload(w, errors.New("at least one slot is required"))
+		return
+	}
+	if err := a.eventLimits.check(createEventRequest{DurationHours: req.DurationHours, Slots: req.Slots}); err != nil {
+		a.invalidPayload(w, err)
+		return
+	}
+	for i, s := range req.Slots {
+		if err := validateSlotBounds(s.StartTime, s.EndTime); err != nil {
+			a.invalidPayload(w, fmt.Errorf("slot %d: %w", i, err))
+			return
+		}
+	}
+	slots, err := a.roundSlots(req.Slots)
+	if err != nil {
+		a.invalidPayload(w, err)
+		return
+	}
+	evt := event.Event{DurationHours: req.DurationHours, Slots: slots}
+	if err := evt.ValidateSchedule(); err != nil {
+		a.invalidPayload(w, err)
+		return
+	}
+
+	if req.OrganizerID != "" {
+		organizerID, err := uuid.Parse(req.OrganizerID)
+		if err != nil {
+			a.invalidPayload(w, errors.New("invalid organizer ID"))
+			return
+		}
+		if _, ok := a.lookupOrganizer(w, r, a.userAccessor(), organizerID); !ok {
+			return
+		}
+		evt.UserID = organizerID
+	}
+
+	var candidates []user.User
+	if len(req.UserIDs) > 0 {
+		if candidates, ok = a.lookupCandidates(w, r, req.UserIDs); !ok {
+			return
+		}
+	}
+
+	possibleEventSlot, err := eventAccessor.FindPossibleSlot(r.Context(), &evt, candidates)
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+	if possibleEventSlot == nil {
+		a.Response(w, http.StatusNotFound, "no possible event slot found")
+		return
+	}
+
+	a.Response(w, http.StatusOK, map[string]any{
+		"slot":              possibleEventSlot.Slot,
+		"users":             possibleEventSlot.Users,
+		"not_working_users": possibleEventSlot.NotWorkingUsers,
+	})
+}
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"events-system/event"
+	"events-system/validation"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// eventSlotsRequest replaces the candidate slots of an event without resending the rest of it.
+type eventSlotsRequest struct {
+	Slots []event.Slot `json:"slots"`
+}
+
+// getEventSlots answers the candidate slots of an event, rendered in its timezone like getEvent.
+func (a *API) getEventSlots(w http.ResponseWriter, r *http.Request) {
+	eventID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		a.Response(w, http.StatusBadRequest, "invalid event ID")
+		return
+	}
+
+	evt, err := a.eventAccessor(a.userAccessor()).GetEvent(r.Context(), eventID)
+	if errors.Is(err, event.ErrNotFound) {
+		a.Response(w, http.StatusNotFound, "event not found")
+		return
+	}
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+
+	a.Response(w, http.StatusOK, map[string]any{"slots": localSlotsResponse(evt.Slots, evt.Location())})
+}
+
+// putEventSlots replaces the candidate slots of an event, validated as on create.
+func (a *API) putEventSlots(w http.ResponseWriter, r *http.Request) {
+	eventID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		a.Response(w, http.StatusBadRequest, "invalid event ID")
+		return
+	}
+
+	var req eventSlotsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		a.invalidBody(w, err)
+		return
+	}
+	if len(req.Slots) > a.eventLimits.maxSlots {
+		a.invalidPayload(w, fmt.Errorf("at most %d slots are allowed", a.eventLimits.maxSlots))
+		return
+	}
+	var errs validation.Errors
+	for i, s := range req.Slots {
+		if err := validateSlotBounds(s.StartTime, s.EndTime); err != nil {
+			a.invalidPayload(w, fmt.Errorf("slot %d: %w", i, err))
+			return
+		}
+		var slotErrs validation.Errors
+		if errors.As(s.Validate(), &slotErrs) {
+			errs.Nest(fmt.Sprintf("slots[%d]", i), fmt.Sprintf("slot %d", i), slotErrs)
+		}
+	}
+	if err := errs.Err(); err != nil {
+		a.invalidPayload(w, err)
+		return
+	}
+	slots, err := a.roundSlots(req.Slots)
+	if err != nil {
+		a.invalidPayload(w, err)
+		return
+	}
+
+	a.setEventSlots(w, r, eventID, slots)
+}
+
+// deleteEventSlots clears the candidate slots of an event, leaving the event itself in place.
+func (a *API) deleteEventSlots(w http.ResponseWriter, r *http.Request) {
+	eventID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		a.Response(w, http.StatusBadRequest, "invalid event ID")
+		return
+	}
+
+	a.setEventSlots(w, r, eventID, nil)
+}
+
+func (a *API) setEventSlots(w http.ResponseWriter, r *http.Request, eventID uuid.UUID, slots []event.Slot) {
+	evt, err := a.eventAccessor(a.userAccessor()).SetEventSlots(r.Context(), eventID, slots)
+	if errors.Is(err, event.ErrNotFound) {
+		a.Response(w, http.StatusNotFound, "event not found")
+		return
+	}
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+	a.notifier.EventUpdated(r.Context(), *evt)
+
+	a.Response(w, http.StatusOK, map[string]any{"slots": localSlotsResponse(evt.Slots, evt.Location())})
+}
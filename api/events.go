@@ -1,97 +1,489 @@
 package api
 
 import (
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"events-system/event"
 	"events-system/user"
 	"fmt"
+	"io"
+	"mime"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 )
 
-type getEventsResponse struct {
-	Events []event.Event `json:"events"`
+// getEvents lists events, newest first, with ?limit= and ?offset= paging.
+func (a *API) getEvents(w http.ResponseWriter, r *http.Request) {
+	limit, offset, err := parsePagination(r)
+	if err != nil {
+		a.Response(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	eventAccessor, ok := a.listingEventAccessor(w, r)
+	if !ok {
+		return
+	}
+	// ?after= switches to keyset paging, an empty value asks for the first page.
+	if r.URL.Query().Has("after") {
+		if r.URL.Query().Has("offset") {
+			a.Response(w, http.StatusBadRequest, "after cannot be combined with offset")
+			return
+		}
+		a.getEventsAfter(w, r, eventAccessor, limit)
+		return
+	}
+
+	events, err := eventAccessor.GetEventsWithOrganizers(r.Context(), limit, offset)
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+	total, err := eventAccessor.CountEvents(r.Context())
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+
+	res := PagedResponse[map[string]any]{Items: make([]map[string]any, 0, len(events)), Limit: limit, Offset: offset, Total: total}
+	for i := range events {
+		res.Items = append(res.Items, eventResponse(&events[i].Event, &events[i].Organizer))
+	}
+	setPageLinks(w, r, limit, offset, total)
+	a.Response(w, http.StatusOK, res)
 }
 
-func (a *API) getEvents(w http.ResponseWriter, r *http.Request) {
-	eventAccessor := event.NewAccessor(a.db, user.NewAccessor(a.db))
-	events, err := eventAccessor.GetEvents(r.Context())
+// getEventsAfter answers the events following the ?after= cursor, see GetEventsWithOrganizersAfter.
+func (a *API) getEventsAfter(w http.ResponseWriter, r *http.Request, eventAccessor *event.Accessor, limit int) {
+	var after *event.Cursor
+	if v := r.URL.Query().Get("after"); v != "" {
+		c, err := decodeEventCursor(v)
+		if err != nil {
+			a.Response(w, http.StatusBadRequest, "invalid cursor")
+			return
+		}
+		after = &c
+	}
+
+	// One extra event tells whether there is a next page without a count query.
+	events, err := eventAccessor.GetEventsWithOrganizersAfter(r.Context(), after, limit+1)
 	if err != nil {
-		a.Response(w, http.StatusInternalServerError, err.Error())
+		a.internalError(w, r, err)
 		return
 	}
-	response := getEventsResponse{
-		Events: events,
+
+	res := CursorPage[map[string]any]{Items: make([]map[string]any, 0, min(len(events), limit)), Limit: limit}
+	if len(events) > limit {
+		events = events[:limit]
+		last := events[limit-1]
+		res.NextCursor = encodeEventCursor(event.Cursor{CreatedAt: last.CreatedAt, ID: last.ID})
+		w.Header().Set("Link", pageLink(r, "next", map[string]string{"limit": strconv.Itoa(limit), "after": res.NextCursor}))
 	}
-	a.Response(w, http.StatusOK, response)
+	for i := range events {
+		res.Items = append(res.Items, eventResponse(&events[i].Event, &events[i].Organizer))
+	}
+	a.Response(w, http.StatusOK, res)
 }
 
-type slot struct {
-	StartTime int64 `json:"start_time"`
-	EndTime   int64 `json:"end_time"`
+// listingEventAccessor returns the accessor the event listings read through: public events only,
+// unless ?organizer_id= scopes the listing to that organizer's events, private ones included. It
+// reports whether to go on.
+func (a *API) listingEventAccessor(w http.ResponseWriter, r *http.Request) (*event.Accessor, bool) {
+	eventAccessor := a.eventAccessor(a.userAccessor())
+	organizer := r.URL.Query().Get("organizer_id")
+	if organizer == "" {
+		return eventAccessor, true
+	}
+	organizerID, err := uuid.Parse(organizer)
+	if err != nil {
+		a.Response(w, http.StatusBadRequest, "invalid organizer_id")
+		return nil, false
+	}
+	return eventAccessor.WithOrganizer(organizerID), true
 }
 
-// createEventRequest is the API DTO that accepts int64 epoch timestamps
-type createEventRequest struct {
-	Title         string `json:"title"`
-	DurationHours int    `json:"duration_hours"`
-	OrganizerID   string `json:"organizer_id"`
-	Slots         []slot `json:"slots"`
+// encodeEventCursor makes an opaque ?after= value out of an event key: the URL-safe base64 of
+// "<created_at unix nanoseconds>,<id>".
+func encodeEventCursor(c event.Cursor) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(c.CreatedAt.UnixNano(), 10) + "," + c.ID.String()))
 }
 
-func (a *API) createEvent(w http.ResponseWriter, r *http.Request) {
-	var req createEventRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		a.Response(w, http.StatusBadRequest, "invalid request body")
+func decodeEventCursor(s string) (event.Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return event.Cursor{}, err
+	}
+	nanos, id, ok := strings.Cut(string(b), ",")
+	if !ok {
+		return event.Cursor{}, errors.New("missing separator")
+	}
+	n, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return event.Cursor{}, err
+	}
+	eventID, err := uuid.Parse(id)
+	if err != nil {
+		return event.Cursor{}, err
+	}
+	return event.Cursor{CreatedAt: time.Unix(0, n).UTC(), ID: eventID}, nil
+}
+
+// searchEvents matches ?q= against event titles, case-insensitively, with ?limit= and ?offset= paging.
+func (a *API) searchEvents(w http.ResponseWriter, r *http.Request) {
+	q := strings.TrimSpace(r.URL.Query().Get("q"))
+	if q == "" {
+		a.Response(w, http.StatusBadRequest, "q is required")
+		return
+	}
+	limit, offset, err := parsePagination(r)
+	if err != nil {
+		a.Response(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	organizerID, err := uuid.Parse(req.OrganizerID)
+	eventAccessor, ok := a.listingEventAccessor(w, r)
+	if !ok {
+		return
+	}
+	events, err := eventAccessor.SearchEvents(r.Context(), q, limit, offset)
 	if err != nil {
-		a.Response(w, http.StatusBadRequest, "invalid organizer ID")
+		a.internalError(w, r, err)
+		return
+	}
+	total, err := eventAccessor.CountSearchEvents(r.Context(), q)
+	if err != nil {
+		a.internalError(w, r, err)
 		return
 	}
 
-	// Convert int64 epoch timestamps to time.Time
-	slots := make([]event.Slot, len(req.Slots))
+	res := PagedResponse[map[string]any]{Items: make([]map[string]any, 0, len(events)), Limit: limit, Offset: offset, Total: total}
+	for i := range events {
+		res.Items = append(res.Items, eventResponse(&events[i], nil))
+	}
+	setPageLinks(w, r, limit, offset, total)
+	a.Response(w, http.StatusOK, res)
+}
+
+// maxSlotTimestamp (2100-01-01T00:00:00Z) bounds slot timestamps, anything later is a client bug.
+const maxSlotTimestamp = 4102444800
+
+// validateSlotBounds rejects timestamps that would turn into nonsensical slots.
+func validateSlotBounds(start, end time.Time) error {
+	if start.Unix() <= 0 || end.Unix() <= 0 {
+		return errors.New("timestamps must be positive unix seconds")
+	}
+	if start.Unix() > maxSlotTimestamp || end.Unix() > maxSlotTimestamp {
+		return errors.New("timestamps must be before 2100-01-01")
+	}
+	return nil
+}
+
+const (
+	defaultMaxEventSlots    = 100
+	defaultMaxDurationHours = 24
+)
+
+// eventLimits bound the size of events clients may create or update.
+type eventLimits struct {
+	maxSlots         int
+	maxDurationHours int
+}
+
+// WithSlotGranularity rounds the slots of created and updated events to multiples of d, see
+// event.Slot.Round. Slots are stored as sent by default.
+func WithSlotGranularity(d time.Duration) Option {
+	return func(a *API) {
+		if d > 0 {
+			a.slotGranularity = d
+		}
+	}
+}
+
+// roundSlots applies the slot granularity, rejecting slots too short to contain a whole multiple of it.
+func (a *API) roundSlots(slots []event.Slot) ([]event.Slot, error) {
+	if a.slotGranularity <= 0 {
+		return slots, nil
+	}
+	rounded := make([]event.Slot, len(slots))
+	for i, s := range slots {
+		rounded[i] = s.Round(a.slotGranularity)
+		if !rounded[i].EndTime.After(rounded[i].StartTime) {
+			return nil, fmt.Errorf("slot %d: shorter than the %s slot granularity once rounded", i, a.slotGranularity)
+		}
+	}
+	return rounded, nil
+}
+
+// WithEventLimits overrides the maximum number of slots per event and the maximum duration_hours.
+// Non-positive values keep the defaults.
+func WithEventLimits(maxSlots, maxDurationHours int) Option {
+	return func(a *API) {
+		if maxSlots > 0 {
+			a.eventLimits.maxSlots = maxSlots
+		}
+		if maxDurationHours > 0 {
+			a.eventLimits.maxDurationHours = maxDurationHours
+		}
+	}
+}
+
+func (l eventLimits) check(req createEventRequest) error {
+	if len(req.Slots) > l.maxSlots {
+		return fmt.Errorf("at most %d slots are allowed", l.maxSlots)
+	}
+	if req.DurationHours > l.maxDurationHours {
+		return fmt.Errorf("duration hours must be at most %d", l.maxDurationHours)
+	}
+	return nil
+}
+
+// localSlotResponse is an event.Slot that also renders the boundaries as RFC 3339 in the
+// event's timezone, so clients can show the organizer's wall-clock times across DST changes.
+// All-day slots have no wall-clock times and render their first and last day instead.
+type localSlotResponse struct {
+	StartTime  int64  `json:"start_time"`
+	EndTime    int64  `json:"end_time"`
+	Label      string `json:"label,omitempty"`
+	AllDay     bool   `json:"all_day,omitempty"`
+	StartLocal string `json:"start_local,omitempty"`
+	EndLocal   string `json:"end_local,omitempty"`
+	StartDate  string `json:"start_date,omitempty"`
+	EndDate    string `json:"end_date,omitempty"` // inclusive
+}
+
+func localSlotsResponse(slots []event.Slot, loc *time.Location) []localSlotResponse {
+	res := make([]localSlotResponse, len(slots))
+	for i, s := range slots {
+		res[i] = localSlotResponse{
+			StartTime: s.StartTime.Unix(),
+			EndTime:   s.EndTime.Unix(),
+			Label:     s.Label,
+			AllDay:    s.AllDay,
+		}
+		if s.AllDay {
+			res[i].StartDate = s.StartTime.UTC().Format(time.DateOnly)
+			res[i].EndDate = s.EndTime.UTC().AddDate(0, 0, -1).Format(time.DateOnly)
+			continue
+		}
+		res[i].StartLocal = s.StartTime.In(loc).Format(time.RFC3339)
+		res[i].EndLocal = s.EndTime.In(loc).Format(time.RFC3339)
+	}
+	return res
+}
+
+// createEventRequest is the API DTO that accepts int64 epoch timestamps
+type createEventRequest struct {
+	ID            string       `json:"id,omitempty"`
+	Title         string       `json:"title"`
+	DurationHours int          `json:"duration_hours"`
+	OrganizerID   string       `json:"organizer_id"`
+	Slots         []event.Slot `json:"slots"`
+	Timezone      string       `json:"timezone,omitempty"` // IANA name, defaults to UTC
+	// Visibility is public or private, defaulting to public. It is only read on create.
+	Visibility string `json:"visibility,omitempty"`
+
+	// RequireOrganizerAvailable makes a create fail with 422 unless the organizer is available for one of the slots.
+	RequireOrganizerAvailable bool `json:"require_organizer_available,omitempty"`
+}
+
+// buildEventFromRequest converts the request DTO into a validated event with the given ID.
+// The returned error is safe to show to the client.
+func (a *API) buildEventFromRequest(req createEventRequest, id uuid.UUID) (*event.Event, error) {
+	organizerID, err := uuid.Parse(req.OrganizerID)
+	if err != nil {
+		return nil, errors.New("invalid organizer ID")
+	}
+	if err := a.eventLimits.check(req); err != nil {
+		return nil, err
+	}
+
 	for i, s := range req.Slots {
-		slots[i] = event.Slot{
-			StartTime: time.Unix(s.StartTime, 0).UTC(),
-			EndTime:   time.Unix(s.EndTime, 0).UTC(),
+		if err := validateSlotBounds(s.StartTime, s.EndTime); err != nil {
+			return nil, fmt.Errorf("slot %d: %w", i, err)
 		}
 	}
+	slots, err := a.roundSlots(req.Slots)
+	if err != nil {
+		return nil, err
+	}
 
-	payload := event.Event{
+	timezone := req.Timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+
+	evt := event.Event{
+		ID:            id,
 		Title:         req.Title,
 		DurationHours: req.DurationHours,
 		UserID:        organizerID,
 		Slots:         slots,
+		Timezone:      timezone,
+		Visibility:    event.Visibility(req.Visibility),
 	}
-
-	if err := payload.Validate(); err != nil {
-		a.Response(w, http.StatusBadRequest, fmt.Errorf("validate: %w", err))
-		return
+	if err := evt.Validate(); err != nil {
+		return nil, fmt.Errorf("validate: %w", err)
 	}
+	return &evt, nil
+}
 
-	eventAccessor := event.NewAccessor(a.db, user.NewAccessor(a.db))
-	evt, err := eventAccessor.CreateEvent(r.Context(), payload, a.now)
+// lookupOrganizer fetches the organizer named by a create or update, answering 422 when no such
+// user exists so that an event never points at a missing organizer. It reports whether to go on.
+func (a *API) lookupOrganizer(w http.ResponseWriter, r *http.Request, users *user.Accessor, id uuid.UUID) (*user.User, bool) {
+	organizer, err := users.GetUser(r.Context(), id)
+	if errors.Is(err, user.ErrNotFound) {
+		a.Response(w, http.StatusUnprocessableEntity, "organizer not found")
+		return nil, false
+	}
 	if err != nil {
-		a.Response(w, http.StatusInternalServerError, err.Error())
-		return
+		a.internalError(w, r, err)
+		return nil, false
 	}
+	return organizer, true
+}
 
-	response := map[string]any{
+// eventResponse is the single representation of an event used by every event endpoint.
+func eventResponse(evt *event.Event, organizer *user.User) map[string]any {
+	res := map[string]any{
 		"id":             evt.ID.String(),
 		"title":          evt.Title,
 		"duration_hours": evt.DurationHours,
 		"organizer_id":   evt.UserID.String(),
-		"slots":          evt.Slots,
+		"organizer":      organizer,
+		"timezone":       evt.Location().String(),
+		"slots":          localSlotsResponse(evt.Slots, evt.Location()),
 		"created_at":     evt.CreatedAt.Unix(),
 	}
-	a.Response(w, http.StatusCreated, response)
+	// Public is the default and left out, as deleted_at is for events that are not deleted.
+	if evt.Visibility == event.VisibilityPrivate {
+		res["visibility"] = evt.Visibility
+	}
+	if evt.DeletedAt != nil {
+		res["deleted_at"] = evt.DeletedAt.Unix()
+	}
+	return res
+}
+
+// eventFields are the keys of eventResponse that ?fields= may select.
+var eventFields = map[string]bool{
+	"id": true, "title": true, "duration_hours": true, "organizer_id": true, "organizer": true,
+	"timezone": true, "slots": true, "visibility": true, "created_at": true, "deleted_at": true,
+}
+
+// parseEventFields reads the comma-separated ?fields= list. A nil result means every field,
+// otherwise id is always included.
+func parseEventFields(r *http.Request) (map[string]bool, error) {
+	raw := r.URL.Query().Get("fields")
+	if raw == "" {
+		return nil, nil
+	}
+	fields := map[string]bool{"id": true}
+	for _, f := range strings.Split(raw, ",") {
+		f = strings.TrimSpace(f)
+		if !eventFields[f] {
+			return nil, fmt.Errorf("unknown field %q", f)
+		}
+		fields[f] = true
+	}
+	return fields, nil
+}
+
+// selectFields drops the keys of res that are not in fields, nil fields keeps them all.
+func selectFields(res map[string]any, fields map[string]bool) map[string]any {
+	if fields == nil {
+		return res
+	}
+	for k := range res {
+		if !fields[k] {
+			delete(res, k)
+		}
+	}
+	return res
+}
+
+func (a *API) createEvent(w http.ResponseWriter, r *http.Request) {
+	var req createEventRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		a.invalidBody(w, err)
+		return
+	}
+
+	var eventID uuid.UUID
+	if req.ID != "" {
+		var err error
+		eventID, err = uuid.Parse(req.ID)
+		if err != nil {
+			a.Response(w, http.StatusBadRequest, "invalid event ID")
+			return
+		}
+	}
+
+	payload, err := a.buildEventFromRequest(req, eventID)
+	if err != nil {
+		a.invalidPayload(w, err)
+		return
+	}
+
+	userAccessor := a.userAccessor()
+	eventAccessor := a.eventAccessor(userAccessor)
+
+	if createIfNoneMatch(r) {
+		if payload.ID == uuid.Nil {
+			a.Response(w, http.StatusBadRequest, "id is required with If-None-Match")
+			return
+		}
+		_, err := eventAccessor.GetEvent(r.Context(), payload.ID)
+		if err == nil {
+			a.Response(w, http.StatusPreconditionFailed, "event already exists")
+			return
+		}
+		if !errors.Is(err, event.ErrNotFound) {
+			a.internalError(w, r, err)
+			return
+		}
+	}
+
+	organizer, ok := a.lookupOrganizer(w, r, userAccessor, payload.UserID)
+	if !ok {
+		return
+	}
+
+	createEvent := eventAccessor.CreateEvent
+	if req.RequireOrganizerAvailable {
+		createEvent = eventAccessor.CreateEventIfOrganizerAvailable
+	}
+	now := a.clock.Now()
+	evt, err := createEvent(r.Context(), *payload, now)
+	if errors.Is(err, event.ErrOrganizerUnavailable) {
+		a.Response(w, http.StatusUnprocessableEntity, event.ErrOrganizerUnavailable.Error())
+		return
+	}
+	// The ID was taken since the If-None-Match check, or belongs to a deleted event.
+	if errors.Is(err, event.ErrAlreadyExists) {
+		status := http.StatusConflict
+		if createIfNoneMatch(r) {
+			status = http.StatusPreconditionFailed
+		}
+		a.Response(w, status, event.ErrAlreadyExists.Error())
+		return
+	}
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+	a.notifier.EventCreated(r.Context(), *evt)
+
+	res := eventResponse(evt, organizer)
+	if warnings := evt.Warnings(now); len(warnings) > 0 {
+		res["warnings"] = warnings
+	}
+	a.created(w, "/api/events/"+evt.ID.String(), res)
 }
 
 func (a *API) getEvent(w http.ResponseWriter, r *http.Request) {
@@ -107,39 +499,63 @@ func (a *API) getEvent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	eventAccessor := event.NewAccessor(a.db, user.NewAccessor(a.db))
-	evt, err := eventAccessor.GetEvent(r.Context(), parsedID)
+	onlyFuture := false
+	if v := r.URL.Query().Get("only_future"); v != "" {
+		onlyFuture, err = strconv.ParseBool(v)
+		if err != nil {
+			a.Response(w, http.StatusBadRequest, "invalid only_future")
+			return
+		}
+	}
+
+	includeDeleted := false
+	if v := r.URL.Query().Get("include_deleted"); v != "" {
+		includeDeleted, err = strconv.ParseBool(v)
+		if err != nil {
+			a.Response(w, http.StatusBadRequest, "invalid include_deleted")
+			return
+		}
+	}
+
+	fields, err := parseEventFields(r)
 	if err != nil {
-		a.Response(w, http.StatusInternalServerError, err.Error())
+		a.Response(w, http.StatusBadRequest, err.Error())
 		return
 	}
-	if evt == nil {
+
+	eventAccessor := a.eventAccessor(a.userAccessor())
+	var evt *event.Event
+	if includeDeleted {
+		evt, err = eventAccessor.GetEventIncludingDeleted(r.Context(), parsedID)
+	} else {
+		evt, err = eventAccessor.GetEvent(r.Context(), parsedID)
+	}
+	if errors.Is(err, event.ErrNotFound) {
 		a.Response(w, http.StatusNotFound, "event not found")
 		return
 	}
-
-	// Fetch organizer user
-	userAccessor := user.NewAccessor(a.db)
-	organizer, err := userAccessor.GetUser(r.Context(), evt.UserID)
 	if err != nil {
-		a.Response(w, http.StatusInternalServerError, err.Error())
+		a.internalError(w, r, err)
 		return
 	}
-	if organizer == nil {
-		a.Response(w, http.StatusInternalServerError, "organizer not found")
-		return
+
+	// Only trims the response, stored slots are left untouched
+	if onlyFuture {
+		evt.Slots = event.FutureSlots(evt.Slots, a.clock.Now())
 	}
 
-	response := map[string]any{
-		"id":             evt.ID.String(),
-		"title":          evt.Title,
-		"duration_hours": evt.DurationHours,
-		"organizer_id":   evt.UserID.String(),
-		"organizer":      organizer,
-		"slots":          evt.Slots,
-		"created_at":     evt.CreatedAt.Unix(),
+	// Fetch organizer user, unless the client did not ask for it
+	var organizer *user.User
+	if fields == nil || fields["organizer"] {
+		organizer, err = a.userAccessor().GetUser(r.Context(), evt.UserID)
+		if err != nil {
+			// A missing organizer is a broken invariant rather than a client error.
+			a.internalError(w, r, fmt.Errorf("get organizer: %w", err))
+			return
+		}
 	}
-	a.Response(w, http.StatusOK, response)
+
+	a.Response(w, http.StatusOK, selectFields(eventResponse(evt, organizer), fields))
 }
 
 func (a *API) deleteEvent(w http.ResponseWriter, r *http.Request) {
@@ -155,21 +571,21 @@ func (a *API) deleteEvent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	eventAccessor := event.NewAccessor(a.db, user.NewAccessor(a.db))
+	eventAccessor := a.eventAccessor(a.userAccessor())
 
 	e, err := eventAccessor.GetEvent(r.Context(), parsedID)
-	if err != nil {
-		a.Response(w, http.StatusInternalServerError, err.Error())
+	if errors.Is(err, event.ErrNotFound) {
+		a.Response(w, http.StatusNotFound, "event not found")
 		return
 	}
-	if e == nil {
-		a.Response(w, http.StatusNotFound, "event not found")
+	if err != nil {
+		a.internalError(w, r, err)
 		return
 	}
 
-	err = eventAccessor.DeleteEvent(r.Context(), e.ID)
+	err = eventAccessor.DeleteEvent(r.Context(), e.ID, a.clock.Now())
 	if err != nil {
-		a.Response(w, http.StatusInternalServerError, err.Error())
+		a.internalError(w, r, err)
 		return
 	}
 	a.Response(w, http.StatusNoContent, nil)
@@ -187,66 +603,238 @@ func (a *API) updateEvent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	eventAccessor := event.NewAccessor(a.db, user.NewAccessor(a.db))
+	eventAccessor := a.eventAccessor(a.userAccessor())
 	e, err := eventAccessor.GetEvent(r.Context(), eventID)
-	if err != nil {
-		a.Response(w, http.StatusInternalServerError, err.Error())
+	if errors.Is(err, event.ErrNotFound) {
+		a.Response(w, http.StatusNotFound, "event not found")
 		return
 	}
-	if e == nil {
-		a.Response(w, http.StatusNotFound, "event not found")
+	if err != nil {
+		a.internalError(w, r, err)
 		return
 	}
 
 	var req createEventRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		a.Response(w, http.StatusBadRequest, "invalid request body")
+		a.invalidBody(w, err)
 		return
 	}
 
-	organizerID, err := uuid.Parse(req.OrganizerID)
+	// Omitting the timezone keeps the current one rather than resetting it to UTC.
+	if req.Timezone == "" {
+		req.Timezone = e.Timezone
+	}
+	payload, err := a.buildEventFromRequest(req, e.ID)
 	if err != nil {
-		a.Response(w, http.StatusBadRequest, "invalid organizer ID")
+		a.invalidPayload(w, err)
+		return
+	}
+	// The organizer itself only changes through a transfer, but a dangling ID is still rejected.
+	if _, ok := a.lookupOrganizer(w, r, a.userAccessor(), payload.UserID); !ok {
 		return
 	}
 
-	// Convert int64 epoch timestamps to time.Time
-	slots := make([]event.Slot, len(req.Slots))
-	for i, s := range req.Slots {
-		slots[i] = event.Slot{
-			StartTime: time.Unix(s.StartTime, 0).UTC(),
-			EndTime:   time.Unix(s.EndTime, 0).UTC(),
+	updatedEvent, err := eventAccessor.UpdateEvent(r.Context(), *payload, a.clock.Now())
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+	a.notifier.EventUpdated(r.Context(), *updatedEvent)
+
+	organizer, err := a.userAccessor().GetUser(r.Context(), updatedEvent.UserID)
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+
+	a.Response(w, http.StatusOK, eventResponse(updatedEvent, organizer))
+}
+
+// patchableEvent is the document JSON Patch operations on an event apply to.
+type patchableEvent struct {
+	Title         string       `json:"title"`
+	DurationHours int          `json:"duration_hours"`
+	Timezone      string       `json:"timezone"`
+	Slots         []event.Slot `json:"slots"`
+}
+
+// checkEventPatchPath accepts the top-level fields of patchableEvent, a whole slot (/slots/0 or
+// /slots/- to append) and a slot member (/slots/0/start_time, end_time, label or all_day).
+func checkEventPatchPath(path string) error {
+	tokens, err := parsePointer(path)
+	if err != nil {
+		return err
+	}
+	switch {
+	case len(tokens) == 1 && slices.Contains([]string{"title", "duration_hours", "timezone", "slots"}, tokens[0]):
+		return nil
+	case len(tokens) == 2 && tokens[0] == "slots":
+		return nil
+	case len(tokens) == 3 && tokens[0] == "slots" && slices.Contains([]string{"start_time", "end_time", "label", "all_day"}, tokens[2]):
+		return nil
+	}
+	return fmt.Errorf("unsupported path %q", path)
+}
+
+// patchEvent applies an RFC 6902 JSON Patch to the event's title, duration, timezone and slots.
+func (a *API) patchEvent(w http.ResponseWriter, r *http.Request) {
+	if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || mediaType != jsonPatchMediaType {
+		w.Header().Set("Accept-Patch", jsonPatchMediaType)
+		a.Response(w, http.StatusUnsupportedMediaType, "Content-Type must be "+jsonPatchMediaType)
+		return
+	}
+
+	eventID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		a.Response(w, http.StatusBadRequest, "invalid event ID")
+		return
+	}
+
+	var ops []patchOperation
+	if err := json.NewDecoder(r.Body).Decode(&ops); err != nil {
+		a.invalidBody(w, err)
+		return
+	}
+	for i, op := range ops {
+		if err := checkEventPatchPath(op.Path); err != nil {
+			a.Response(w, http.StatusBadRequest, fmt.Sprintf("operation %d: %v", i, err))
+			return
 		}
 	}
 
-	payload := event.Event{
-		ID:            e.ID,
-		Title:         req.Title,
-		DurationHours: req.DurationHours,
-		UserID:        organizerID,
-		Slots:         slots,
+	eventAccessor := a.eventAccessor(a.userAccessor())
+	e, err := eventAccessor.GetEvent(r.Context(), eventID)
+	if errors.Is(err, event.ErrNotFound) {
+		a.Response(w, http.StatusNotFound, "event not found")
+		return
+	}
+	if err != nil {
+		a.internalError(w, r, err)
+		return
 	}
 
-	if err := payload.Validate(); err != nil {
-		a.Response(w, http.StatusBadRequest, fmt.Errorf("validate: %w", err))
+	// Round-trip through JSON so the operations see slots in their API form, as unix seconds.
+	var doc any
+	b, err := json.Marshal(patchableEvent{Title: e.Title, DurationHours: e.DurationHours, Timezone: e.Timezone, Slots: e.Slots})
+	if err == nil {
+		err = json.Unmarshal(b, &doc)
+	}
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+	doc, err = applyJSONPatch(doc, ops)
+	if errors.Is(err, errPatchTestFailed) {
+		a.Response(w, http.StatusConflict, err.Error())
+		return
+	}
+	if err != nil {
+		a.Response(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	updatedEvent, err := eventAccessor.UpdateEvent(r.Context(), payload, a.now)
+	var patched patchableEvent
+	if b, err = json.Marshal(doc); err == nil {
+		err = json.Unmarshal(b, &patched)
+	}
+	if err != nil {
+		a.Response(w, http.StatusBadRequest, fmt.Sprintf("patched event: %v", err))
+		return
+	}
+	payload, err := a.buildEventFromRequest(createEventRequest{
+		Title:         patched.Title,
+		DurationHours: patched.DurationHours,
+		OrganizerID:   e.UserID.String(),
+		Slots:         patched.Slots,
+		Timezone:      patched.Timezone,
+	}, e.ID)
 	if err != nil {
-		a.Response(w, http.StatusInternalServerError, err.Error())
+		a.invalidPayload(w, err)
 		return
 	}
 
-	response := map[string]any{
-		"id":             updatedEvent.ID.String(),
-		"title":          updatedEvent.Title,
-		"duration_hours": updatedEvent.DurationHours,
-		"organizer_id":   updatedEvent.UserID.String(),
-		"slots":          updatedEvent.Slots,
-		"created_at":     updatedEvent.CreatedAt.Unix(),
+	updatedEvent, err := eventAccessor.UpdateEvent(r.Context(), *payload, a.clock.Now())
+	if err != nil {
+		a.internalError(w, r, err)
+		return
 	}
-	a.Response(w, http.StatusOK, response)
+	a.notifier.EventUpdated(r.Context(), *updatedEvent)
+
+	organizer, err := a.userAccessor().GetUser(r.Context(), updatedEvent.UserID)
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+
+	a.Response(w, http.StatusOK, eventResponse(updatedEvent, organizer))
+}
+
+type duplicateEventRequest struct {
+	ShiftHours int `json:"shift_hours"`
+}
+
+func (a *API) duplicateEvent(w http.ResponseWriter, r *http.Request) {
+	eventID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		a.Response(w, http.StatusBadRequest, "invalid event ID")
+		return
+	}
+
+	// The body is optional, an empty one makes a plain copy.
+	var req duplicateEventRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		a.invalidBody(w, err)
+		return
+	}
+
+	eventAccessor := a.eventAccessor(a.userAccessor())
+	source, err := eventAccessor.GetEvent(r.Context(), eventID)
+	if errors.Is(err, event.ErrNotFound) {
+		a.Response(w, http.StatusNotFound, "event not found")
+		return
+	}
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+
+	shift := time.Duration(req.ShiftHours) * time.Hour
+	slots := make([]event.Slot, 0, len(source.Slots))
+	for _, s := range source.Slots {
+		s.StartTime = s.StartTime.Add(shift)
+		s.EndTime = s.EndTime.Add(shift)
+		slots = append(slots, s)
+	}
+
+	// The shifted copy goes through the same checks as a create, e.g. an all-day slot must still
+	// start at midnight.
+	payload, err := a.buildEventFromRequest(createEventRequest{
+		Title:         source.Title,
+		DurationHours: source.DurationHours,
+		OrganizerID:   source.UserID.String(),
+		Slots:         slots,
+		Timezone:      source.Timezone,
+		Visibility:    string(source.Visibility),
+	}, uuid.Nil)
+	if err != nil {
+		a.invalidPayload(w, err)
+		return
+	}
+
+	duplicate, err := eventAccessor.CreateEvent(r.Context(), *payload, a.clock.Now())
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+	a.notifier.EventCreated(r.Context(), *duplicate)
+
+	organizer, err := a.userAccessor().GetUser(r.Context(), duplicate.UserID)
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+
+	a.created(w, "/api/events/"+duplicate.ID.String(), eventResponse(duplicate, organizer))
 }
 
 func (a *API) getPossibleEventSlot(w http.ResponseWriter, r *http.Request) {
@@ -262,10 +850,33 @@ func (a *API) getPossibleEventSlot(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	eventAccessor := event.NewAccessor(a.db, user.NewAccessor(a.db))
-	possibleEventSlot, err := eventAccessor.GetPossibleEventSlot(r.Context(), parsedID)
+	// The attendee list can be paged; the pick itself always weighs every available user.
+	limit, offset, err := parseOptionalPagination(r)
 	if err != nil {
-		a.Response(w, http.StatusInternalServerError, err.Error())
+		a.Response(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	eventAccessor, ok := a.possibleSlotAccessor(w, r)
+	if !ok {
+		return
+	}
+
+	// Restrict the search to the given users, nil means everyone.
+	var candidates []user.User
+	if raw := r.URL.Query().Get("user_ids"); raw != "" {
+		if candidates, ok = a.lookupCandidates(w, r, strings.Split(raw, ",")); !ok {
+			return
+		}
+	}
+
+	evt, possibleEventSlot, err := eventAccessor.GetEventWithPossibleSlot(r.Context(), parsedID, candidates)
+	if errors.Is(err, event.ErrNotFound) {
+		a.Response(w, http.StatusNotFound, "event not found")
+		return
+	}
+	if err != nil {
+		a.internalError(w, r, err)
 		return
 	}
 
@@ -274,10 +885,369 @@ func (a *API) getPossibleEventSlot(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// The organizer's own availability within the event's slots, for context on the pick.
+	organizerSlots, err := a.userAccessor().GetUserSlots(r.Context(), evt.UserID)
+	if err != nil {
+		a.internalError(w, r, fmt.Errorf("get organizer slots: %w", err))
+		return
+	}
+	from, to := evt.Window()
+
 	response := map[string]any{
-		"slot":              possibleEventSlot.Slot,
-		"users":             possibleEventSlot.Users,
-		"not_working_users": possibleEventSlot.NotWorkingUsers,
+		"slot":                   possibleEventSlot.Slot,
+		"users":                  user.PageUsers(possibleEventSlot.Users, limit, offset),
+		"users_total":            len(possibleEventSlot.Users),
+		"not_working_users":      possibleEventSlot.NotWorkingUsers,
+		"organizer_availability": user.CommonAvailability([][]user.Slot{organizerSlots}, from, to, 0),
+	}
+	if possibleEventSlot.Window != nil {
+		response["window"] = possibleEventSlot.Window
 	}
 	a.Response(w, http.StatusOK, response)
 }
+
+// possibleSlotAccessor returns the event accessor for a possible-slot search in the mode asked
+// for by the request, answering 400 for an unknown mode. It reports whether to go on.
+func (a *API) possibleSlotAccessor(w http.ResponseWriter, r *http.Request) (*event.Accessor, bool) {
+	eventAccessor := a.eventAccessor(a.userAccessor())
+	switch r.URL.Query().Get("mode") {
+	case "", "contain":
+	case "overlap":
+		eventAccessor = eventAccessor.WithOverlap()
+	case "trim":
+		eventAccessor = eventAccessor.WithTrim()
+	default:
+		a.Response(w, http.StatusBadRequest, "mode must be contain, overlap or trim")
+		return nil, false
+	}
+	return eventAccessor, true
+}
+
+// lookupCandidates fetches the users a possible-slot search is restricted to, answering 400 for
+// a malformed ID and 404 for a missing user. It reports whether to go on.
+func (a *API) lookupCandidates(w http.ResponseWriter, r *http.Request, rawIDs []string) ([]user.User, bool) {
+	userIDs := make([]uuid.UUID, 0, len(rawIDs))
+	for _, rawID := range rawIDs {
+		userID, err := uuid.Parse(strings.TrimSpace(rawID))
+		if err != nil {
+			a.Response(w, http.StatusBadRequest, fmt.Sprintf("invalid user ID %q", rawID))
+			return nil, false
+		}
+		userIDs = append(userIDs, userID)
+	}
+	return a.lookupUsers(w, r, userIDs)
+}
+
+// lookupUsers fetches the users with the given IDs in one query, in the order of ids, answering 404
+// for the first ID with no user. It reports whether to go on.
+func (a *API) lookupUsers(w http.ResponseWriter, r *http.Request, ids []uuid.UUID) ([]user.User, bool) {
+	found, err := a.userAccessor().GetUsersByIDs(r.Context(), ids)
+	if err != nil {
+		a.internalError(w, r, err)
+		return nil, false
+	}
+	byID := make(map[uuid.UUID]user.User, len(found))
+	for _, u := range found {
+		byID[u.ID] = u
+	}
+	users := make([]user.User, 0, len(ids))
+	for _, id := range ids {
+		u, ok := byID[id]
+		if !ok {
+			a.Response(w, http.StatusNotFound, fmt.Sprintf("user %s not found", id))
+			return nil, false
+		}
+		users = append(users, u)
+	}
+	return users, true
+}
+
+type attendanceSummaryResponse struct {
+	BestSlot       event.Slot  `json:"best_slot"`
+	AttendingCount int         `json:"attending_count"`
+	TotalUsers     int         `json:"total_users"`
+	NotWorking     []user.User `json:"not_working"`
+}
+
+func (a *API) getAttendanceSummary(w http.ResponseWriter, r *http.Request) {
+	eventID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		a.Response(w, http.StatusBadRequest, "invalid event ID")
+		return
+	}
+
+	eventAccessor := a.eventAccessor(a.userAccessor())
+	possibleEventSlot, err := eventAccessor.GetPossibleEventSlot(r.Context(), eventID)
+	if errors.Is(err, event.ErrNotFound) {
+		a.Response(w, http.StatusNotFound, "event not found")
+		return
+	}
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+	if possibleEventSlot == nil {
+		a.Response(w, http.StatusNotFound, "no possible event slot found")
+		return
+	}
+
+	// Every user is a candidate, so attending and not working users add up to all users.
+	a.Response(w, http.StatusOK, attendanceSummaryResponse{
+		BestSlot:       possibleEventSlot.Slot,
+		AttendingCount: len(possibleEventSlot.Users),
+		TotalUsers:     len(possibleEventSlot.Users) + len(possibleEventSlot.NotWorkingUsers),
+		NotWorking:     possibleEventSlot.NotWorkingUsers,
+	})
+}
+
+type slotAvailabilityResponse struct {
+	Slot            event.Slot `json:"slot"`
+	AvailableCount  int        `json:"available_count"`
+	NotWorkingCount int        `json:"not_working_count"`
+}
+
+func (a *API) getSlotAvailability(w http.ResponseWriter, r *http.Request) {
+	eventID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		a.Response(w, http.StatusBadRequest, "invalid event ID")
+		return
+	}
+
+	eventAccessor := a.eventAccessor(a.userAccessor())
+	availability, err := eventAccessor.GetSlotAvailability(r.Context(), eventID)
+	if errors.Is(err, event.ErrNotFound) {
+		a.Response(w, http.StatusNotFound, "event not found")
+		return
+	}
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+
+	response := make([]slotAvailabilityResponse, 0, len(availability))
+	for _, sa := range availability {
+		response = append(response, slotAvailabilityResponse{
+			Slot:            sa.Slot,
+			AvailableCount:  sa.AvailableCount,
+			NotWorkingCount: sa.NotWorkingCount,
+		})
+	}
+	a.Response(w, http.StatusOK, response)
+}
+
+type reassignEventsRequest struct {
+	ToID string `json:"to_id"`
+}
+
+type reassignEventsResponse struct {
+	Moved int64 `json:"moved"`
+}
+
+func (a *API) reassignEvents(w http.ResponseWriter, r *http.Request) {
+	fromID, err := uuid.Parse(mux.Vars(r)["fromID"])
+	if err != nil {
+		a.Response(w, http.StatusBadRequest, "invalid organizer ID")
+		return
+	}
+
+	var req reassignEventsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		a.invalidBody(w, err)
+		return
+	}
+
+	toID, err := uuid.Parse(req.ToID)
+	if err != nil {
+		a.Response(w, http.StatusBadRequest, "invalid target organizer ID")
+		return
+	}
+
+	userAccessor := a.userAccessor()
+	for _, id := range []uuid.UUID{fromID, toID} {
+		_, err := userAccessor.GetUser(r.Context(), id)
+		if errors.Is(err, user.ErrNotFound) {
+			a.Response(w, http.StatusNotFound, "user not found")
+			return
+		}
+		if err != nil {
+			a.internalError(w, r, err)
+			return
+		}
+	}
+
+	eventAccessor := a.eventAccessor(userAccessor)
+	moved, err := eventAccessor.ReassignEvents(r.Context(), fromID, toID)
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+
+	a.Response(w, http.StatusOK, reassignEventsResponse{Moved: moved})
+}
+
+type transferEventRequest struct {
+	NewOrganizerID string `json:"new_organizer_id"`
+}
+
+func (a *API) transferEvent(w http.ResponseWriter, r *http.Request) {
+	eventID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		a.Response(w, http.StatusBadRequest, "invalid event ID")
+		return
+	}
+
+	var req transferEventRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		a.invalidBody(w, err)
+		return
+	}
+
+	newOrganizerID, err := uuid.Parse(req.NewOrganizerID)
+	if err != nil {
+		a.Response(w, http.StatusBadRequest, "invalid new organizer ID")
+		return
+	}
+
+	userAccessor := a.userAccessor()
+	organizer, err := userAccessor.GetUser(r.Context(), newOrganizerID)
+	if errors.Is(err, user.ErrNotFound) {
+		a.Response(w, http.StatusNotFound, "user not found")
+		return
+	}
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+
+	e, err := a.eventAccessor(userAccessor).TransferEvent(r.Context(), eventID, newOrganizerID)
+	if errors.Is(err, event.ErrNotFound) {
+		a.Response(w, http.StatusNotFound, "event not found")
+		return
+	}
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+	a.notifier.EventUpdated(r.Context(), *e)
+
+	a.Response(w, http.StatusOK, eventResponse(e, organizer))
+}
+
+type rsvpRequest struct {
+	UserID string           `json:"user_id"`
+	Status event.RSVPStatus `json:"status"`
+}
+
+// rsvpConflict is an event the user already said yes to, at the slot it would take place.
+type rsvpConflict struct {
+	EventID uuid.UUID  `json:"event_id"`
+	Title   string     `json:"title"`
+	Slot    event.Slot `json:"slot"`
+}
+
+type rsvpConflictResponse struct {
+	Error     string         `json:"error"`
+	Conflicts []rsvpConflict `json:"conflicts"`
+}
+
+// setRSVP records a user's answer to an event. A yes is refused with 409 while the user has said yes
+// to another event whose chosen slot overlaps this one's, see event.Accessor.GetRSVPConflicts.
+func (a *API) setRSVP(w http.ResponseWriter, r *http.Request) {
+	eventID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		a.Response(w, http.StatusBadRequest, "invalid event ID")
+		return
+	}
+
+	var req rsvpRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		a.invalidBody(w, err)
+		return
+	}
+
+	userID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		a.Response(w, http.StatusBadRequest, "invalid user ID")
+		return
+	}
+	if err := req.Status.Validate(); err != nil {
+		a.invalidPayload(w, err)
+		return
+	}
+
+	userAccessor := a.userAccessor()
+	eventAccessor := a.eventAccessor(userAccessor)
+	evt, err := eventAccessor.GetEvent(r.Context(), eventID)
+	if errors.Is(err, event.ErrNotFound) {
+		a.Response(w, http.StatusNotFound, "event not found")
+		return
+	}
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+
+	u, err := userAccessor.GetUser(r.Context(), userID)
+	if errors.Is(err, user.ErrNotFound) {
+		a.Response(w, http.StatusNotFound, "user not found")
+		return
+	}
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+
+	if req.Status == event.RSVPYes {
+		conflicts, err := eventAccessor.GetRSVPConflicts(r.Context(), evt, u.ID)
+		if err != nil {
+			a.internalError(w, r, err)
+			return
+		}
+		if len(conflicts) > 0 {
+			res := rsvpConflictResponse{Error: "user already attends an overlapping event", Conflicts: make([]rsvpConflict, len(conflicts))}
+			for i, c := range conflicts {
+				res.Conflicts[i] = rsvpConflict{EventID: c.Event.ID, Title: c.Event.Title, Slot: c.Slot}
+			}
+			a.Response(w, http.StatusConflict, res)
+			return
+		}
+	}
+
+	if err := eventAccessor.SetRSVP(r.Context(), evt.ID, u.ID, req.Status); err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+
+	a.Response(w, http.StatusOK, event.Attendee{User: *u, Status: req.Status})
+}
+
+type getAttendeesResponse struct {
+	Attendees []event.Attendee `json:"attendees"`
+}
+
+func (a *API) getAttendees(w http.ResponseWriter, r *http.Request) {
+	eventID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		a.Response(w, http.StatusBadRequest, "invalid event ID")
+		return
+	}
+
+	eventAccessor := a.eventAccessor(a.userAccessor())
+	evt, err := eventAccessor.GetEvent(r.Context(), eventID)
+	if errors.Is(err, event.ErrNotFound) {
+		a.Response(w, http.StatusNotFound, "event not found")
+		return
+	}
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+
+	attendees, err := eventAccessor.GetAttendees(r.Context(), evt.ID)
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+
+	a.Response(w, http.StatusOK, getAttendeesResponse{Attendees: attendees})
+}
@@ -3,16 +3,25 @@ package api_test
 import (
 	"bytes"
 	"database/sql"
+	"database/sql/driver"
 	"encoding/json"
 	"events-system/api"
+	"events-system/event"
+	"events-system/validation"
+	"fmt"
+	"io"
+	"maps"
 	"net/http"
 	"net/http/httptest"
 	"regexp"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -28,6 +37,53 @@ func setupEventsAPI(t *testing.T) (*api.API, sqlmock.Sqlmock) {
 	return a, dbMock
 }
 
+// slotsArg matches a slots column written with the given bounds, in unix seconds.
+type slotsArg [][2]int64
+
+func (s slotsArg) Match(v driver.Value) bool {
+	var slots event.SlotsColumn
+	if err := slots.Scan(v); err != nil || len(slots) != len(s) {
+		return false
+	}
+	for i, slot := range slots {
+		if slot.StartTime.Unix() != s[i][0] || slot.EndTime.Unix() != s[i][1] {
+			return false
+		}
+	}
+	return true
+}
+
+// jsonRequest builds a request carrying a JSON body.
+func jsonRequest(method, target string, body io.Reader) *http.Request {
+	req := httptest.NewRequest(method, target, body)
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
+
+// labelsArg matches a slots column whose slots carry the given labels, "" meaning none.
+type labelsArg []string
+
+func (l labelsArg) Match(v driver.Value) bool {
+	var slots event.SlotsColumn
+	if err := slots.Scan(v); err != nil || len(slots) != len(l) {
+		return false
+	}
+	for i, slot := range slots {
+		if slot.Label != l[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// capturedArg matches any value and keeps it, so that what was written can be read back.
+type capturedArg struct{ value driver.Value }
+
+func (c *capturedArg) Match(v driver.Value) bool {
+	c.value = v
+	return true
+}
+
 func TestEventsAPI(t *testing.T) {
 	t.Parallel()
 
@@ -39,9 +95,13 @@ func TestEventsAPI(t *testing.T) {
 		startTime := time.Now().Add(24 * time.Hour)
 		endTime := startTime.Add(2 * time.Hour)
 
-		insertQuery := `INSERT INTO events (id, title, duration_hours, user_id, slots, created_at) VALUES ($1, $2, $3, $4, $5, $6)`
+		insertQuery := `INSERT INTO events (id, title, duration_hours, user_id, slots, timezone, created_at, visibility) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+			WithArgs(organizerID).
+			WillReturnRows(sqlmock.NewRows(userColumns).
+				AddRow(organizerID, "Organizer", "organizer@example.com", userCreatedAt, userCreatedAt))
 		dbMock.ExpectExec(regexp.QuoteMeta(insertQuery)).
-			WithArgs(sqlmock.AnyArg(), "Team Meeting", 2, organizerID, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WithArgs(sqlmock.AnyArg(), "Team Meeting", 2, organizerID, sqlmock.AnyArg(), "UTC", sqlmock.AnyArg(), "public").
 			WillReturnResult(sqlmock.NewResult(1, 1))
 
 		body := map[string]any{
@@ -67,106 +127,282 @@ func TestEventsAPI(t *testing.T) {
 		require.True(t, ok)
 		assert.Equal(t, "Team Meeting", evt["title"])
 		assert.NotEmpty(t, evt["id"])
+		assert.Equal(t, "/api/events/"+evt["id"].(string), rec.Header().Get("Location"))
 	})
 
-	t.Run("create event invalid body", func(t *testing.T) {
+	t.Run("create event with slot labels", func(t *testing.T) {
 		t.Parallel()
-		a, _ := setupEventsAPI(t)
+		a, dbMock := setupEventsAPI(t)
 
-		req := httptest.NewRequest(http.MethodPost, "/api/events", bytes.NewBufferString("invalid"))
+		organizerID := uuid.New()
+		startTime := time.Date(2030, 1, 2, 9, 0, 0, 0, time.UTC)
+
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+			WithArgs(organizerID).
+			WillReturnRows(sqlmock.NewRows(userColumns).
+				AddRow(organizerID, "Organizer", "organizer@example.com", userCreatedAt, userCreatedAt))
+		dbMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO events`)).
+			WithArgs(sqlmock.AnyArg(), "Team Meeting", 1, organizerID, labelsArg{"Morning option", ""}, "UTC", sqlmock.AnyArg(), "public").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		body := fmt.Sprintf(`{"title":"Team Meeting","duration_hours":1,"organizer_id":%q,"slots":[`+
+			`{"start_time":%d,"end_time":%d,"label":"Morning option"},{"start_time":%d,"end_time":%d}]}`,
+			organizerID, startTime.Unix(), startTime.Add(time.Hour).Unix(), startTime.Add(5*time.Hour).Unix(), startTime.Add(6*time.Hour).Unix())
+		req := httptest.NewRequest(http.MethodPost, "/api/events", strings.NewReader(body))
 		req.Header.Set("Content-Type", "application/json")
 		rec := httptest.NewRecorder()
 
 		a.Router().ServeHTTP(rec, req)
 
-		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		assert.Equal(t, http.StatusCreated, rec.Code)
+
+		var res api.Response
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+		slots := res.Response.(map[string]any)["slots"].([]any)
+		require.Len(t, slots, 2)
+		assert.Equal(t, "Morning option", slots[0].(map[string]any)["label"])
+		assert.NotContains(t, slots[1].(map[string]any), "label")
 	})
 
-	t.Run("create event validation error", func(t *testing.T) {
+	t.Run("get event with and without slot labels", func(t *testing.T) {
 		t.Parallel()
-		a, _ := setupEventsAPI(t)
+		a, dbMock := setupEventsAPI(t)
 
-		body := `{"title":"","duration_hours":2,"organizer_id":"00000000-0000-0000-0000-000000000000","slots":[]}`
-		req := httptest.NewRequest(http.MethodPost, "/api/events", bytes.NewBufferString(body))
-		req.Header.Set("Content-Type", "application/json")
+		eventID, organizerID := uuid.New(), uuid.New()
+		// The second slot was stored before labels existed.
+		slotsJSON := []byte(`[{"start_time":"2030-01-02T09:00:00Z","end_time":"2030-01-02T10:00:00Z","label":"Morning option"},` +
+			`{"start_time":"2030-01-02T14:00:00Z","end_time":"2030-01-02T15:00:00Z"}]`)
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1`)).
+			WithArgs(eventID).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}).
+				AddRow(eventID, "Team Meeting", 1, organizerID, slotsJSON, "UTC", time.Now(), "public"))
+
+		req := httptest.NewRequest(http.MethodGet, "/api/events/"+eventID.String()+"?fields=slots", nil)
 		rec := httptest.NewRecorder()
 
 		a.Router().ServeHTTP(rec, req)
 
-		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var res api.Response
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+		slots := res.Response.(map[string]any)["slots"].([]any)
+		require.Len(t, slots, 2)
+		assert.Equal(t, "Morning option", slots[0].(map[string]any)["label"])
+		assert.NotContains(t, slots[1].(map[string]any), "label")
 	})
 
-	t.Run("get event", func(t *testing.T) {
+	t.Run("create event with negative timestamp", func(t *testing.T) {
 		t.Parallel()
 		a, dbMock := setupEventsAPI(t)
 
-		eventID := uuid.New()
-		organizerID := uuid.New()
-		now := time.Now()
-		startTime := now.Add(24 * time.Hour)
-		endTime := startTime.Add(2 * time.Hour)
-		// Slots stored in DB as JSONB with ISO8601 strings (TIMESTAMPTZ)
-		slotsJSON := []byte(`[{"start_time":"` + startTime.Format(time.RFC3339) + `","end_time":"` + endTime.Format(time.RFC3339) + `"}]`)
-
-		selectQuery := regexp.QuoteMeta(`SELECT id, title, duration_hours, user_id, slots, created_at FROM events WHERE id = $1`)
-		dbMock.ExpectQuery(selectQuery).
-			WithArgs(eventID).
-			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "created_at"}).
-				AddRow(eventID, "Team Meeting", 2, organizerID, slotsJSON, now))
-
-		// Mock GetUser for organizer
-		getUserQuery := regexp.QuoteMeta(`SELECT id, name, email FROM users WHERE id = $1`)
-		dbMock.ExpectQuery(getUserQuery).
-			WithArgs(organizerID).
-			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email"}).
-				AddRow(organizerID, "Organizer", "organizer@example.com"))
-
-		req := httptest.NewRequest(http.MethodGet, "/api/events/"+eventID.String(), nil)
+		body, _ := json.Marshal(map[string]any{
+			"title":          "Team Meeting",
+			"duration_hours": 2,
+			"organizer_id":   uuid.NewString(),
+			"slots": []map[string]int64{
+				{"start_time": time.Now().Unix(), "end_time": time.Now().Add(2 * time.Hour).Unix()},
+				{"start_time": -3600, "end_time": 3600},
+			},
+		})
+		req := httptest.NewRequest(http.MethodPost, "/api/events", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
 		rec := httptest.NewRecorder()
 
 		a.Router().ServeHTTP(rec, req)
 
 		require.NoError(t, dbMock.ExpectationsWereMet())
-		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
 
 		var res api.Response
 		require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
-		assert.Equal(t, http.StatusOK, res.Status)
-		evt, ok := res.Response.(map[string]any)
-		require.True(t, ok)
-		assert.Equal(t, eventID.String(), evt["id"])
-		assert.Equal(t, "Team Meeting", evt["title"])
-		// Check organizer is included
-		organizer, ok := evt["organizer"].(map[string]any)
-		require.True(t, ok)
-		assert.Equal(t, organizerID.String(), organizer["id"])
-		assert.Equal(t, "Organizer", organizer["name"])
+		assert.Contains(t, res.Response, "slot 1:")
 	})
 
-	t.Run("get event not found", func(t *testing.T) {
+	t.Run("create event if none match", func(t *testing.T) {
 		t.Parallel()
 		a, dbMock := setupEventsAPI(t)
 
 		eventID := uuid.New()
-		selectQuery := regexp.QuoteMeta(`SELECT id, title, duration_hours, user_id, slots, created_at FROM events WHERE id = $1`)
+		organizerID := uuid.New()
+		startTime := time.Now().Add(24 * time.Hour)
+		endTime := startTime.Add(2 * time.Hour)
+		selectQuery := regexp.QuoteMeta(`SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1`)
+		insertQuery := regexp.QuoteMeta(`INSERT INTO events (id, title, duration_hours, user_id, slots, timezone, created_at, visibility) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`)
+		body, _ := json.Marshal(map[string]any{
+			"id":             eventID.String(),
+			"title":          "Team Meeting",
+			"duration_hours": 2,
+			"organizer_id":   organizerID.String(),
+			"slots":          []map[string]int64{{"start_time": startTime.Unix(), "end_time": endTime.Unix()}},
+		})
+
+		// first attempt creates the event
 		dbMock.ExpectQuery(selectQuery).
 			WithArgs(eventID).
 			WillReturnError(sql.ErrNoRows)
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+			WithArgs(organizerID).
+			WillReturnRows(sqlmock.NewRows(userColumns).
+				AddRow(organizerID, "Organizer", "organizer@example.com", userCreatedAt, userCreatedAt))
+		dbMock.ExpectExec(insertQuery).
+			WithArgs(eventID, "Team Meeting", 2, organizerID, sqlmock.AnyArg(), "UTC", sqlmock.AnyArg(), "public").
+			WillReturnResult(sqlmock.NewResult(1, 1))
 
-		req := httptest.NewRequest(http.MethodGet, "/api/events/"+eventID.String(), nil)
+		req := httptest.NewRequest(http.MethodPost, "/api/events", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-None-Match", "*")
 		rec := httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+
+		// retry finds it and fails the precondition
+		dbMock.ExpectQuery(selectQuery).
+			WithArgs(eventID).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}).
+				AddRow(eventID, "Team Meeting", 2, organizerID, []byte("[]"), "UTC", time.Now(), "public"))
 
+		req = httptest.NewRequest(http.MethodPost, "/api/events", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-None-Match", "*")
+		rec = httptest.NewRecorder()
 		a.Router().ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
 
 		require.NoError(t, dbMock.ExpectationsWereMet())
-		assert.Equal(t, http.StatusNotFound, rec.Code)
 	})
 
-	t.Run("get event invalid id", func(t *testing.T) {
+	t.Run("create event with a taken id", func(t *testing.T) {
+		t.Parallel()
+
+		for _, tc := range []struct {
+			name        string
+			ifNoneMatch string
+			status      int
+		}{
+			// Another request stored the event between the If-None-Match check and the insert.
+			{name: "if none match race", ifNoneMatch: "*", status: http.StatusPreconditionFailed},
+			// The id belongs to a deleted event.
+			{name: "deleted event", status: http.StatusConflict},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+				a, dbMock := setupEventsAPI(t)
+
+				eventID := uuid.New()
+				organizerID := uuid.New()
+				startTime := time.Now().Add(24 * time.Hour)
+				body, _ := json.Marshal(map[string]any{
+					"id":             eventID.String(),
+					"title":          "Team Meeting",
+					"duration_hours": 2,
+					"organizer_id":   organizerID.String(),
+					"slots":          []map[string]int64{{"start_time": startTime.Unix(), "end_time": startTime.Add(2 * time.Hour).Unix()}},
+				})
+
+				if tc.ifNoneMatch != "" {
+					dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1`)).
+						WithArgs(eventID).
+						WillReturnError(sql.ErrNoRows)
+				}
+				dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+					WithArgs(organizerID).
+					WillReturnRows(sqlmock.NewRows(userColumns).
+						AddRow(organizerID, "Organizer", "organizer@example.com", userCreatedAt, userCreatedAt))
+				dbMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO events`)).
+					WithArgs(eventID, "Team Meeting", 2, organizerID, sqlmock.AnyArg(), "UTC", sqlmock.AnyArg(), "public").
+					WillReturnError(&pq.Error{Code: "23505"})
+
+				req := jsonRequest(http.MethodPost, "/api/events", bytes.NewBuffer(body))
+				if tc.ifNoneMatch != "" {
+					req.Header.Set("If-None-Match", tc.ifNoneMatch)
+				}
+				rec := httptest.NewRecorder()
+				a.Router().ServeHTTP(rec, req)
+
+				require.NoError(t, dbMock.ExpectationsWereMet())
+				assert.Equal(t, tc.status, rec.Code, rec.Body.String())
+			})
+		}
+	})
+
+	t.Run("create event requiring organizer availability", func(t *testing.T) {
+		t.Parallel()
+
+		organizerID := uuid.New()
+		startTime := time.Now().Add(24 * time.Hour)
+		endTime := startTime.Add(2 * time.Hour)
+		availableQuery := regexp.QuoteMeta(`SELECT 1 FROM users_availability`)
+		recurrencesQuery := regexp.QuoteMeta(`FROM users_recurring_availability WHERE user_id = ANY($1)`)
+		body, _ := json.Marshal(map[string]any{
+			"title":                       "Team Meeting",
+			"duration_hours":              2,
+			"organizer_id":                organizerID.String(),
+			"slots":                       []map[string]int64{{"start_time": startTime.Unix(), "end_time": endTime.Unix()}},
+			"require_organizer_available": true,
+		})
+
+		t.Run("organizer available", func(t *testing.T) {
+			t.Parallel()
+			a, dbMock := setupEventsAPI(t)
+
+			dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+				WithArgs(organizerID).
+				WillReturnRows(sqlmock.NewRows(userColumns).
+					AddRow(organizerID, "Organizer", "organizer@example.com", userCreatedAt, userCreatedAt))
+			dbMock.ExpectQuery(recurrencesQuery).
+				WithArgs(sqlmock.AnyArg()).
+				WillReturnRows(sqlmock.NewRows([]string{"user_id", "weekday", "start_minute", "end_minute", "valid_from", "valid_until"}))
+			dbMock.ExpectBegin()
+			dbMock.ExpectQuery(availableQuery).
+				WithArgs(organizerID, sqlmock.AnyArg(), 2).
+				WillReturnRows(sqlmock.NewRows([]string{"?column?"}).AddRow(1))
+			dbMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO events (id, title, duration_hours, user_id, slots, timezone, created_at, visibility) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`)).
+				WithArgs(sqlmock.AnyArg(), "Team Meeting", 2, organizerID, sqlmock.AnyArg(), "UTC", sqlmock.AnyArg(), "public").
+				WillReturnResult(sqlmock.NewResult(1, 1))
+			dbMock.ExpectCommit()
+
+			rec := httptest.NewRecorder()
+			a.Router().ServeHTTP(rec, jsonRequest(http.MethodPost, "/api/events", bytes.NewReader(body)))
+
+			assert.Equal(t, http.StatusCreated, rec.Code)
+			require.NoError(t, dbMock.ExpectationsWereMet())
+		})
+
+		t.Run("organizer unavailable", func(t *testing.T) {
+			t.Parallel()
+			a, dbMock := setupEventsAPI(t)
+
+			dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+				WithArgs(organizerID).
+				WillReturnRows(sqlmock.NewRows(userColumns).
+					AddRow(organizerID, "Organizer", "organizer@example.com", userCreatedAt, userCreatedAt))
+			dbMock.ExpectQuery(recurrencesQuery).
+				WithArgs(sqlmock.AnyArg()).
+				WillReturnRows(sqlmock.NewRows([]string{"user_id", "weekday", "start_minute", "end_minute", "valid_from", "valid_until"}))
+			dbMock.ExpectBegin()
+			dbMock.ExpectQuery(availableQuery).
+				WithArgs(organizerID, sqlmock.AnyArg(), 2).
+				WillReturnRows(sqlmock.NewRows([]string{"?column?"}))
+			dbMock.ExpectRollback()
+
+			rec := httptest.NewRecorder()
+			a.Router().ServeHTTP(rec, jsonRequest(http.MethodPost, "/api/events", bytes.NewReader(body)))
+
+			assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+			assert.JSONEq(t, `{"status":422,"response":"organizer is not available for any slot"}`, rec.Body.String())
+			require.NoError(t, dbMock.ExpectationsWereMet())
+		})
+	})
+
+	t.Run("create event invalid body", func(t *testing.T) {
 		t.Parallel()
 		a, _ := setupEventsAPI(t)
 
-		req := httptest.NewRequest(http.MethodGet, "/api/events/not-a-uuid", nil)
+		req := httptest.NewRequest(http.MethodPost, "/api/events", bytes.NewBufferString("invalid"))
+		req.Header.Set("Content-Type", "application/json")
 		rec := httptest.NewRecorder()
 
 		a.Router().ServeHTTP(rec, req)
@@ -174,130 +410,297 @@ func TestEventsAPI(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, rec.Code)
 	})
 
-	t.Run("update event", func(t *testing.T) {
+	t.Run("create event validation error", func(t *testing.T) {
+		t.Parallel()
+		a, _ := setupEventsAPI(t)
+
+		body := `{"title":"","duration_hours":2,"organizer_id":"00000000-0000-0000-0000-000000000000","slots":[]}`
+		req := httptest.NewRequest(http.MethodPost, "/api/events", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+
+		a.Router().ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	})
+
+	t.Run("create event with a past slot warns", func(t *testing.T) {
 		t.Parallel()
 		a, dbMock := setupEventsAPI(t)
 
-		eventID := uuid.New()
 		organizerID := uuid.New()
-		now := time.Now()
-		startTime := now.Add(24 * time.Hour)
-		endTime := startTime.Add(2 * time.Hour)
+		past := time.Date(2020, 1, 2, 9, 0, 0, 0, time.UTC)
+		future := time.Now().Add(24 * time.Hour)
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+			WithArgs(organizerID).
+			WillReturnRows(sqlmock.NewRows(userColumns).
+				AddRow(organizerID, "Organizer", "organizer@example.com", userCreatedAt, userCreatedAt))
+		dbMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO events`)).
+			WillReturnResult(sqlmock.NewResult(1, 1))
 
-		slotsJSON := []byte(`[{"start_time":"` + startTime.Format(time.RFC3339) + `","end_time":"` + endTime.Format(time.RFC3339) + `"}]`)
+		body := fmt.Sprintf(`{"title":"Team Meeting","duration_hours":1,"organizer_id":%q,"slots":[`+
+			`{"start_time":%d,"end_time":%d},{"start_time":%d,"end_time":%d}]}`,
+			organizerID, past.Unix(), past.Add(time.Hour).Unix(), future.Unix(), future.Add(time.Hour).Unix())
+		rec := httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, jsonRequest(http.MethodPost, "/api/events", strings.NewReader(body)))
 
-		getQuery := regexp.QuoteMeta(`SELECT id, title, duration_hours, user_id, slots, created_at FROM events WHERE id = $1`)
-		dbMock.ExpectQuery(getQuery).
-			WithArgs(eventID).
-			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "created_at"}).
-				AddRow(eventID, "Old Title", 2, organizerID, slotsJSON, now))
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		require.Equal(t, http.StatusCreated, rec.Code)
+		var res api.Response
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+		evt := res.Response.(map[string]any)
+		assert.Len(t, evt["slots"], 2)
+		assert.Equal(t, []any{"slot 0 is in the past"}, evt["warnings"])
+	})
 
-		updateQuery := regexp.QuoteMeta(`UPDATE events SET title = $1, duration_hours = $2, slots = $3 WHERE id = $4`)
-		dbMock.ExpectExec(updateQuery).
-			WithArgs("Updated Title", 3, sqlmock.AnyArg(), eventID).
-			WillReturnResult(sqlmock.NewResult(1, 1))
+	t.Run("create event organizer not found", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupEventsAPI(t)
 
-		// After update, GetEvent is called to return the updated event with original created_at
-		getQueryAfterUpdate := regexp.QuoteMeta(`SELECT id, title, duration_hours, user_id, slots, created_at FROM events WHERE id = $1`)
-		dbMock.ExpectQuery(getQueryAfterUpdate).
-			WithArgs(eventID).
-			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "created_at"}).
-				AddRow(eventID, "Updated Title", 3, organizerID, slotsJSON, now))
+		organizerID := uuid.New()
+		startTime := time.Date(2030, 1, 2, 9, 0, 0, 0, time.UTC)
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+			WithArgs(organizerID).
+			WillReturnError(sql.ErrNoRows)
 
-		body := map[string]any{
-			"title":          "Updated Title",
-			"duration_hours": 3,
-			"organizer_id":   organizerID.String(),
-			"slots":          []map[string]int64{{"start_time": startTime.Unix(), "end_time": endTime.Unix()}},
-		}
-		bodyBytes, _ := json.Marshal(body)
-		req := httptest.NewRequest(http.MethodPut, "/api/events/"+eventID.String(), bytes.NewBuffer(bodyBytes))
-		req.Header.Set("Content-Type", "application/json")
+		body := fmt.Sprintf(`{"title":"Team Meeting","duration_hours":1,"organizer_id":%q,"slots":[{"start_time":%d,"end_time":%d}]}`,
+			organizerID, startTime.Unix(), startTime.Add(time.Hour).Unix())
 		rec := httptest.NewRecorder()
-
-		a.Router().ServeHTTP(rec, req)
+		a.Router().ServeHTTP(rec, jsonRequest(http.MethodPost, "/api/events", strings.NewReader(body)))
 
 		require.NoError(t, dbMock.ExpectationsWereMet())
-		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		assert.JSONEq(t, `{"status":422,"response":"organizer not found"}`, rec.Body.String())
+	})
 
+	t.Run("create event with timezone", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupEventsAPI(t)
+
+		organizerID := uuid.New()
+		start := time.Date(2030, 6, 3, 7, 0, 0, 0, time.UTC)
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+			WithArgs(organizerID).
+			WillReturnRows(sqlmock.NewRows(userColumns).AddRow(organizerID, "Organizer", "organizer@example.com", userCreatedAt, userCreatedAt))
+		dbMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO events (id, title, duration_hours, user_id, slots, timezone, created_at, visibility) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`)).
+			WithArgs(sqlmock.AnyArg(), "Standup", 1, organizerID, sqlmock.AnyArg(), "Europe/Berlin", sqlmock.AnyArg(), "public").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		body := fmt.Sprintf(`{"title":"Standup","duration_hours":1,"organizer_id":%q,"timezone":"Europe/Berlin","slots":[{"start_time":%d,"end_time":%d}]}`,
+			organizerID, start.Unix(), start.Add(time.Hour).Unix())
+		rec := httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, jsonRequest(http.MethodPost, "/api/events", strings.NewReader(body)))
+
+		require.Equal(t, http.StatusCreated, rec.Code)
 		var res api.Response
 		require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
-		assert.Equal(t, http.StatusOK, res.Status)
 		evt, ok := res.Response.(map[string]any)
 		require.True(t, ok)
-		assert.Equal(t, "Updated Title", evt["title"])
+		assert.Equal(t, "Europe/Berlin", evt["timezone"])
+		assert.Equal(t, []any{map[string]any{
+			"start_time":  float64(start.Unix()),
+			"end_time":    float64(start.Add(time.Hour).Unix()),
+			"start_local": "2030-06-03T09:00:00+02:00",
+			"end_local":   "2030-06-03T10:00:00+02:00",
+		}}, evt["slots"])
+		require.NoError(t, dbMock.ExpectationsWereMet())
 	})
 
-	t.Run("update event not found", func(t *testing.T) {
+	t.Run("create event invalid timezone", func(t *testing.T) {
+		t.Parallel()
+		a, _ := setupEventsAPI(t)
+
+		body := `{"title":"Standup","duration_hours":1,"organizer_id":"` + uuid.NewString() + `","timezone":"Mars/Olympus_Mons","slots":[]}`
+		rec := httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, jsonRequest(http.MethodPost, "/api/events", strings.NewReader(body)))
+
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		assert.Contains(t, rec.Body.String(), "unknown timezone")
+	})
+
+	t.Run("create event invalid visibility", func(t *testing.T) {
+		t.Parallel()
+		a, _ := setupEventsAPI(t)
+
+		body := `{"title":"Standup","duration_hours":1,"organizer_id":"` + uuid.NewString() + `","visibility":"secret","slots":[]}`
+		rec := httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, jsonRequest(http.MethodPost, "/api/events", strings.NewReader(body)))
+
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		assert.Contains(t, rec.Body.String(), "invalid visibility")
+	})
+
+	t.Run("create event with an all-day slot", func(t *testing.T) {
 		t.Parallel()
 		a, dbMock := setupEventsAPI(t)
 
-		eventID := uuid.New()
 		organizerID := uuid.New()
-		getQuery := regexp.QuoteMeta(`SELECT id, title, duration_hours, user_id, slots, created_at FROM events WHERE id = $1`)
-		dbMock.ExpectQuery(getQuery).
-			WithArgs(eventID).
-			WillReturnError(sql.ErrNoRows)
+		day := func(d int) time.Time { return time.Date(2030, 1, d, 0, 0, 0, 0, time.UTC) }
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+			WithArgs(organizerID).
+			WillReturnRows(sqlmock.NewRows(userColumns).
+				AddRow(organizerID, "Organizer", "organizer@example.com", userCreatedAt, userCreatedAt))
+		dbMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO events (id, title, duration_hours, user_id, slots, timezone, created_at, visibility) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`)).
+			WithArgs(sqlmock.AnyArg(), "Offsite", 0, organizerID, slotsArg{{day(2).Unix(), day(4).Unix()}}, "Europe/Berlin", sqlmock.AnyArg(), "public").
+			WillReturnResult(sqlmock.NewResult(1, 1))
 
-		body := `{"title":"Updated","duration_hours":2,"organizer_id":"` + organizerID.String() + `","slots":[]}`
-		req := httptest.NewRequest(http.MethodPut, "/api/events/"+eventID.String(), bytes.NewBufferString(body))
-		req.Header.Set("Content-Type", "application/json")
+		// Any time of the first and last day will do.
+		body := fmt.Sprintf(`{"title":"Offsite","duration_hours":0,"organizer_id":%q,"timezone":"Europe/Berlin","slots":[{"start_time":%d,"end_time":%d,"all_day":true}]}`,
+			organizerID, day(2).Add(9*time.Hour).Unix(), day(3).Add(17*time.Hour).Unix())
 		rec := httptest.NewRecorder()
-
-		a.Router().ServeHTTP(rec, req)
+		a.Router().ServeHTTP(rec, jsonRequest(http.MethodPost, "/api/events", strings.NewReader(body)))
 
 		require.NoError(t, dbMock.ExpectationsWereMet())
-		assert.Equal(t, http.StatusNotFound, rec.Code)
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+		var res struct {
+			Response struct {
+				Slots []map[string]any `json:"slots"`
+			} `json:"response"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+		assert.Equal(t, []map[string]any{{
+			"start_time": float64(day(2).Unix()),
+			"end_time":   float64(day(4).Unix()),
+			"all_day":    true,
+			"start_date": "2030-01-02",
+			"end_date":   "2030-01-03",
+		}}, res.Response.Slots)
 	})
 
-	t.Run("delete event", func(t *testing.T) {
+	t.Run("create event reports every invalid field", func(t *testing.T) {
 		t.Parallel()
 		a, dbMock := setupEventsAPI(t)
 
-		eventID := uuid.New()
-		organizerID := uuid.New()
-		now := time.Now()
+		start := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+		body := fmt.Sprintf(`{"title":"","duration_hours":0,"organizer_id":%q,"slots":[{"start_time":%d,"end_time":%d}]}`,
+			uuid.NewString(), start.Unix(), start.Add(-time.Hour).Unix())
+		rec := httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, jsonRequest(http.MethodPost, "/api/events", strings.NewReader(body)))
 
-		getQuery := regexp.QuoteMeta(`SELECT id, title, duration_hours, user_id, slots, created_at FROM events WHERE id = $1`)
-		dbMock.ExpectQuery(getQuery).
-			WithArgs(eventID).
-			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "created_at"}).
-				AddRow(eventID, "Event", 2, organizerID, []byte("[]"), now))
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		assert.JSONEq(t, `{"status":422,"response":{
+			"error":"validate: title is required; duration hours must be greater than 0; slot 0: start time is after end time",
+			"errors":[
+				{"field":"title","message":"title is required"},
+				{"field":"duration_hours","message":"duration hours must be greater than 0"},
+				{"field":"slots[0].end_time","message":"slot 0: start time is after end time"}
+			]}}`, rec.Body.String())
+	})
 
-		deleteQuery := regexp.QuoteMeta(`DELETE FROM events WHERE id = $1`)
-		dbMock.ExpectExec(deleteQuery).
-			WithArgs(eventID).
+	t.Run("created event reads back with identical slots", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupEventsAPI(t)
+
+		organizerID := uuid.New()
+		// Sub-minute bounds in a timezone with an offset must survive the JSONB round trip.
+		start := time.Date(2030, 7, 1, 7, 15, 37, 0, time.UTC)
+		slots := &capturedArg{}
+		organizerRow := func() *sqlmock.Rows {
+			return sqlmock.NewRows(userColumns).AddRow(organizerID, "Organizer", "organizer@example.com", userCreatedAt, userCreatedAt)
+		}
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+			WithArgs(organizerID).
+			WillReturnRows(organizerRow())
+		dbMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO events`)).
+			WithArgs(sqlmock.AnyArg(), "Standup", 1, organizerID, slots, "Europe/Berlin", sqlmock.AnyArg(), "public").
 			WillReturnResult(sqlmock.NewResult(1, 1))
 
-		req := httptest.NewRequest(http.MethodDelete, "/api/events/"+eventID.String(), nil)
+		body := fmt.Sprintf(`{"title":"Standup","duration_hours":1,"organizer_id":%q,"timezone":"Europe/Berlin","slots":[`+
+			`{"start_time":%d,"end_time":%d,"label":"Early"},{"start_time":%d,"end_time":%d}]}`,
+			organizerID, start.Unix(), start.Add(time.Hour+29*time.Second).Unix(), start.Add(26*time.Hour).Unix(), start.Add(27*time.Hour).Unix())
 		rec := httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, jsonRequest(http.MethodPost, "/api/events", strings.NewReader(body)))
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+		var created struct {
+			Response struct {
+				ID    string          `json:"id"`
+				Slots json.RawMessage `json:"slots"`
+			} `json:"response"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
 
-		a.Router().ServeHTTP(rec, req)
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1`)).
+			WithArgs(created.Response.ID).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}).
+				AddRow(created.Response.ID, "Standup", 1, organizerID, slots.value, "Europe/Berlin", time.Now(), "public"))
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+			WithArgs(organizerID).
+			WillReturnRows(organizerRow())
+
+		rec = httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/events/"+created.Response.ID, nil))
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
 
+		var read struct {
+			Response struct {
+				Slots json.RawMessage `json:"slots"`
+			} `json:"response"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &read))
+		assert.Equal(t, string(created.Response.Slots), string(read.Response.Slots))
 		require.NoError(t, dbMock.ExpectationsWereMet())
-		assert.Equal(t, http.StatusNoContent, rec.Code)
 	})
 
-	t.Run("delete event not found", func(t *testing.T) {
+	t.Run("get event renders slots across a DST change", func(t *testing.T) {
 		t.Parallel()
 		a, dbMock := setupEventsAPI(t)
 
-		eventID := uuid.New()
-		getQuery := regexp.QuoteMeta(`SELECT id, title, duration_hours, user_id, slots, created_at FROM events WHERE id = $1`)
-		dbMock.ExpectQuery(getQuery).
+		eventID, organizerID := uuid.New(), uuid.New()
+		// US clocks go forward at 02:00 on 2030-03-10, so this slot starts in EST and ends in EDT.
+		start := time.Date(2030, 3, 10, 6, 0, 0, 0, time.UTC)
+		slotsJSON, _ := event.SlotsColumn([]event.Slot{{StartTime: start, EndTime: start.Add(2 * time.Hour)}}).Value()
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1`)).
 			WithArgs(eventID).
-			WillReturnError(sql.ErrNoRows)
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}).
+				AddRow(eventID, "Night shift", 2, organizerID, slotsJSON, "America/New_York", time.Now(), "public"))
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+			WithArgs(organizerID).
+			WillReturnRows(sqlmock.NewRows(userColumns).AddRow(organizerID, "Organizer", "organizer@example.com", userCreatedAt, userCreatedAt))
 
-		req := httptest.NewRequest(http.MethodDelete, "/api/events/"+eventID.String(), nil)
 		rec := httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/events/"+eventID.String(), nil))
 
-		a.Router().ServeHTTP(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+		var res api.Response
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+		evt, ok := res.Response.(map[string]any)
+		require.True(t, ok)
+		slots, ok := evt["slots"].([]any)
+		require.True(t, ok)
+		require.Len(t, slots, 1)
+		slot := slots[0].(map[string]any)
+		assert.Equal(t, "2030-03-10T01:00:00-05:00", slot["start_local"])
+		assert.Equal(t, "2030-03-10T04:00:00-04:00", slot["end_local"])
+		require.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("get private event", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupEventsAPI(t)
+
+		eventID, organizerID := uuid.New(), uuid.New()
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1`)).
+			WithArgs(eventID).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}).
+				AddRow(eventID, "1:1", 1, organizerID, []byte("[]"), "UTC", time.Now(), "private"))
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+			WithArgs(organizerID).
+			WillReturnRows(sqlmock.NewRows(userColumns).
+				AddRow(organizerID, "Organizer", "organizer@example.com", userCreatedAt, userCreatedAt))
+
+		rec := httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/events/"+eventID.String(), nil))
 
 		require.NoError(t, dbMock.ExpectationsWereMet())
-		assert.Equal(t, http.StatusNotFound, rec.Code)
+		require.Equal(t, http.StatusOK, rec.Code)
+		var res api.Response
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+		assert.Equal(t, "private", res.Response.(map[string]any)["visibility"])
 	})
 
-	t.Run("get possible event slot", func(t *testing.T) {
+	t.Run("get event", func(t *testing.T) {
 		t.Parallel()
 		a, dbMock := setupEventsAPI(t)
 
@@ -306,27 +709,1945 @@ func TestEventsAPI(t *testing.T) {
 		now := time.Now()
 		startTime := now.Add(24 * time.Hour)
 		endTime := startTime.Add(2 * time.Hour)
+		// Slots stored in DB as JSONB with ISO8601 strings (TIMESTAMPTZ)
 		slotsJSON := []byte(`[{"start_time":"` + startTime.Format(time.RFC3339) + `","end_time":"` + endTime.Format(time.RFC3339) + `"}]`)
 
-		getEventQuery := regexp.QuoteMeta(`SELECT id, title, duration_hours, user_id, slots, created_at FROM events WHERE id = $1`)
-		dbMock.ExpectQuery(getEventQuery).
+		selectQuery := regexp.QuoteMeta(`SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1`)
+		dbMock.ExpectQuery(selectQuery).
 			WithArgs(eventID).
-			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "created_at"}).
-				AddRow(eventID, "Event", 2, organizerID, slotsJSON, now))
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}).
+				AddRow(eventID, "Team Meeting", 2, organizerID, slotsJSON, "UTC", now, "public"))
 
-		getUsersQuery := regexp.QuoteMeta(`SELECT id, name, email FROM users`)
-		userID := uuid.New()
-		dbMock.ExpectQuery(getUsersQuery).
-			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email"}).
-				AddRow(userID, "Alice", "alice@example.com"))
+		// Mock GetUser for organizer
+		getUserQuery := regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)
+		dbMock.ExpectQuery(getUserQuery).
+			WithArgs(organizerID).
+			WillReturnRows(sqlmock.NewRows(userColumns).
+				AddRow(organizerID, "Organizer", "organizer@example.com", userCreatedAt, userCreatedAt))
+
+		req := httptest.NewRequest(http.MethodGet, "/api/events/"+eventID.String(), nil)
+		rec := httptest.NewRecorder()
+
+		a.Router().ServeHTTP(rec, req)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var res api.Response
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+		assert.Equal(t, http.StatusOK, res.Status)
+		evt, ok := res.Response.(map[string]any)
+		require.True(t, ok)
+		assert.Equal(t, eventID.String(), evt["id"])
+		assert.Equal(t, "Team Meeting", evt["title"])
+		// Check organizer is included
+		organizer, ok := evt["organizer"].(map[string]any)
+		require.True(t, ok)
+		assert.Equal(t, organizerID.String(), organizer["id"])
+		assert.Equal(t, "Organizer", organizer["name"])
+	})
+
+	t.Run("get event selected fields", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupEventsAPI(t)
+
+		eventID := uuid.New()
+		// No organizer lookup is expected as the organizer was not requested.
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1`)).
+			WithArgs(eventID).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}).
+				AddRow(eventID, "Team Meeting", 2, uuid.New(), []byte("[]"), "UTC", time.Now(), "public"))
+
+		rec := httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/events/"+eventID.String()+"?fields=title,duration_hours", nil))
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		var res api.Response
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+		assert.Equal(t, map[string]any{
+			"id":             eventID.String(),
+			"title":          "Team Meeting",
+			"duration_hours": float64(2),
+		}, res.Response)
+		require.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("get event unknown field", func(t *testing.T) {
+		t.Parallel()
+		a, _ := setupEventsAPI(t)
+
+		rec := httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/events/"+uuid.NewString()+"?fields=id,password", nil))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), `unknown field \"password\"`)
+	})
+
+	t.Run("get event only future slots", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupEventsAPI(t)
+
+		eventID := uuid.New()
+		organizerID := uuid.New()
+		now := time.Now()
+		pastStart := now.Add(-48 * time.Hour).Truncate(time.Second)
+		futureStart := now.Add(48 * time.Hour).Truncate(time.Second)
+		slotsJSON := []byte(`[` +
+			`{"start_time":"` + pastStart.Format(time.RFC3339) + `","end_time":"` + pastStart.Add(time.Hour).Format(time.RFC3339) + `"},` +
+			`{"start_time":"` + futureStart.Format(time.RFC3339) + `","end_time":"` + futureStart.Add(time.Hour).Format(time.RFC3339) + `"}]`)
+
+		for _, tc := range []struct {
+			query string
+			slots int
+		}{
+			{query: "", slots: 2},
+			{query: "?only_future=true", slots: 1},
+		} {
+			dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1`)).
+				WithArgs(eventID).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}).
+					AddRow(eventID, "Team Meeting", 1, organizerID, slotsJSON, "UTC", now, "public"))
+			dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+				WithArgs(organizerID).
+				WillReturnRows(sqlmock.NewRows(userColumns).
+					AddRow(organizerID, "Organizer", "organizer@example.com", userCreatedAt, userCreatedAt))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/events/"+eventID.String()+tc.query, nil)
+			rec := httptest.NewRecorder()
+
+			a.Router().ServeHTTP(rec, req)
+
+			require.Equal(t, http.StatusOK, rec.Code)
+			var res api.Response
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+			evt, ok := res.Response.(map[string]any)
+			require.True(t, ok)
+			slots, ok := evt["slots"].([]any)
+			require.True(t, ok)
+			require.Len(t, slots, tc.slots, tc.query)
+			last := slots[len(slots)-1].(map[string]any)
+			assert.Equal(t, float64(futureStart.Unix()), last["start_time"])
+		}
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("get soft deleted event", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupEventsAPI(t)
+
+		eventID := uuid.New()
+		organizerID := uuid.New()
+		now := time.Now()
+
+		// hidden from normal reads
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1 AND deleted_at IS NULL`)).
+			WithArgs(eventID).
+			WillReturnError(sql.ErrNoRows)
+
+		rec := httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/events/"+eventID.String(), nil))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+
+		// visible with the admin flag
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility, deleted_at FROM events WHERE id = $1`)).
+			WithArgs(eventID).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility", "deleted_at"}).
+				AddRow(eventID, "Team Meeting", 2, organizerID, []byte("[]"), "UTC", now, "public", now))
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+			WithArgs(organizerID).
+			WillReturnRows(sqlmock.NewRows(userColumns).
+				AddRow(organizerID, "Organizer", "organizer@example.com", userCreatedAt, userCreatedAt))
+
+		rec = httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/events/"+eventID.String()+"?include_deleted=true", nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var res api.Response
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+		evt := res.Response.(map[string]any)
+		assert.Equal(t, float64(now.Unix()), evt["deleted_at"])
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("get event not found", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupEventsAPI(t)
+
+		eventID := uuid.New()
+		selectQuery := regexp.QuoteMeta(`SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1`)
+		dbMock.ExpectQuery(selectQuery).
+			WithArgs(eventID).
+			WillReturnError(sql.ErrNoRows)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/events/"+eventID.String(), nil)
+		rec := httptest.NewRecorder()
+
+		a.Router().ServeHTTP(rec, req)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("get event invalid id", func(t *testing.T) {
+		t.Parallel()
+		a, _ := setupEventsAPI(t)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/events/not-a-uuid", nil)
+		rec := httptest.NewRecorder()
+
+		a.Router().ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("update event", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupEventsAPI(t)
+
+		eventID := uuid.New()
+		organizerID := uuid.New()
+		now := time.Now()
+		startTime := now.Add(24 * time.Hour)
+		endTime := startTime.Add(2 * time.Hour)
+
+		slotsJSON := []byte(`[{"start_time":"` + startTime.Format(time.RFC3339) + `","end_time":"` + endTime.Format(time.RFC3339) + `"}]`)
+
+		getQuery := regexp.QuoteMeta(`SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1`)
+		dbMock.ExpectQuery(getQuery).
+			WithArgs(eventID).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}).
+				AddRow(eventID, "Old Title", 2, organizerID, slotsJSON, "UTC", now, "public"))
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+			WithArgs(organizerID).
+			WillReturnRows(sqlmock.NewRows(userColumns).
+				AddRow(organizerID, "Organizer", "organizer@example.com", userCreatedAt, userCreatedAt))
+
+		updateQuery := regexp.QuoteMeta(`UPDATE events SET title = $1, duration_hours = $2, slots = $3, timezone = $4 WHERE id = $5`)
+		dbMock.ExpectExec(updateQuery).
+			WithArgs("Updated Title", 3, sqlmock.AnyArg(), "UTC", eventID).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		// After update, GetEvent is called to return the updated event with original created_at
+		getQueryAfterUpdate := regexp.QuoteMeta(`SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1`)
+		dbMock.ExpectQuery(getQueryAfterUpdate).
+			WithArgs(eventID).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}).
+				AddRow(eventID, "Updated Title", 3, organizerID, slotsJSON, "UTC", now, "public"))
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+			WithArgs(organizerID).
+			WillReturnRows(sqlmock.NewRows(userColumns).
+				AddRow(organizerID, "Organizer", "organizer@example.com", userCreatedAt, userCreatedAt))
+
+		body := map[string]any{
+			"title":          "Updated Title",
+			"duration_hours": 3,
+			"organizer_id":   organizerID.String(),
+			"slots":          []map[string]int64{{"start_time": startTime.Unix(), "end_time": endTime.Unix()}},
+		}
+		bodyBytes, _ := json.Marshal(body)
+		req := httptest.NewRequest(http.MethodPut, "/api/events/"+eventID.String(), bytes.NewBuffer(bodyBytes))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+
+		a.Router().ServeHTTP(rec, req)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var res api.Response
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+		assert.Equal(t, http.StatusOK, res.Status)
+		evt, ok := res.Response.(map[string]any)
+		require.True(t, ok)
+		assert.Equal(t, "Updated Title", evt["title"])
+	})
+
+	t.Run("json patch event", func(t *testing.T) {
+		t.Parallel()
+
+		startTime := time.Date(2030, 1, 2, 9, 0, 0, 0, time.UTC)
+		endTime := startTime.Add(2 * time.Hour)
+		slotsJSON := []byte(`[{"start_time":"` + startTime.Format(time.RFC3339) + `","end_time":"` + endTime.Format(time.RFC3339) + `"}]`)
+		newStart := startTime.Add(24 * time.Hour).Unix()
+		newEnd := endTime.Add(24 * time.Hour).Unix()
+
+		for _, tc := range []struct {
+			name  string
+			patch string
+			title string
+			slots slotsArg
+		}{
+			{
+				name:  "add slot",
+				patch: fmt.Sprintf(`[{"op":"add","path":"/slots/-","value":{"start_time":%d,"end_time":%d}}]`, newStart, newEnd),
+				title: "Team Meeting",
+				slots: slotsArg{{startTime.Unix(), endTime.Unix()}, {newStart, newEnd}},
+			},
+			{
+				name:  "replace title",
+				patch: `[{"op":"test","path":"/title","value":"Team Meeting"},{"op":"replace","path":"/title","value":"Standup"}]`,
+				title: "Standup",
+				slots: slotsArg{{startTime.Unix(), endTime.Unix()}},
+			},
+			{
+				name: "mark slot all day",
+				patch: fmt.Sprintf(`[{"op":"replace","path":"/slots/0/start_time","value":%d},{"op":"replace","path":"/slots/0/end_time","value":%d},{"op":"add","path":"/slots/0/all_day","value":true}]`,
+					startTime.Truncate(24*time.Hour).Unix(), startTime.Truncate(24*time.Hour).Add(24*time.Hour).Unix()),
+				title: "Team Meeting",
+				slots: slotsArg{{startTime.Truncate(24 * time.Hour).Unix(), startTime.Truncate(24 * time.Hour).Add(24 * time.Hour).Unix()}},
+			},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+				a, dbMock := setupEventsAPI(t)
+
+				eventID := uuid.New()
+				organizerID := uuid.New()
+				getQuery := regexp.QuoteMeta(`SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1`)
+				dbMock.ExpectQuery(getQuery).
+					WithArgs(eventID).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}).
+						AddRow(eventID, "Team Meeting", 2, organizerID, slotsJSON, "UTC", time.Now(), "public"))
+				dbMock.ExpectExec(regexp.QuoteMeta(`UPDATE events SET title = $1, duration_hours = $2, slots = $3, timezone = $4 WHERE id = $5`)).
+					WithArgs(tc.title, 2, tc.slots, "UTC", eventID).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				dbMock.ExpectQuery(getQuery).
+					WithArgs(eventID).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}).
+						AddRow(eventID, tc.title, 2, organizerID, slotsJSON, "UTC", time.Now(), "public"))
+				dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+					WithArgs(organizerID).
+					WillReturnRows(sqlmock.NewRows(userColumns).
+						AddRow(organizerID, "Organizer", "organizer@example.com", userCreatedAt, userCreatedAt))
+
+				req := httptest.NewRequest(http.MethodPatch, "/api/events/"+eventID.String(), strings.NewReader(tc.patch))
+				req.Header.Set("Content-Type", "application/json-patch+json")
+				rec := httptest.NewRecorder()
+
+				a.Router().ServeHTTP(rec, req)
+
+				require.NoError(t, dbMock.ExpectationsWereMet())
+				require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+			})
+		}
+	})
+
+	t.Run("json patch event rejected", func(t *testing.T) {
+		t.Parallel()
+
+		for _, tc := range []struct {
+			name        string
+			contentType string
+			patch       string
+			status      int
+		}{
+			{name: "plain json", contentType: "application/json", patch: `[]`, status: http.StatusUnsupportedMediaType},
+			{name: "unsupported path", patch: `[{"op":"replace","path":"/organizer_id","value":"x"}]`, status: http.StatusBadRequest},
+			{name: "unsupported op", patch: `[{"op":"move","from":"/title","path":"/timezone"}]`, status: http.StatusBadRequest},
+			{name: "slot out of range", patch: `[{"op":"remove","path":"/slots/5"}]`, status: http.StatusBadRequest},
+			{name: "invalid result", patch: `[{"op":"replace","path":"/title","value":""}]`, status: http.StatusUnprocessableEntity},
+			{name: "failed test", patch: `[{"op":"test","path":"/title","value":"Other"}]`, status: http.StatusConflict},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+				a, dbMock := setupEventsAPI(t)
+
+				eventID := uuid.New()
+				dbMock.ExpectQuery(regexp.QuoteMeta(`FROM events WHERE id = $1`)).
+					WithArgs(eventID).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}).
+						AddRow(eventID, "Team Meeting", 2, uuid.New(), []byte("[]"), "UTC", time.Now(), "public"))
+
+				contentType := tc.contentType
+				if contentType == "" {
+					contentType = "application/json-patch+json"
+				}
+				req := httptest.NewRequest(http.MethodPatch, "/api/events/"+eventID.String(), strings.NewReader(tc.patch))
+				req.Header.Set("Content-Type", contentType)
+				rec := httptest.NewRecorder()
+
+				a.Router().ServeHTTP(rec, req)
+
+				assert.Equal(t, tc.status, rec.Code, rec.Body.String())
+			})
+		}
+	})
+
+	t.Run("update event not found", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupEventsAPI(t)
+
+		eventID := uuid.New()
+		organizerID := uuid.New()
+		getQuery := regexp.QuoteMeta(`SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1`)
+		dbMock.ExpectQuery(getQuery).
+			WithArgs(eventID).
+			WillReturnError(sql.ErrNoRows)
+
+		body := `{"title":"Updated","duration_hours":2,"organizer_id":"` + organizerID.String() + `","slots":[]}`
+		req := httptest.NewRequest(http.MethodPut, "/api/events/"+eventID.String(), bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+
+		a.Router().ServeHTTP(rec, req)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("update event organizer not found", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupEventsAPI(t)
+
+		eventID := uuid.New()
+		organizerID := uuid.New()
+		startTime := time.Date(2030, 1, 2, 9, 0, 0, 0, time.UTC)
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1`)).
+			WithArgs(eventID).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}).
+				AddRow(eventID, "Old Title", 1, uuid.New(), []byte("[]"), "UTC", time.Now(), "public"))
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+			WithArgs(organizerID).
+			WillReturnError(sql.ErrNoRows)
+
+		body := fmt.Sprintf(`{"title":"Updated","duration_hours":1,"organizer_id":%q,"slots":[{"start_time":%d,"end_time":%d}]}`,
+			organizerID, startTime.Unix(), startTime.Add(time.Hour).Unix())
+		rec := httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, jsonRequest(http.MethodPut, "/api/events/"+eventID.String(), strings.NewReader(body)))
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		assert.JSONEq(t, `{"status":422,"response":"organizer not found"}`, rec.Body.String())
+	})
+
+	t.Run("delete event", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupEventsAPI(t)
+
+		eventID := uuid.New()
+		organizerID := uuid.New()
+		now := time.Now()
+
+		getQuery := regexp.QuoteMeta(`SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1`)
+		dbMock.ExpectQuery(getQuery).
+			WithArgs(eventID).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}).
+				AddRow(eventID, "Event", 2, organizerID, []byte("[]"), "UTC", now, "public"))
+
+		deleteQuery := regexp.QuoteMeta(`UPDATE events SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL`)
+		dbMock.ExpectExec(deleteQuery).
+			WithArgs(sqlmock.AnyArg(), eventID).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		req := httptest.NewRequest(http.MethodDelete, "/api/events/"+eventID.String(), nil)
+		rec := httptest.NewRecorder()
+
+		a.Router().ServeHTTP(rec, req)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+	})
+
+	t.Run("delete event not found", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupEventsAPI(t)
+
+		eventID := uuid.New()
+		getQuery := regexp.QuoteMeta(`SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1`)
+		dbMock.ExpectQuery(getQuery).
+			WithArgs(eventID).
+			WillReturnError(sql.ErrNoRows)
+
+		req := httptest.NewRequest(http.MethodDelete, "/api/events/"+eventID.String(), nil)
+		rec := httptest.NewRecorder()
+
+		a.Router().ServeHTTP(rec, req)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("duplicate event", func(t *testing.T) {
+		t.Parallel()
+
+		startTime := time.Date(2030, 1, 2, 9, 0, 0, 0, time.UTC)
+		midnight := startTime.Truncate(24 * time.Hour)
+		for _, tc := range []struct {
+			name  string
+			body  string
+			slot  event.Slot
+			shift time.Duration
+		}{
+			{name: "plain", body: "", slot: event.Slot{StartTime: startTime, EndTime: startTime.Add(2 * time.Hour)}, shift: 0},
+			{name: "shifted", body: `{"shift_hours": 168}`, slot: event.Slot{StartTime: startTime, EndTime: startTime.Add(2 * time.Hour)}, shift: 168 * time.Hour},
+			{name: "all day", body: `{"shift_hours": 24}`, slot: event.Slot{StartTime: midnight, EndTime: midnight.Add(24 * time.Hour), Label: "Offsite", AllDay: true}, shift: 24 * time.Hour},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+				a, dbMock := setupEventsAPI(t)
+
+				eventID := uuid.New()
+				organizerID := uuid.New()
+				shifted := tc.slot
+				shifted.StartTime = shifted.StartTime.Add(tc.shift)
+				shifted.EndTime = shifted.EndTime.Add(tc.shift)
+				slotsJSON, _ := event.SlotsColumn([]event.Slot{tc.slot}).Value()
+				// The copy keeps every slot field, only the bounds move.
+				shiftedJSON, _ := event.SlotsColumn([]event.Slot{shifted}).Value()
+
+				dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1`)).
+					WithArgs(eventID).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}).
+						AddRow(eventID, "Team Meeting", 2, organizerID, slotsJSON, "UTC", time.Now(), "private"))
+				// The copy stays as private as the original.
+				dbMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO events (id, title, duration_hours, user_id, slots, timezone, created_at, visibility) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`)).
+					WithArgs(sqlmock.AnyArg(), "Team Meeting", 2, organizerID, shiftedJSON, "UTC", sqlmock.AnyArg(), "private").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+					WithArgs(organizerID).
+					WillReturnRows(sqlmock.NewRows(userColumns).
+						AddRow(organizerID, "Organizer", "organizer@example.com", userCreatedAt, userCreatedAt))
+
+				req := httptest.NewRequest(http.MethodPost, "/api/events/"+eventID.String()+"/duplicate", strings.NewReader(tc.body))
+				req.Header.Set("Content-Type", "application/json")
+				rec := httptest.NewRecorder()
+
+				a.Router().ServeHTTP(rec, req)
+
+				require.NoError(t, dbMock.ExpectationsWereMet())
+				assert.Equal(t, http.StatusCreated, rec.Code)
+
+				var res api.Response
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+				evt := res.Response.(map[string]any)
+				assert.NotEqual(t, eventID.String(), evt["id"])
+				assert.Equal(t, "/api/events/"+evt["id"].(string), rec.Header().Get("Location"))
+				assert.Equal(t, "Team Meeting", evt["title"])
+				assert.Equal(t, "private", evt["visibility"])
+				slots := evt["slots"].([]any)
+				require.Len(t, slots, 1)
+				assert.Equal(t, float64(shifted.StartTime.Unix()), slots[0].(map[string]any)["start_time"])
+			})
+		}
+	})
+
+	t.Run("duplicate event with invalid shifted slots", func(t *testing.T) {
+		t.Parallel()
+
+		midnight := time.Date(2030, 1, 2, 0, 0, 0, 0, time.UTC)
+		startTime := midnight.Add(9 * time.Hour)
+		for _, tc := range []struct {
+			name  string
+			body  string
+			slot  event.Slot
+			field string
+		}{
+			{name: "all day off midnight", body: `{"shift_hours": 12}`, slot: event.Slot{StartTime: midnight, EndTime: midnight.Add(24 * time.Hour), AllDay: true}, field: "slots[0].all_day"},
+			{name: "past 2100", body: `{"shift_hours": 1000000}`, slot: event.Slot{StartTime: startTime, EndTime: startTime.Add(2 * time.Hour)}},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+				a, dbMock := setupEventsAPI(t)
+
+				eventID := uuid.New()
+				slotsJSON, _ := event.SlotsColumn([]event.Slot{tc.slot}).Value()
+				dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1`)).
+					WithArgs(eventID).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}).
+						AddRow(eventID, "Team Meeting", 2, uuid.New(), slotsJSON, "UTC", time.Now(), "public"))
+
+				rec := httptest.NewRecorder()
+				a.Router().ServeHTTP(rec, jsonRequest(http.MethodPost, "/api/events/"+eventID.String()+"/duplicate", strings.NewReader(tc.body)))
+
+				require.NoError(t, dbMock.ExpectationsWereMet())
+				assert.Equal(t, http.StatusUnprocessableEntity, rec.Code, rec.Body.String())
+				if tc.field != "" {
+					var res struct {
+						Response struct {
+							Errors []validation.FieldError `json:"errors"`
+						} `json:"response"`
+					}
+					require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+					require.NotEmpty(t, res.Response.Errors)
+					assert.Equal(t, tc.field, res.Response.Errors[0].Field)
+				}
+			})
+		}
+	})
+
+	t.Run("duplicate event not found", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupEventsAPI(t)
+
+		eventID := uuid.New()
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1`)).
+			WithArgs(eventID).
+			WillReturnError(sql.ErrNoRows)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/events/"+eventID.String()+"/duplicate", nil)
+		rec := httptest.NewRecorder()
+
+		a.Router().ServeHTTP(rec, req)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("event slots sub-resource", func(t *testing.T) {
+		t.Parallel()
+
+		eventID := uuid.New()
+		organizerID := uuid.New()
+		start := time.Date(2030, 6, 3, 7, 0, 0, 0, time.UTC)
+		current := []event.Slot{{StartTime: start, EndTime: start.Add(time.Hour), Label: "Morning option"}}
+		replaced := []event.Slot{{StartTime: start.Add(24 * time.Hour), EndTime: start.Add(25 * time.Hour)}}
+		selectQuery := regexp.QuoteMeta(`SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1`)
+		// Anchored so that an UPDATE touching any other column fails to match.
+		updateQuery := "^" + regexp.QuoteMeta(`UPDATE events SET slots = $1 WHERE id = $2 AND deleted_at IS NULL`) + "$"
+		eventRows := func(slots []event.Slot) *sqlmock.Rows {
+			stored, err := event.SlotsColumn(slots).Value()
+			require.NoError(t, err)
+			return sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}).
+				AddRow(eventID, "Team Meeting", 1, organizerID, stored, "Europe/Berlin", time.Now(), "public")
+		}
+		slotsOf := func(rec *httptest.ResponseRecorder) []any {
+			var res api.Response
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+			return res.Response.(map[string]any)["slots"].([]any)
+		}
+
+		t.Run("get", func(t *testing.T) {
+			t.Parallel()
+			a, dbMock := setupEventsAPI(t)
+
+			dbMock.ExpectQuery(selectQuery).WithArgs(eventID).WillReturnRows(eventRows(current))
+
+			rec := httptest.NewRecorder()
+			a.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/events/"+eventID.String()+"/slots", nil))
+
+			require.NoError(t, dbMock.ExpectationsWereMet())
+			require.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, []any{map[string]any{
+				"start_time":  float64(start.Unix()),
+				"end_time":    float64(start.Add(time.Hour).Unix()),
+				"label":       "Morning option",
+				"start_local": "2030-06-03T09:00:00+02:00",
+				"end_local":   "2030-06-03T10:00:00+02:00",
+			}}, slotsOf(rec))
+		})
+
+		t.Run("replace", func(t *testing.T) {
+			t.Parallel()
+			a, dbMock := setupEventsAPI(t)
+
+			dbMock.ExpectExec(updateQuery).
+				WithArgs(event.SlotsColumn(replaced), eventID).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			dbMock.ExpectQuery(selectQuery).WithArgs(eventID).WillReturnRows(eventRows(replaced))
+
+			body := fmt.Sprintf(`{"slots":[{"start_time":%d,"end_time":%d}]}`, replaced[0].StartTime.Unix(), replaced[0].EndTime.Unix())
+			rec := httptest.NewRecorder()
+			a.Router().ServeHTTP(rec, jsonRequest(http.MethodPut, "/api/events/"+eventID.String()+"/slots", strings.NewReader(body)))
+
+			require.NoError(t, dbMock.ExpectationsWereMet())
+			require.Equal(t, http.StatusOK, rec.Code)
+			slots := slotsOf(rec)
+			require.Len(t, slots, 1)
+			assert.Equal(t, float64(replaced[0].StartTime.Unix()), slots[0].(map[string]any)["start_time"])
+		})
+
+		t.Run("replace with invalid slot", func(t *testing.T) {
+			t.Parallel()
+			a, dbMock := setupEventsAPI(t)
+
+			body := fmt.Sprintf(`{"slots":[{"start_time":%d,"end_time":%d}]}`, start.Unix(), start.Add(-time.Hour).Unix())
+			rec := httptest.NewRecorder()
+			a.Router().ServeHTTP(rec, jsonRequest(http.MethodPut, "/api/events/"+eventID.String()+"/slots", strings.NewReader(body)))
+
+			require.NoError(t, dbMock.ExpectationsWereMet())
+			assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+			assert.JSONEq(t, `{"status":422,"response":{"error":"slot 0: start time is after end time","errors":[
+				{"field":"slots[0].end_time","message":"slot 0: start time is after end time"}
+			]}}`, rec.Body.String())
+		})
+
+		t.Run("clear", func(t *testing.T) {
+			t.Parallel()
+			a, dbMock := setupEventsAPI(t)
+
+			dbMock.ExpectExec(updateQuery).
+				WithArgs(event.SlotsColumn(nil), eventID).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			dbMock.ExpectQuery(selectQuery).WithArgs(eventID).WillReturnRows(eventRows(nil))
+
+			rec := httptest.NewRecorder()
+			a.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/api/events/"+eventID.String()+"/slots", nil))
+
+			require.NoError(t, dbMock.ExpectationsWereMet())
+			require.Equal(t, http.StatusOK, rec.Code)
+			assert.Empty(t, slotsOf(rec))
+		})
+
+		t.Run("event not found", func(t *testing.T) {
+			t.Parallel()
+			a, dbMock := setupEventsAPI(t)
+
+			dbMock.ExpectExec(updateQuery).
+				WithArgs(event.SlotsColumn(nil), eventID).
+				WillReturnResult(sqlmock.NewResult(0, 0))
+
+			rec := httptest.NewRecorder()
+			a.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/api/events/"+eventID.String()+"/slots", nil))
+
+			require.NoError(t, dbMock.ExpectationsWereMet())
+			assert.Equal(t, http.StatusNotFound, rec.Code)
+		})
+	})
+
+	t.Run("get possible event slot", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupEventsAPI(t)
+
+		eventID := uuid.New()
+		organizerID := uuid.New()
+		now := time.Now()
+		startTime := now.Add(24 * time.Hour)
+		endTime := startTime.Add(2 * time.Hour)
+		slotsJSON := []byte(`[{"start_time":"` + startTime.Format(time.RFC3339) + `","end_time":"` + endTime.Format(time.RFC3339) + `"}]`)
+
+		getEventQuery := regexp.QuoteMeta(`SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1`)
+		dbMock.ExpectQuery(getEventQuery).
+			WithArgs(eventID).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}).
+				AddRow(eventID, "Event", 2, organizerID, slotsJSON, "UTC", now, "public"))
+
+		getUsersQuery := regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users`)
+		userID := uuid.New()
+		dbMock.ExpectQuery(getUsersQuery).
+			WillReturnRows(sqlmock.NewRows(userColumns).
+				AddRow(userID, "Alice", "alice@example.com", userCreatedAt, userCreatedAt))
+
+		dbMock.ExpectQuery(regexp.QuoteMeta(`FROM events WHERE user_id = $1 AND deleted_at IS NULL`)).
+			WithArgs(organizerID, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}))
+
+		getUsersForSlotQuery := `SELECT users\.id, users\.name, users\.email`
+		dbMock.ExpectQuery(getUsersForSlotQuery).
+			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), 2).
+			WillReturnRows(sqlmock.NewRows(userColumns).
+				AddRow(userID, "Alice", "alice@example.com", userCreatedAt, userCreatedAt))
+		dbMock.ExpectQuery(`FROM users_recurring_availability`).
+			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "created_at", "updated_at", "weekday", "start_minute", "end_minute", "valid_from", "valid_until"}))
+
+		// The event read for the search is reused, so it is not read again. The organizer is free
+		// from an hour before the slot until an hour into it, and again next week.
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT start_time, end_time FROM users_availability WHERE user_id = $1`)).
+			WithArgs(organizerID).
+			WillReturnRows(sqlmock.NewRows([]string{"start_time", "end_time"}).
+				AddRow(startTime.Add(-time.Hour), startTime.Add(time.Hour)).
+				AddRow(startTime.Add(7*24*time.Hour), endTime.Add(7*24*time.Hour)))
+
+		req := httptest.NewRequest(http.MethodGet, "/api/events/"+eventID.String()+"/possible-slot", nil)
+		rec := httptest.NewRecorder()
+
+		a.Router().ServeHTTP(rec, req)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var res api.Response
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+		assert.Equal(t, http.StatusOK, res.Status)
+		possible, ok := res.Response.(map[string]any)
+		require.True(t, ok)
+		assert.Contains(t, possible, "slot")
+		assert.Contains(t, possible, "users")
+		assert.Contains(t, possible, "not_working_users")
+		assert.Equal(t, []any{
+			map[string]any{"start_time": float64(startTime.Unix()), "end_time": float64(startTime.Add(time.Hour).Unix())},
+		}, possible["organizer_availability"])
+	})
+
+	t.Run("get attendance summary", func(t *testing.T) {
+		t.Parallel()
+
+		for _, tc := range []struct {
+			name       string
+			available  []string
+			attending  float64
+			notWorking []any
+		}{
+			{name: "full attendance", available: []string{"Alice", "Bob"}, attending: 2, notWorking: []any{}},
+			{name: "partial attendance", available: []string{"Alice"}, attending: 1, notWorking: []any{"Bob"}},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+				a, dbMock := setupEventsAPI(t)
+
+				eventID := uuid.New()
+				organizerID := uuid.New()
+				startTime := time.Date(2030, 1, 2, 9, 0, 0, 0, time.UTC)
+				slotsJSON, _ := event.SlotsColumn([]event.Slot{{StartTime: startTime, EndTime: startTime.Add(2 * time.Hour)}}).Value()
+				ids := map[string]uuid.UUID{"Alice": uuid.New(), "Bob": uuid.New()}
+
+				dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1`)).
+					WithArgs(eventID).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}).
+						AddRow(eventID, "Event", 2, organizerID, slotsJSON, "UTC", time.Now(), "public"))
+				dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users`)).
+					WillReturnRows(sqlmock.NewRows(userColumns).
+						AddRow(ids["Alice"], "Alice", "alice@example.com", userCreatedAt, userCreatedAt).
+						AddRow(ids["Bob"], "Bob", "bob@example.com", userCreatedAt, userCreatedAt))
+				dbMock.ExpectQuery(regexp.QuoteMeta(`FROM events WHERE user_id = $1 AND deleted_at IS NULL`)).
+					WithArgs(organizerID, sqlmock.AnyArg(), sqlmock.AnyArg()).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}))
+				rows := sqlmock.NewRows(userColumns)
+				for _, name := range tc.available {
+					rows.AddRow(ids[name], name, strings.ToLower(name)+"@example.com", userCreatedAt, userCreatedAt)
+				}
+				dbMock.ExpectQuery(`SELECT users\.id, users\.name, users\.email`).
+					WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), 2).
+					WillReturnRows(rows)
+				dbMock.ExpectQuery(`FROM users_recurring_availability`).
+					WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg()).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "created_at", "updated_at", "weekday", "start_minute", "end_minute", "valid_from", "valid_until"}))
+
+				req := httptest.NewRequest(http.MethodGet, "/api/events/"+eventID.String()+"/attendance-summary", nil)
+				rec := httptest.NewRecorder()
+
+				a.Router().ServeHTTP(rec, req)
+
+				require.NoError(t, dbMock.ExpectationsWereMet())
+				assert.Equal(t, http.StatusOK, rec.Code)
+
+				var res api.Response
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+				summary := res.Response.(map[string]any)
+				assert.Equal(t, tc.attending, summary["attending_count"])
+				assert.Equal(t, float64(2), summary["total_users"])
+				assert.Equal(t, float64(startTime.Unix()), summary["best_slot"].(map[string]any)["start_time"])
+				names := []any{}
+				for _, u := range summary["not_working"].([]any) {
+					names = append(names, u.(map[string]any)["name"])
+				}
+				assert.Equal(t, tc.notWorking, names)
+			})
+		}
+	})
+
+	t.Run("get attendance summary not found", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupEventsAPI(t)
+
+		eventID := uuid.New()
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1`)).
+			WithArgs(eventID).
+			WillReturnError(sql.ErrNoRows)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/events/"+eventID.String()+"/attendance-summary", nil)
+		rec := httptest.NewRecorder()
+
+		a.Router().ServeHTTP(rec, req)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("get slot availability", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupEventsAPI(t)
+
+		eventID := uuid.New()
+		startTime := time.Date(2030, 1, 2, 9, 0, 0, 0, time.UTC)
+		slots := []event.Slot{
+			{StartTime: startTime, EndTime: startTime.Add(2 * time.Hour)},
+			{StartTime: startTime.Add(24 * time.Hour), EndTime: startTime.Add(26 * time.Hour)},
+		}
+		slotsJSON, _ := event.SlotsColumn(slots).Value()
+		alice, bob, carol := uuid.New(), uuid.New(), uuid.New()
+
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1`)).
+			WithArgs(eventID).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}).
+				AddRow(eventID, "Event", 2, alice, slotsJSON, "UTC", time.Now(), "public"))
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users`)).
+			WillReturnRows(sqlmock.NewRows(userColumns).
+				AddRow(alice, "Alice", "alice@example.com", userCreatedAt, userCreatedAt).
+				AddRow(bob, "Bob", "bob@example.com", userCreatedAt, userCreatedAt).
+				AddRow(carol, "Carol", "carol@example.com", userCreatedAt, userCreatedAt))
+		for _, available := range [][]uuid.UUID{{alice, bob}, {carol}} {
+			rows := sqlmock.NewRows(userColumns)
+			for _, id := range available {
+				rows.AddRow(id, "User", "user@example.com", userCreatedAt, userCreatedAt)
+			}
+			dbMock.ExpectQuery(`SELECT users\.id, users\.name, users\.email`).
+				WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), 2).
+				WillReturnRows(rows)
+			dbMock.ExpectQuery(`FROM users_recurring_availability`).
+				WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg()).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "created_at", "updated_at", "weekday", "start_minute", "end_minute", "valid_from", "valid_until"}))
+		}
+
+		req := httptest.NewRequest(http.MethodGet, "/api/events/"+eventID.String()+"/slot-availability", nil)
+		rec := httptest.NewRecorder()
+
+		a.Router().ServeHTTP(rec, req)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var res api.Response
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+		assert.Equal(t, []any{
+			map[string]any{
+				"slot":              map[string]any{"start_time": float64(slots[0].StartTime.Unix()), "end_time": float64(slots[0].EndTime.Unix())},
+				"available_count":   float64(2),
+				"not_working_count": float64(1),
+			},
+			map[string]any{
+				"slot":              map[string]any{"start_time": float64(slots[1].StartTime.Unix()), "end_time": float64(slots[1].EndTime.Unix())},
+				"available_count":   float64(1),
+				"not_working_count": float64(2),
+			},
+		}, res.Response)
+	})
+
+	t.Run("get possible event slot for unknown user", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupEventsAPI(t)
+
+		userID := uuid.New()
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = ANY($1)`)).
+			WillReturnRows(sqlmock.NewRows(userColumns))
+
+		req := httptest.NewRequest(http.MethodGet, "/api/events/"+uuid.NewString()+"/possible-slot?user_ids="+userID.String(), nil)
+		rec := httptest.NewRecorder()
+
+		a.Router().ServeHTTP(rec, req)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("get possible event slot pages the users", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupEventsAPI(t)
+
+		eventID, organizerID := uuid.New(), uuid.New()
+		now := time.Now()
+		startTime := now.Add(24 * time.Hour)
+		endTime := startTime.Add(2 * time.Hour)
+		slotsJSON := []byte(`[{"start_time":"` + startTime.Format(time.RFC3339) + `","end_time":"` + endTime.Format(time.RFC3339) + `"}]`)
+		eventColumns := []string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}
+		getEventQuery := regexp.QuoteMeta(`SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1`)
+
+		users := sqlmock.NewRows(userColumns)
+		names := []string{"Alice", "Bob", "Carol", "Dave", "Erin"}
+		ids := make([]uuid.UUID, len(names))
+		for i, name := range names {
+			ids[i] = uuid.New()
+			users.AddRow(ids[i], name, strings.ToLower(name)+"@example.com", userCreatedAt, userCreatedAt)
+		}
+		available := sqlmock.NewRows(userColumns)
+		for i, name := range names {
+			available.AddRow(ids[i], name, strings.ToLower(name)+"@example.com", userCreatedAt, userCreatedAt)
+		}
+
+		dbMock.ExpectQuery(getEventQuery).
+			WithArgs(eventID).
+			WillReturnRows(sqlmock.NewRows(eventColumns).AddRow(eventID, "Event", 2, organizerID, slotsJSON, "UTC", now, "public"))
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users`)).
+			WillReturnRows(users)
+		dbMock.ExpectQuery(regexp.QuoteMeta(`FROM events WHERE user_id = $1 AND deleted_at IS NULL`)).
+			WithArgs(organizerID, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows(eventColumns))
+		dbMock.ExpectQuery(`SELECT users\.id, users\.name, users\.email`).
+			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), 2).
+			WillReturnRows(available)
+		dbMock.ExpectQuery(`FROM users_recurring_availability`).
+			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "created_at", "updated_at", "weekday", "start_minute", "end_minute", "valid_from", "valid_until"}))
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT start_time, end_time FROM users_availability WHERE user_id = $1`)).
+			WithArgs(organizerID).
+			WillReturnRows(sqlmock.NewRows([]string{"start_time", "end_time"}))
+
+		rec := httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/events/"+eventID.String()+"/possible-slot?limit=2&offset=1", nil))
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var res api.Response
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+		possible := res.Response.(map[string]any)
+		assert.Equal(t, float64(5), possible["users_total"])
+		page := possible["users"].([]any)
+		require.Len(t, page, 2)
+		assert.Equal(t, "Bob", page[0].(map[string]any)["name"])
+		assert.Equal(t, "Carol", page[1].(map[string]any)["name"])
+	})
+
+	t.Run("get possible event slot invalid limit", func(t *testing.T) {
+		t.Parallel()
+		a, _ := setupEventsAPI(t)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/events/"+uuid.NewString()+"/possible-slot?limit=0", nil)
+		rec := httptest.NewRecorder()
+
+		a.Router().ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("get possible event slot invalid mode", func(t *testing.T) {
+		t.Parallel()
+		a, _ := setupEventsAPI(t)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/events/"+uuid.NewString()+"/possible-slot?mode=fuzzy", nil)
+		rec := httptest.NewRecorder()
+
+		a.Router().ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("get possible event slot invalid id", func(t *testing.T) {
+		t.Parallel()
+		a, _ := setupEventsAPI(t)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/events/bad-id/possible-slot", nil)
+		rec := httptest.NewRecorder()
+
+		a.Router().ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("preview possible slot", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupEventsAPI(t)
+
+		startTime := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+		alice, bob := uuid.New(), uuid.New()
+
+		// Nothing is read or written for the event itself, and without an organizer there are
+		// no conflicts to look up.
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users`)).
+			WillReturnRows(sqlmock.NewRows(userColumns).
+				AddRow(alice, "Alice", "alice@example.com", userCreatedAt, userCreatedAt).
+				AddRow(bob, "Bob", "bob@example.com", userCreatedAt, userCreatedAt))
+		for _, available := range [][]uuid.UUID{{alice}, {alice, bob}} {
+			rows := sqlmock.NewRows(userColumns)
+			for _, id := range available {
+				rows.AddRow(id, "User", "user@example.com", userCreatedAt, userCreatedAt)
+			}
+			dbMock.ExpectQuery(`SELECT users\.id, users\.name, users\.email`).
+				WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), 2).
+				WillReturnRows(rows)
+			dbMock.ExpectQuery(`FROM users_recurring_availability`).
+				WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg()).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "created_at", "updated_at", "weekday", "start_minute", "end_minute", "valid_from", "valid_until"}))
+		}
+
+		body := fmt.Sprintf(`{"duration_hours":2,"slots":[{"start_time":%d,"end_time":%d},{"start_time":%d,"end_time":%d}]}`,
+			startTime.Unix(), startTime.Add(2*time.Hour).Unix(), startTime.Add(24*time.Hour).Unix(), startTime.Add(26*time.Hour).Unix())
+		rec := httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, jsonRequest(http.MethodPost, "/api/events/possible-slot", strings.NewReader(body)))
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		var res api.Response
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+		possible := res.Response.(map[string]any)
+		assert.Equal(t, float64(startTime.Add(24*time.Hour).Unix()), possible["slot"].(map[string]any)["start_time"])
+		assert.Len(t, possible["users"], 2)
+		assert.Empty(t, possible["not_working_users"])
+	})
+
+	t.Run("preview possible slot demotes the organizer's conflicts", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupEventsAPI(t)
+
+		startTime := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+		organizerID, alice := uuid.New(), uuid.New()
+		eventColumns := []string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}
+		otherSlotsJSON, _ := event.SlotsColumn([]event.Slot{{StartTime: startTime, EndTime: startTime.Add(2 * time.Hour)}}).Value()
+
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+			WithArgs(organizerID).
+			WillReturnRows(sqlmock.NewRows(userColumns).AddRow(organizerID, "Olivia", "olivia@example.com", userCreatedAt, userCreatedAt))
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users`)).
+			WillReturnRows(sqlmock.NewRows(userColumns).AddRow(alice, "Alice", "alice@example.com", userCreatedAt, userCreatedAt))
+		// The organizer already has an event during the first slot.
+		dbMock.ExpectQuery(regexp.QuoteMeta(`FROM events WHERE user_id = $1 AND deleted_at IS NULL`)).
+			WithArgs(organizerID, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows(eventColumns).AddRow(uuid.New(), "Other", 2, organizerID, otherSlotsJSON, "UTC", time.Now(), "public"))
+		dbMock.ExpectQuery(regexp.QuoteMeta(`FROM events WHERE user_id = $1 AND deleted_at IS NULL`)).
+			WithArgs(organizerID, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows(eventColumns))
+		dbMock.ExpectQuery(`SELECT users\.id, users\.name, users\.email`).
+			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), 2).
+			WillReturnRows(sqlmock.NewRows(userColumns).AddRow(alice, "Alice", "alice@example.com", userCreatedAt, userCreatedAt))
+		dbMock.ExpectQuery(`FROM users_recurring_availability`).
+			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "created_at", "updated_at", "weekday", "start_minute", "end_minute", "valid_from", "valid_until"}))
+
+		body := fmt.Sprintf(`{"duration_hours":2,"organizer_id":%q,"slots":[{"start_time":%d,"end_time":%d},{"start_time":%d,"end_time":%d}]}`,
+			organizerID, startTime.Unix(), startTime.Add(2*time.Hour).Unix(), startTime.Add(24*time.Hour).Unix(), startTime.Add(26*time.Hour).Unix())
+		rec := httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, jsonRequest(http.MethodPost, "/api/events/possible-slot", strings.NewReader(body)))
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		var res api.Response
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+		assert.Equal(t, float64(startTime.Add(24*time.Hour).Unix()), res.Response.(map[string]any)["slot"].(map[string]any)["start_time"])
+	})
+
+	t.Run("preview possible slot with no one available", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupEventsAPI(t)
+
+		startTime := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+		alice := uuid.New()
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = ANY($1)`)).
+			WillReturnRows(sqlmock.NewRows(userColumns).AddRow(alice, "Alice", "alice@example.com", userCreatedAt, userCreatedAt))
+		dbMock.ExpectQuery(`SELECT users\.id, users\.name, users\.email`).
+			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), 2, sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows(userColumns))
+		dbMock.ExpectQuery(`FROM users_recurring_availability`).
+			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "created_at", "updated_at", "weekday", "start_minute", "end_minute", "valid_from", "valid_until"}))
+
+		body := fmt.Sprintf(`{"duration_hours":2,"user_ids":[%q],"slots":[{"start_time":%d,"end_time":%d}]}`,
+			alice, startTime.Unix(), startTime.Add(2*time.Hour).Unix())
+		rec := httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, jsonRequest(http.MethodPost, "/api/events/possible-slot", strings.NewReader(body)))
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		assert.Equal(t, http.StatusNotFound, rec.Code, rec.Body.String())
+	})
+
+	t.Run("preview possible slot reports every invalid field", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupEventsAPI(t)
+
+		start := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+		body := fmt.Sprintf(`{"duration_hours":0,"slots":[{"start_time":%d,"end_time":%d}]}`, start.Unix(), start.Add(-time.Hour).Unix())
+		rec := httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, jsonRequest(http.MethodPost, "/api/events/possible-slot", strings.NewReader(body)))
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		assert.JSONEq(t, `{"status":422,"response":{
+			"error":"duration hours must be greater than 0; slot 0: start time is after end time",
+			"errors":[
+				{"field":"duration_hours","message":"duration hours must be greater than 0"},
+				{"field":"slots[0].end_time","message":"slot 0: start time is after end time"}
+			]}}`, rec.Body.String())
+	})
+
+	t.Run("preview possible slot rejects a bad payload", func(t *testing.T) {
+		t.Parallel()
+
+		for _, tc := range []struct {
+			name string
+			body string
+			code int
+		}{
+			{name: "no slots", body: `{"duration_hours":2,"slots":[]}`, code: http.StatusUnprocessableEntity},
+			{name: "invalid organizer", body: `{"duration_hours":2,"organizer_id":"bad","slots":[{"start_time":1893488400,"end_time":1893495600}]}`, code: http.StatusUnprocessableEntity},
+			{name: "invalid user", body: `{"duration_hours":2,"user_ids":["bad"],"slots":[{"start_time":1893488400,"end_time":1893495600}]}`, code: http.StatusBadRequest},
+			{name: "malformed", body: `{"duration_hours":`, code: http.StatusBadRequest},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+				a, dbMock := setupEventsAPI(t)
+
+				rec := httptest.NewRecorder()
+				a.Router().ServeHTTP(rec, jsonRequest(http.MethodPost, "/api/events/possible-slot", strings.NewReader(tc.body)))
+
+				require.NoError(t, dbMock.ExpectationsWereMet())
+				assert.Equal(t, tc.code, rec.Code, rec.Body.String())
+			})
+		}
+	})
+
+	t.Run("batch possible slot", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupEventsAPI(t)
+
+		planning, review, organizerID := uuid.New(), uuid.New(), uuid.New()
+		alice, bob := uuid.New(), uuid.New()
+		startTime := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+		slotsJSON, _ := event.SlotsColumn([]event.Slot{{StartTime: startTime, EndTime: startTime.Add(2 * time.Hour)}}).Value()
+		eventColumns := []string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}
+		getEventQuery := regexp.QuoteMeta(`SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1`)
+
+		dbMock.ExpectQuery(getEventQuery).
+			WithArgs(planning).
+			WillReturnRows(sqlmock.NewRows(eventColumns).AddRow(planning, "Planning", 2, organizerID, slotsJSON, "UTC", time.Now(), "public"))
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users`)).
+			WillReturnRows(sqlmock.NewRows(userColumns).
+				AddRow(alice, "Alice", "alice@example.com", userCreatedAt, userCreatedAt).
+				AddRow(bob, "Bob", "bob@example.com", userCreatedAt, userCreatedAt))
+		dbMock.ExpectQuery(regexp.QuoteMeta(`FROM events WHERE user_id = $1 AND deleted_at IS NULL`)).
+			WithArgs(organizerID, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows(eventColumns))
+		dbMock.ExpectQuery(`SELECT users\.id, users\.name, users\.email`).
+			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), 2).
+			WillReturnRows(sqlmock.NewRows(userColumns).AddRow(alice, "Alice", "alice@example.com", userCreatedAt, userCreatedAt))
+		dbMock.ExpectQuery(`FROM users_recurring_availability`).
+			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "created_at", "updated_at", "weekday", "start_minute", "end_minute", "valid_from", "valid_until"}))
+		// The second event proposes the same slot, so neither users nor availability are read again.
+		dbMock.ExpectQuery(getEventQuery).
+			WithArgs(review).
+			WillReturnRows(sqlmock.NewRows(eventColumns).AddRow(review, "Review", 2, organizerID, slotsJSON, "UTC", time.Now(), "public"))
+		dbMock.ExpectQuery(regexp.QuoteMeta(`FROM events WHERE user_id = $1 AND deleted_at IS NULL`)).
+			WithArgs(organizerID, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows(eventColumns))
+
+		body := fmt.Sprintf(`{"event_ids":[%q,%q]}`, planning, review)
+		rec := httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, jsonRequest(http.MethodPost, "/api/events/batch-possible-slot", strings.NewReader(body)))
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		var res struct {
+			Response struct {
+				Results []struct {
+					EventID         uuid.UUID        `json:"event_id"`
+					Slot            *event.Slot      `json:"slot"`
+					Users           []map[string]any `json:"users"`
+					NotWorkingUsers []map[string]any `json:"not_working_users"`
+				} `json:"results"`
+			} `json:"response"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+		require.Len(t, res.Response.Results, 2)
+		for i, id := range []uuid.UUID{planning, review} {
+			result := res.Response.Results[i]
+			assert.Equal(t, id, result.EventID)
+			require.NotNil(t, result.Slot)
+			assert.Equal(t, startTime.Unix(), result.Slot.StartTime.Unix())
+			require.Len(t, result.Users, 1)
+			assert.Equal(t, "Alice", result.Users[0]["name"])
+			require.Len(t, result.NotWorkingUsers, 1)
+			assert.Equal(t, "Bob", result.NotWorkingUsers[0]["name"])
+		}
+	})
+
+	t.Run("batch possible slot event not found", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupEventsAPI(t)
+
+		eventID := uuid.New()
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1`)).
+			WithArgs(eventID).
+			WillReturnError(sql.ErrNoRows)
+
+		rec := httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, jsonRequest(http.MethodPost, "/api/events/batch-possible-slot", strings.NewReader(fmt.Sprintf(`{"event_ids":[%q]}`, eventID))))
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("batch possible slot rejects a bad payload", func(t *testing.T) {
+		t.Parallel()
+
+		tooMany := make([]string, 51)
+		for i := range tooMany {
+			tooMany[i] = fmt.Sprintf("%q", uuid.NewString())
+		}
+		for _, tc := range []struct {
+			name string
+			body string
+			code int
+		}{
+			{name: "no events", body: `{"event_ids":[]}`, code: http.StatusUnprocessableEntity},
+			{name: "too many events", body: `{"event_ids":[` + strings.Join(tooMany, ",") + `]}`, code: http.StatusUnprocessableEntity},
+			{name: "invalid event ID", body: `{"event_ids":["bad"]}`, code: http.StatusBadRequest},
+			{name: "malformed", body: `{"event_ids":`, code: http.StatusBadRequest},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+				a, dbMock := setupEventsAPI(t)
+
+				rec := httptest.NewRecorder()
+				a.Router().ServeHTTP(rec, jsonRequest(http.MethodPost, "/api/events/batch-possible-slot", strings.NewReader(tc.body)))
+
+				require.NoError(t, dbMock.ExpectationsWereMet())
+				assert.Equal(t, tc.code, rec.Code, rec.Body.String())
+			})
+		}
+	})
+
+	t.Run("list events with organizers", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupEventsAPI(t)
+
+		aliceID, bobID := uuid.New(), uuid.New()
+		slotsJSON, _ := event.SlotsColumn([]event.Slot{}).Value()
+		dbMock.ExpectQuery(`FROM events\s+JOIN users ON users\.id = events\.user_id`).
+			WithArgs(20, 0).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "visibility", "created_at", "id", "name", "email"}).
+				AddRow(uuid.New(), "Planning", 1, aliceID, slotsJSON, "UTC", "public", time.Now(), aliceID, "Alice", "alice@example.com").
+				AddRow(uuid.New(), "Retro", 1, bobID, slotsJSON, "UTC", "public", time.Now(), bobID, "Bob", "bob@example.com"))
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM events WHERE deleted_at IS NULL AND events.visibility = 'public'`)).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+
+		rec := httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/events", nil))
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		// A single page has neither a previous nor a next one.
+		assert.Equal(t, `</api/events?limit=20&offset=0>; rel="last"`, rec.Header().Get("Link"))
+		var resp struct {
+			Response struct {
+				Items []struct {
+					OrganizerID string `json:"organizer_id"`
+					Organizer   struct {
+						ID    string `json:"id"`
+						Name  string `json:"name"`
+						Email string `json:"email"`
+					} `json:"organizer"`
+				} `json:"items"`
+				Total int `json:"total"`
+			} `json:"response"`
+		}
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+		require.Len(t, resp.Response.Items, 2)
+		assert.Equal(t, 2, resp.Response.Total)
+		for _, evt := range resp.Response.Items {
+			assert.Equal(t, evt.OrganizerID, evt.Organizer.ID)
+			assert.NotEmpty(t, evt.Organizer.Name)
+			assert.NotEmpty(t, evt.Organizer.Email)
+		}
+		assert.Equal(t, "Alice", resp.Response.Items[0].Organizer.Name)
+		assert.Equal(t, "Bob", resp.Response.Items[1].Organizer.Name)
+		require.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("list events links to the other pages", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupEventsAPI(t)
+
+		organizerID := uuid.New()
+		slotsJSON, _ := event.SlotsColumn([]event.Slot{}).Value()
+		dbMock.ExpectQuery(`FROM events\s+JOIN users ON users\.id = events\.user_id`).
+			WithArgs(2, 2, organizerID).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "visibility", "created_at", "id", "name", "email"}).
+				AddRow(uuid.New(), "Planning", 1, organizerID, slotsJSON, "UTC", "public", time.Now(), organizerID, "Alice", "alice@example.com").
+				AddRow(uuid.New(), "Retro", 1, organizerID, slotsJSON, "UTC", "public", time.Now(), organizerID, "Alice", "alice@example.com"))
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM events`)).
+			WithArgs(organizerID).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(7))
+
+		rec := httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/events?organizer_id="+organizerID.String()+"&limit=2&offset=2", nil))
+
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		// The links keep the other query parameters.
+		page := func(offset int) string {
+			return fmt.Sprintf("/api/events?limit=2&offset=%d&organizer_id=%s", offset, organizerID)
+		}
+		assert.Equal(t, "<"+page(0)+`>; rel="prev", <`+page(4)+`>; rel="next", <`+page(6)+`>; rel="last"`, rec.Header().Get("Link"))
+		require.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("list events of an organizer", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupEventsAPI(t)
+
+		organizerID := uuid.New()
+		publicID, privateID := uuid.New(), uuid.New()
+		slotsJSON, _ := event.SlotsColumn([]event.Slot{}).Value()
+		// Scoped to the organizer, their private events are listed too.
+		dbMock.ExpectQuery(regexp.QuoteMeta(`WHERE events.deleted_at IS NULL AND events.user_id = $3`)).
+			WithArgs(20, 0, organizerID).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "visibility", "created_at", "id", "name", "email"}).
+				AddRow(publicID, "Planning", 1, organizerID, slotsJSON, "UTC", "public", time.Now(), organizerID, "Alice", "alice@example.com").
+				AddRow(privateID, "1:1", 1, organizerID, slotsJSON, "UTC", "private", time.Now(), organizerID, "Alice", "alice@example.com"))
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM events WHERE deleted_at IS NULL AND events.user_id = $1`)).
+			WithArgs(organizerID).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+
+		rec := httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/events?organizer_id="+organizerID.String(), nil))
+
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var resp struct {
+			Response api.PagedResponse[map[string]any] `json:"response"`
+		}
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+		require.Len(t, resp.Response.Items, 2)
+		assert.Equal(t, 2, resp.Response.Total)
+		assert.NotContains(t, resp.Response.Items[0], "visibility")
+		assert.Equal(t, privateID.String(), resp.Response.Items[1]["id"])
+		assert.Equal(t, "private", resp.Response.Items[1]["visibility"])
+		require.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("list events with an invalid organizer", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupEventsAPI(t)
+
+		for _, url := range []string{"/api/events?organizer_id=bad", "/api/events/search?q=x&organizer_id=bad"} {
+			rec := httptest.NewRecorder()
+			a.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, url, nil))
+			assert.Equal(t, http.StatusBadRequest, rec.Code, url)
+			assert.JSONEq(t, `{"status":400,"response":"invalid organizer_id"}`, rec.Body.String(), url)
+		}
+		require.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("list events by cursor", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupEventsAPI(t)
+
+		organizerID := uuid.New()
+		createdAt := time.Date(2030, 1, 2, 9, 0, 0, 123456000, time.UTC)
+		ids := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+		slotsJSON, _ := event.SlotsColumn([]event.Slot{}).Value()
+		rows := func(from int) *sqlmock.Rows {
+			rows := sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "visibility", "created_at", "id", "name", "email"})
+			for i := from; i < len(ids); i++ {
+				// Newest first; the first two share created_at, so only the id orders them.
+				rows.AddRow(ids[i], fmt.Sprintf("Event %d", i), 1, organizerID, slotsJSON, "UTC", "public", createdAt.Add(time.Duration(min(2-i, 1))*time.Hour),
+					organizerID, "Organizer", "organizer@example.com")
+			}
+			return rows
+		}
+		type page struct {
+			Response struct {
+				Items []struct {
+					ID string `json:"id"`
+				} `json:"items"`
+				NextCursor string `json:"next_cursor"`
+			} `json:"response"`
+		}
+		get := func(url string) page {
+			rec := httptest.NewRecorder()
+			a.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, url, nil))
+			require.Equal(t, http.StatusOK, rec.Code)
+			var p page
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&p))
+			return p
+		}
+
+		// Each page asks for one event more than the limit to tell whether another page follows.
+		dbMock.ExpectQuery(`WHERE events\.deleted_at IS NULL AND events\.visibility = 'public'\s+ORDER BY events\.created_at DESC, events\.id\s+LIMIT \$1$`).
+			WithArgs(3).
+			WillReturnRows(rows(0))
+		first := get("/api/events?after=&limit=2")
+		require.Len(t, first.Response.Items, 2)
+		require.NotEmpty(t, first.Response.NextCursor)
+
+		dbMock.ExpectQuery(regexp.QuoteMeta(`AND (events.created_at < $2 OR (events.created_at = $2 AND events.id > $3)) AND events.visibility = 'public'`)).
+			WithArgs(3, createdAt.Add(time.Hour), ids[1]).
+			WillReturnRows(rows(2))
+		second := get("/api/events?limit=2&after=" + first.Response.NextCursor)
+		require.Len(t, second.Response.Items, 1)
+		assert.Empty(t, second.Response.NextCursor)
+
+		var seen []string
+		for _, p := range []page{first, second} {
+			for _, item := range p.Response.Items {
+				seen = append(seen, item.ID)
+			}
+		}
+		assert.Equal(t, []string{ids[0].String(), ids[1].String(), ids[2].String()}, seen)
+		require.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("list events by cursor invalid parameters", func(t *testing.T) {
+		t.Parallel()
+		a, _ := setupEventsAPI(t)
+
+		for url, message := range map[string]string{
+			"/api/events?after=not-a-cursor": "invalid cursor",
+			"/api/events?after=&offset=20":   "after cannot be combined with offset",
+		} {
+			rec := httptest.NewRecorder()
+			a.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, url, nil))
+			assert.Equal(t, http.StatusBadRequest, rec.Code, url)
+			assert.JSONEq(t, `{"status":400,"response":"`+message+`"}`, rec.Body.String(), url)
+		}
+	})
+
+	t.Run("search events", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupEventsAPI(t)
+
+		eventID := uuid.New()
+		slotsJSON, _ := event.SlotsColumn([]event.Slot{}).Value()
+		dbMock.ExpectQuery("FROM events\\s+WHERE deleted_at IS NULL AND title ILIKE").
+			WithArgs("Standup", 5, 10).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}).
+				AddRow(eventID, "Daily standup", 1, uuid.New(), slotsJSON, "UTC", time.Now(), "public"))
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM events WHERE deleted_at IS NULL AND title ILIKE`)).
+			WithArgs("Standup").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(11))
+
+		req := httptest.NewRequest(http.MethodGet, "/api/events/search?q=Standup&limit=5&offset=10", nil)
+		rec := httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		var resp struct {
+			Response api.PagedResponse[map[string]any] `json:"response"`
+		}
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+		require.Len(t, resp.Response.Items, 1)
+		assert.Equal(t, eventID.String(), resp.Response.Items[0]["id"])
+		assert.Equal(t, 5, resp.Response.Limit)
+		assert.Equal(t, 10, resp.Response.Offset)
+		assert.Equal(t, 11, resp.Response.Total)
+		require.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("search events invalid parameters", func(t *testing.T) {
+		t.Parallel()
+		a, _ := setupEventsAPI(t)
+
+		for _, target := range []string{"/api/events/search", "/api/events/search?q=%20", "/api/events/search?q=x&limit=0", "/api/events/search?q=x&offset=-1"} {
+			rec := httptest.NewRecorder()
+			a.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+			assert.Equal(t, http.StatusBadRequest, rec.Code, target)
+		}
+	})
+
+	t.Run("reassign events", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupEventsAPI(t)
+
+		fromID := uuid.New()
+		toID := uuid.New()
+		getUserQuery := regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)
+		dbMock.ExpectQuery(getUserQuery).
+			WithArgs(fromID).
+			WillReturnRows(sqlmock.NewRows(userColumns).
+				AddRow(fromID, "Leaving", "leaving@example.com", userCreatedAt, userCreatedAt))
+		dbMock.ExpectQuery(getUserQuery).
+			WithArgs(toID).
+			WillReturnRows(sqlmock.NewRows(userColumns).
+				AddRow(toID, "Staying", "staying@example.com", userCreatedAt, userCreatedAt))
+
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec(regexp.QuoteMeta(`UPDATE events SET user_id = $1 WHERE user_id = $2`)).
+			WithArgs(toID, fromID).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		dbMock.ExpectCommit()
+
+		body := `{"to_id":"` + toID.String() + `"}`
+		req := httptest.NewRequest(http.MethodPost, "/api/organizers/"+fromID.String()+"/reassign", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+
+		a.Router().ServeHTTP(rec, req)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var res api.Response
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+		moved, ok := res.Response.(map[string]any)
+		require.True(t, ok)
+		assert.Equal(t, float64(2), moved["moved"])
+	})
 
-		getUsersForSlotQuery := `SELECT users\.id, users\.name, users\.email`
-		dbMock.ExpectQuery(getUsersForSlotQuery).
+	t.Run("reassign events target not found", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupEventsAPI(t)
+
+		fromID := uuid.New()
+		toID := uuid.New()
+		getUserQuery := regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)
+		dbMock.ExpectQuery(getUserQuery).
+			WithArgs(fromID).
+			WillReturnRows(sqlmock.NewRows(userColumns).
+				AddRow(fromID, "Leaving", "leaving@example.com", userCreatedAt, userCreatedAt))
+		dbMock.ExpectQuery(getUserQuery).
+			WithArgs(toID).
+			WillReturnError(sql.ErrNoRows)
+
+		body := `{"to_id":"` + toID.String() + `"}`
+		req := httptest.NewRequest(http.MethodPost, "/api/organizers/"+fromID.String()+"/reassign", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+
+		a.Router().ServeHTTP(rec, req)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("transfer event", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupEventsAPI(t)
+
+		eventID := uuid.New()
+		newOrganizerID := uuid.New()
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+			WithArgs(newOrganizerID).
+			WillReturnRows(sqlmock.NewRows(userColumns).
+				AddRow(newOrganizerID, "Staying", "staying@example.com", userCreatedAt, userCreatedAt))
+		dbMock.ExpectExec(regexp.QuoteMeta(`UPDATE events SET user_id = $1 WHERE id = $2 AND deleted_at IS NULL`)).
+			WithArgs(newOrganizerID, eventID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1`)).
+			WithArgs(eventID).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}).
+				AddRow(eventID, "Team Meeting", 1, newOrganizerID, []byte("[]"), "UTC", time.Now(), "public"))
+
+		body := `{"new_organizer_id":"` + newOrganizerID.String() + `"}`
+		req := jsonRequest(http.MethodPost, "/api/events/"+eventID.String()+"/transfer", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+
+		a.Router().ServeHTTP(rec, req)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var res api.Response
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+		e := res.Response.(map[string]any)
+		assert.Equal(t, newOrganizerID.String(), e["organizer_id"])
+		assert.Equal(t, "Staying", e["organizer"].(map[string]any)["name"])
+	})
+
+	t.Run("transfer event errors", func(t *testing.T) {
+		t.Parallel()
+
+		for _, tc := range []struct {
+			name   string
+			body   string
+			expect func(dbMock sqlmock.Sqlmock, eventID, newOrganizerID uuid.UUID)
+			status int
+		}{
+			{
+				name:   "invalid new organizer id",
+				body:   `{"new_organizer_id":"nope"}`,
+				expect: func(sqlmock.Sqlmock, uuid.UUID, uuid.UUID) {},
+				status: http.StatusBadRequest,
+			},
+			{
+				name: "new organizer not found",
+				expect: func(dbMock sqlmock.Sqlmock, _, newOrganizerID uuid.UUID) {
+					dbMock.ExpectQuery(regexp.QuoteMeta(`FROM users WHERE id = $1`)).
+						WithArgs(newOrganizerID).
+						WillReturnError(sql.ErrNoRows)
+				},
+				status: http.StatusNotFound,
+			},
+			{
+				name: "event not found",
+				expect: func(dbMock sqlmock.Sqlmock, eventID, newOrganizerID uuid.UUID) {
+					dbMock.ExpectQuery(regexp.QuoteMeta(`FROM users WHERE id = $1`)).
+						WithArgs(newOrganizerID).
+						WillReturnRows(sqlmock.NewRows(userColumns).
+							AddRow(newOrganizerID, "Staying", "staying@example.com", userCreatedAt, userCreatedAt))
+					dbMock.ExpectExec(regexp.QuoteMeta(`UPDATE events SET user_id = $1 WHERE id = $2`)).
+						WithArgs(newOrganizerID, eventID).
+						WillReturnResult(sqlmock.NewResult(0, 0))
+				},
+				status: http.StatusNotFound,
+			},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+				a, dbMock := setupEventsAPI(t)
+
+				eventID := uuid.New()
+				newOrganizerID := uuid.New()
+				tc.expect(dbMock, eventID, newOrganizerID)
+				body := tc.body
+				if body == "" {
+					body = `{"new_organizer_id":"` + newOrganizerID.String() + `"}`
+				}
+				req := jsonRequest(http.MethodPost, "/api/events/"+eventID.String()+"/transfer", strings.NewReader(body))
+				rec := httptest.NewRecorder()
+
+				a.Router().ServeHTTP(rec, req)
+
+				require.NoError(t, dbMock.ExpectationsWereMet())
+				assert.Equal(t, tc.status, rec.Code)
+			})
+		}
+	})
+
+	t.Run("slot encoding is consistent across create, get and update", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupEventsAPI(t)
+
+		eventID := uuid.New()
+		organizerID := uuid.New()
+		now := time.Now()
+		startTime := time.Date(2030, 1, 2, 9, 0, 0, 0, time.UTC)
+		endTime := startTime.Add(2 * time.Hour)
+		slotsJSON := []byte(`[{"start_time":"` + startTime.Format(time.RFC3339) + `","end_time":"` + endTime.Format(time.RFC3339) + `"}]`)
+		expectedSlots := []any{map[string]any{
+			"start_time":  float64(startTime.Unix()),
+			"end_time":    float64(endTime.Unix()),
+			"start_local": startTime.UTC().Format(time.RFC3339),
+			"end_local":   endTime.UTC().Format(time.RFC3339),
+		}}
+		body, _ := json.Marshal(map[string]any{
+			"title":          "Team Meeting",
+			"duration_hours": 2,
+			"organizer_id":   organizerID.String(),
+			"slots":          []map[string]int64{{"start_time": startTime.Unix(), "end_time": endTime.Unix()}},
+		})
+		selectQuery := regexp.QuoteMeta(`SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1`)
+		eventRows := func() *sqlmock.Rows {
+			return sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}).
+				AddRow(eventID, "Team Meeting", 2, organizerID, slotsJSON, "UTC", now, "public")
+		}
+		expectOrganizer := func() {
+			dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+				WithArgs(organizerID).
+				WillReturnRows(sqlmock.NewRows(userColumns).
+					AddRow(organizerID, "Organizer", "organizer@example.com", userCreatedAt, userCreatedAt))
+		}
+		slotsOf := func(rec *httptest.ResponseRecorder) any {
+			var res api.Response
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+			evt, ok := res.Response.(map[string]any)
+			require.True(t, ok)
+			return evt["slots"]
+		}
+
+		// create
+		expectOrganizer()
+		dbMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO events (id, title, duration_hours, user_id, slots, timezone, created_at, visibility) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`)).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		rec := httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, jsonRequest(http.MethodPost, "/api/events", bytes.NewBuffer(body)))
+		require.Equal(t, http.StatusCreated, rec.Code)
+		assert.Equal(t, expectedSlots, slotsOf(rec))
+
+		// get
+		dbMock.ExpectQuery(selectQuery).WithArgs(eventID).WillReturnRows(eventRows())
+		expectOrganizer()
+		rec = httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/events/"+eventID.String(), nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, expectedSlots, slotsOf(rec))
+
+		// update
+		dbMock.ExpectQuery(selectQuery).WithArgs(eventID).WillReturnRows(eventRows())
+		expectOrganizer()
+		dbMock.ExpectExec(regexp.QuoteMeta(`UPDATE events SET title = $1, duration_hours = $2, slots = $3, timezone = $4 WHERE id = $5`)).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		dbMock.ExpectQuery(selectQuery).WithArgs(eventID).WillReturnRows(eventRows())
+		expectOrganizer()
+		rec = httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, jsonRequest(http.MethodPut, "/api/events/"+eventID.String(), bytes.NewBuffer(body)))
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, expectedSlots, slotsOf(rec))
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("get event ical", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupEventsAPI(t)
+
+		eventID := uuid.New()
+		organizerID := uuid.New()
+		now := time.Now()
+		startTime := time.Date(2030, 1, 2, 9, 0, 0, 0, time.UTC)
+		endTime := startTime.Add(2 * time.Hour)
+		slotsJSON := []byte(`[{"start_time":"` + startTime.Format(time.RFC3339) + `","end_time":"` + endTime.Format(time.RFC3339) + `"}]`)
+
+		getEventQuery := regexp.QuoteMeta(`SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1`)
+		eventRows := func() *sqlmock.Rows {
+			return sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}).
+				AddRow(eventID, "Team Meeting", 2, organizerID, slotsJSON, "UTC", now, "public")
+		}
+		dbMock.ExpectQuery(getEventQuery).WithArgs(eventID).WillReturnRows(eventRows())
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+			WithArgs(organizerID).
+			WillReturnRows(sqlmock.NewRows(userColumns).
+				AddRow(organizerID, "Organizer", "organizer@example.com", userCreatedAt, userCreatedAt))
+		dbMock.ExpectQuery(getEventQuery).WithArgs(eventID).WillReturnRows(eventRows())
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users`)).
+			WillReturnRows(sqlmock.NewRows(userColumns).
+				AddRow(organizerID, "Organizer", "organizer@example.com", userCreatedAt, userCreatedAt))
+		dbMock.ExpectQuery(regexp.QuoteMeta(`FROM events WHERE user_id = $1 AND deleted_at IS NULL`)).
+			WithArgs(organizerID, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}))
+		dbMock.ExpectQuery(`SELECT users\.id, users\.name, users\.email`).
 			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), 2).
-			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email"}).
-				AddRow(userID, "Alice", "alice@example.com"))
+			WillReturnRows(sqlmock.NewRows(userColumns).
+				AddRow(organizerID, "Organizer", "organizer@example.com", userCreatedAt, userCreatedAt))
+		dbMock.ExpectQuery(`FROM users_recurring_availability`).
+			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "created_at", "updated_at", "weekday", "start_minute", "end_minute", "valid_from", "valid_until"}))
 
-		req := httptest.NewRequest(http.MethodGet, "/api/events/"+eventID.String()+"/possible-slot", nil)
+		req := httptest.NewRequest(http.MethodGet, "/api/events/"+eventID.String()+"/ical", nil)
+		rec := httptest.NewRecorder()
+
+		a.Router().ServeHTTP(rec, req)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "text/calendar; charset=utf-8", rec.Header().Get("Content-Type"))
+		body := rec.Body.String()
+		assert.Contains(t, body, "BEGIN:VCALENDAR")
+		assert.Contains(t, body, "DTSTART:20300102T090000Z\r\n")
+		assert.Contains(t, body, "UID:"+eventID.String())
+		assert.Contains(t, body, "ORGANIZER;CN=Organizer:mailto:organizer@example.com")
+	})
+
+	t.Run("event feed follows the best slot under a stable UID", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupEventsAPI(t)
+
+		eventID := uuid.New()
+		organizerID := uuid.New()
+		now := time.Now()
+		eventColumns := []string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}
+		getEventQuery := regexp.QuoteMeta(`SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1`)
+
+		// expectFeed mocks one fetch of an event whose only, and so best, slot starts at start.
+		expectFeed := func(start time.Time) {
+			slotsJSON := []byte(`[{"start_time":"` + start.Format(time.RFC3339) + `","end_time":"` + start.Add(2*time.Hour).Format(time.RFC3339) + `"}]`)
+			dbMock.ExpectQuery(getEventQuery).WithArgs(eventID).
+				WillReturnRows(sqlmock.NewRows(eventColumns).AddRow(eventID, "Team Meeting", 2, organizerID, slotsJSON, "UTC", now, "public"))
+			dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+				WithArgs(organizerID).
+				WillReturnRows(sqlmock.NewRows(userColumns).
+					AddRow(organizerID, "Organizer", "organizer@example.com", userCreatedAt, userCreatedAt))
+			dbMock.ExpectQuery(getEventQuery).WithArgs(eventID).
+				WillReturnRows(sqlmock.NewRows(eventColumns).AddRow(eventID, "Team Meeting", 2, organizerID, slotsJSON, "UTC", now, "public"))
+			dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users`)).
+				WillReturnRows(sqlmock.NewRows(userColumns).
+					AddRow(organizerID, "Organizer", "organizer@example.com", userCreatedAt, userCreatedAt))
+			dbMock.ExpectQuery(regexp.QuoteMeta(`FROM events WHERE user_id = $1 AND deleted_at IS NULL`)).
+				WithArgs(organizerID, sqlmock.AnyArg(), sqlmock.AnyArg()).
+				WillReturnRows(sqlmock.NewRows(eventColumns))
+			dbMock.ExpectQuery(`SELECT users\.id, users\.name, users\.email`).
+				WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), 2).
+				WillReturnRows(sqlmock.NewRows(userColumns).
+					AddRow(organizerID, "Organizer", "organizer@example.com", userCreatedAt, userCreatedAt))
+			dbMock.ExpectQuery(`FROM users_recurring_availability`).
+				WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg()).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "created_at", "updated_at", "weekday", "start_minute", "end_minute", "valid_from", "valid_until"}))
+		}
+		fetch := func(etag string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodGet, "/api/events/"+eventID.String()+"/feed.ics", nil)
+			if etag != "" {
+				req.Header.Set("If-None-Match", etag)
+			}
+			rec := httptest.NewRecorder()
+			a.Router().ServeHTTP(rec, req)
+			return rec
+		}
+
+		expectFeed(time.Date(2030, 1, 2, 9, 0, 0, 0, time.UTC))
+		first := fetch("")
+		require.Equal(t, http.StatusOK, first.Code)
+		assert.Equal(t, "text/calendar; charset=utf-8", first.Header().Get("Content-Type"))
+		assert.Equal(t, "no-cache", first.Header().Get("Cache-Control"))
+		assert.Empty(t, first.Header().Get("Content-Disposition"))
+		assert.Contains(t, first.Body.String(), "UID:"+eventID.String()+"\r\n")
+		assert.Contains(t, first.Body.String(), "DTSTART:20300102T090000Z\r\n")
+
+		// The slot moved, so the ETag of the first fetch no longer matches.
+		expectFeed(time.Date(2030, 1, 3, 14, 0, 0, 0, time.UTC))
+		second := fetch(first.Header().Get("ETag"))
+		require.Equal(t, http.StatusOK, second.Code)
+		assert.NotEqual(t, first.Header().Get("ETag"), second.Header().Get("ETag"))
+		assert.Contains(t, second.Body.String(), "UID:"+eventID.String()+"\r\n")
+		assert.Contains(t, second.Body.String(), "DTSTART:20300103T140000Z\r\n")
+
+		expectFeed(time.Date(2030, 1, 3, 14, 0, 0, 0, time.UTC))
+		unchanged := fetch(second.Header().Get("ETag"))
+		assert.Equal(t, http.StatusNotModified, unchanged.Code)
+		assert.Empty(t, unchanged.Body.String())
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("get event ical for an all-day slot", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupEventsAPI(t)
+
+		eventID := uuid.New()
+		organizerID := uuid.New()
+		eventColumns := []string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}
+		slotsJSON := []byte(`[{"start_time":"2030-01-02T00:00:00Z","end_time":"2030-01-04T00:00:00Z","all_day":true}]`)
+		getEventQuery := regexp.QuoteMeta(`SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1`)
+
+		dbMock.ExpectQuery(getEventQuery).WithArgs(eventID).
+			WillReturnRows(sqlmock.NewRows(eventColumns).AddRow(eventID, "Offsite", 0, organizerID, slotsJSON, "UTC", time.Now(), "public"))
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+			WithArgs(organizerID).
+			WillReturnRows(sqlmock.NewRows(userColumns).
+				AddRow(organizerID, "Organizer", "organizer@example.com", userCreatedAt, userCreatedAt))
+		// Nobody is available, so the export falls back to the only slot.
+		dbMock.ExpectQuery(getEventQuery).WithArgs(eventID).
+			WillReturnRows(sqlmock.NewRows(eventColumns).AddRow(eventID, "Offsite", 0, organizerID, slotsJSON, "UTC", time.Now(), "public"))
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users`)).
+			WillReturnRows(sqlmock.NewRows(userColumns))
+
+		rec := httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/events/"+eventID.String()+"/ical", nil))
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "DTSTART;VALUE=DATE:20300102\r\nDTEND;VALUE=DATE:20300104\r\n")
+	})
+
+	t.Run("get event ical not found", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupEventsAPI(t)
+
+		eventID := uuid.New()
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1`)).
+			WithArgs(eventID).
+			WillReturnError(sql.ErrNoRows)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/events/"+eventID.String()+"/ical", nil)
+		rec := httptest.NewRecorder()
+
+		a.Router().ServeHTTP(rec, req)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("set rsvp", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupEventsAPI(t)
+
+		eventID := uuid.New()
+		userID := uuid.New()
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1`)).
+			WithArgs(eventID).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}).
+				AddRow(eventID, "Event", 1, uuid.New(), []byte("[]"), "UTC", time.Now(), "public"))
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+			WithArgs(userID).
+			WillReturnRows(sqlmock.NewRows(userColumns).
+				AddRow(userID, "Alice", "alice@example.com", userCreatedAt, userCreatedAt))
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT event_attendees.event_id`)).
+			WithArgs(userID, "yes", eventID).
+			WillReturnRows(sqlmock.NewRows([]string{"event_id"}))
+		dbMock.ExpectExec(`INSERT INTO event_attendees`).
+			WithArgs(eventID, userID, "yes").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		body := `{"user_id":"` + userID.String() + `","status":"yes"}`
+		req := httptest.NewRequest(http.MethodPost, "/api/events/"+eventID.String()+"/rsvp", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
 		rec := httptest.NewRecorder()
 
 		a.Router().ServeHTTP(rec, req)
@@ -336,23 +2657,347 @@ func TestEventsAPI(t *testing.T) {
 
 		var res api.Response
 		require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
-		assert.Equal(t, http.StatusOK, res.Status)
-		possible, ok := res.Response.(map[string]any)
-		require.True(t, ok)
-		assert.Contains(t, possible, "slot")
-		assert.Contains(t, possible, "users")
-		assert.Contains(t, possible, "not_working_users")
+		attendee := res.Response.(map[string]any)
+		assert.Equal(t, "yes", attendee["status"])
 	})
 
-	t.Run("get possible event slot invalid id", func(t *testing.T) {
+	t.Run("set rsvp invalid status", func(t *testing.T) {
 		t.Parallel()
 		a, _ := setupEventsAPI(t)
 
-		req := httptest.NewRequest(http.MethodGet, "/api/events/bad-id/possible-slot", nil)
+		body := `{"user_id":"` + uuid.New().String() + `","status":"sure"}`
+		req := httptest.NewRequest(http.MethodPost, "/api/events/"+uuid.New().String()+"/rsvp", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+
+		a.Router().ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	})
+
+	t.Run("get attendees", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupEventsAPI(t)
+
+		eventID := uuid.New()
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1`)).
+			WithArgs(eventID).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}).
+				AddRow(eventID, "Event", 1, uuid.New(), []byte("[]"), "UTC", time.Now(), "public"))
+		dbMock.ExpectQuery(`SELECT users\.id, users\.name, users\.email, event_attendees\.status`).
+			WithArgs(eventID).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "status"}).
+				AddRow(uuid.New(), "Alice", "alice@example.com", "yes").
+				AddRow(uuid.New(), "Bob", "bob@example.com", "maybe"))
+
+		req := httptest.NewRequest(http.MethodGet, "/api/events/"+eventID.String()+"/attendees", nil)
+		rec := httptest.NewRecorder()
+
+		a.Router().ServeHTTP(rec, req)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var res api.Response
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+		attendees := res.Response.(map[string]any)["attendees"].([]any)
+		require.Len(t, attendees, 2)
+		assert.Equal(t, "maybe", attendees[1].(map[string]any)["status"])
+	})
+
+	t.Run("export event", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupEventsAPI(t)
+
+		eventID := uuid.New()
+		startTime := time.Date(2030, 1, 2, 9, 0, 0, 0, time.UTC)
+		slots := []event.Slot{
+			{StartTime: startTime, EndTime: startTime.Add(2 * time.Hour)},
+			{StartTime: startTime.Add(24 * time.Hour), EndTime: startTime.Add(26 * time.Hour)},
+		}
+		slotsJSON, _ := event.SlotsColumn(slots).Value()
+		alice, bob := uuid.New(), uuid.New()
+
+		expectEvent := func() {
+			dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1`)).
+				WithArgs(eventID).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}).
+					AddRow(eventID, "Event", 2, alice, slotsJSON, "UTC", time.Now(), "public"))
+		}
+		expectEvent()
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+			WithArgs(alice).
+			WillReturnRows(sqlmock.NewRows(userColumns).AddRow(alice, "Alice", "alice@example.com", userCreatedAt, userCreatedAt))
+		expectEvent()
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users`)).
+			WillReturnRows(sqlmock.NewRows(userColumns).
+				AddRow(alice, "Alice", "alice@example.com", userCreatedAt, userCreatedAt).
+				AddRow(bob, "Bob", "bob@example.com", userCreatedAt, userCreatedAt))
+		// The second slot suits more users, so it is ranked first.
+		for _, available := range [][]uuid.UUID{{alice}, {alice, bob}} {
+			rows := sqlmock.NewRows(userColumns)
+			for _, id := range available {
+				rows.AddRow(id, "User", "user@example.com", userCreatedAt, userCreatedAt)
+			}
+			dbMock.ExpectQuery(`SELECT users\.id, users\.name, users\.email`).
+				WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), 2).
+				WillReturnRows(rows)
+			dbMock.ExpectQuery(`FROM users_recurring_availability`).
+				WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg()).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "created_at", "updated_at", "weekday", "start_minute", "end_minute", "valid_from", "valid_until"}))
+		}
+		dbMock.ExpectQuery(`SELECT users\.id, users\.name, users\.email, event_attendees\.status`).
+			WithArgs(eventID).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "status"}).AddRow(bob, "Bob", "bob@example.com", "yes"))
+
+		req := httptest.NewRequest(http.MethodGet, "/api/events/"+eventID.String()+"/export?format=json", nil)
+		rec := httptest.NewRecorder()
+
+		a.Router().ServeHTTP(rec, req)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var res api.Response
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+		export := res.Response.(map[string]any)
+		assert.ElementsMatch(t, []string{"event", "ranked_slots", "attendees", "exported_at"}, slices.Collect(maps.Keys(export)))
+
+		evt := export["event"].(map[string]any)
+		assert.Equal(t, eventID.String(), evt["id"])
+		assert.Equal(t, "Alice", evt["organizer"].(map[string]any)["name"])
+		assert.Equal(t, []any{
+			map[string]any{
+				"slot":              map[string]any{"start_time": float64(slots[1].StartTime.Unix()), "end_time": float64(slots[1].EndTime.Unix())},
+				"available_count":   float64(2),
+				"not_working_count": float64(0),
+			},
+			map[string]any{
+				"slot":              map[string]any{"start_time": float64(slots[0].StartTime.Unix()), "end_time": float64(slots[0].EndTime.Unix())},
+				"available_count":   float64(1),
+				"not_working_count": float64(1),
+			},
+		}, export["ranked_slots"])
+		attendees := export["attendees"].([]any)
+		require.Len(t, attendees, 1)
+		assert.Equal(t, "yes", attendees[0].(map[string]any)["status"])
+	})
+
+	t.Run("export event not found", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupEventsAPI(t)
+
+		eventID := uuid.New()
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1`)).
+			WithArgs(eventID).
+			WillReturnError(sql.ErrNoRows)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/events/"+eventID.String()+"/export", nil)
+		rec := httptest.NewRecorder()
+
+		a.Router().ServeHTTP(rec, req)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("export event unsupported format", func(t *testing.T) {
+		t.Parallel()
+		a, _ := setupEventsAPI(t)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/events/"+uuid.NewString()+"/export?format=csv", nil)
 		rec := httptest.NewRecorder()
 
 		a.Router().ServeHTTP(rec, req)
 
 		assert.Equal(t, http.StatusBadRequest, rec.Code)
 	})
+
+	t.Run("create and update responses have the same shape as get", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupEventsAPI(t)
+
+		eventID := uuid.New()
+		organizerID := uuid.New()
+		now := time.Now()
+		startTime := time.Date(2030, 1, 2, 9, 0, 0, 0, time.UTC)
+		endTime := startTime.Add(2 * time.Hour)
+		slotsJSON := []byte(`[{"start_time":"` + startTime.Format(time.RFC3339) + `","end_time":"` + endTime.Format(time.RFC3339) + `"}]`)
+		body, _ := json.Marshal(map[string]any{
+			"title":          "Team Meeting",
+			"duration_hours": 2,
+			"organizer_id":   organizerID.String(),
+			"slots":          []map[string]int64{{"start_time": startTime.Unix(), "end_time": endTime.Unix()}},
+		})
+		selectQuery := regexp.QuoteMeta(`SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1`)
+		expectEvent := func() {
+			dbMock.ExpectQuery(selectQuery).WithArgs(eventID).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}).
+					AddRow(eventID, "Team Meeting", 2, organizerID, slotsJSON, "UTC", now, "public"))
+		}
+		expectOrganizer := func() {
+			dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+				WithArgs(organizerID).
+				WillReturnRows(sqlmock.NewRows(userColumns).
+					AddRow(organizerID, "Organizer", "organizer@example.com", userCreatedAt, userCreatedAt))
+		}
+		keysOf := func(rec *httptest.ResponseRecorder) []string {
+			var res api.Response
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+			evt, ok := res.Response.(map[string]any)
+			require.True(t, ok)
+			organizer, ok := evt["organizer"].(map[string]any)
+			require.True(t, ok)
+			assert.Equal(t, organizerID.String(), organizer["id"])
+			keys := make([]string, 0, len(evt))
+			for k := range evt {
+				keys = append(keys, k)
+			}
+			slices.Sort(keys)
+			return keys
+		}
+
+		expectOrganizer()
+		dbMock.ExpectExec(`INSERT INTO events`).WillReturnResult(sqlmock.NewResult(1, 1))
+		rec := httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, jsonRequest(http.MethodPost, "/api/events", bytes.NewBuffer(body)))
+		require.Equal(t, http.StatusCreated, rec.Code)
+		createKeys := keysOf(rec)
+
+		expectEvent()
+		expectOrganizer()
+		rec = httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/events/"+eventID.String(), nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+		getKeys := keysOf(rec)
+
+		expectEvent()
+		expectOrganizer()
+		dbMock.ExpectExec(`UPDATE events SET`).WillReturnResult(sqlmock.NewResult(1, 1))
+		expectEvent()
+		expectOrganizer()
+		rec = httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, jsonRequest(http.MethodPut, "/api/events/"+eventID.String(), bytes.NewBuffer(body)))
+		require.Equal(t, http.StatusOK, rec.Code)
+		updateKeys := keysOf(rec)
+
+		assert.Equal(t, getKeys, createKeys)
+		assert.Equal(t, getKeys, updateKeys)
+		require.NoError(t, dbMock.ExpectationsWereMet())
+	})
+}
+
+func TestEventLimits(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2030, 1, 1, 9, 0, 0, 0, time.UTC)
+	slots := func(n int) []map[string]int64 {
+		res := make([]map[string]int64, n)
+		for i := range res {
+			s := start.Add(time.Duration(i) * time.Hour)
+			res[i] = map[string]int64{"start_time": s.Unix(), "end_time": s.Add(time.Hour).Unix()}
+		}
+		return res
+	}
+
+	for _, tc := range []struct {
+		name          string
+		slots         int
+		durationHours int
+		status        int
+	}{
+		{name: "exactly max slots and duration", slots: 3, durationHours: 4, status: http.StatusCreated},
+		{name: "one slot over", slots: 4, durationHours: 4, status: http.StatusUnprocessableEntity},
+		{name: "one hour over", slots: 3, durationHours: 5, status: http.StatusUnprocessableEntity},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			db, dbMock, err := sqlmock.New()
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = db.Close() })
+			a := api.NewAPI(db, api.WithEventLimits(3, 4))
+			a.RegisterRoutes()
+
+			organizerID := uuid.New()
+			if tc.status == http.StatusCreated {
+				dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+					WithArgs(organizerID).
+					WillReturnRows(sqlmock.NewRows(userColumns).
+						AddRow(organizerID, "Organizer", "organizer@example.com", userCreatedAt, userCreatedAt))
+				dbMock.ExpectExec(`INSERT INTO events`).WillReturnResult(sqlmock.NewResult(1, 1))
+			}
+
+			body, _ := json.Marshal(map[string]any{
+				"title":          "Team Meeting",
+				"duration_hours": tc.durationHours,
+				"organizer_id":   organizerID.String(),
+				"slots":          slots(tc.slots),
+			})
+			rec := httptest.NewRecorder()
+			a.Router().ServeHTTP(rec, jsonRequest(http.MethodPost, "/api/events", bytes.NewReader(body)))
+
+			assert.Equal(t, tc.status, rec.Code)
+			require.NoError(t, dbMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSlotGranularity(t *testing.T) {
+	t.Parallel()
+
+	setup := func(t *testing.T) (*api.API, sqlmock.Sqlmock) {
+		db, dbMock, err := sqlmock.New()
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = db.Close() })
+		a := api.NewAPI(db, api.WithSlotGranularity(15*time.Minute))
+		a.RegisterRoutes()
+		return a, dbMock
+	}
+	at := func(hour, minute int) int64 { return time.Date(2030, 1, 1, hour, minute, 0, 0, time.UTC).Unix() }
+
+	t.Run("create rounds the start up and the end down", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setup(t)
+
+		organizerID := uuid.New()
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+			WithArgs(organizerID).
+			WillReturnRows(sqlmock.NewRows(userColumns).
+				AddRow(organizerID, "Organizer", "organizer@example.com", userCreatedAt, userCreatedAt))
+		dbMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO events (id, title, duration_hours, user_id, slots, timezone, created_at, visibility) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`)).
+			WithArgs(sqlmock.AnyArg(), "Review", 1, organizerID, slotsArg{{at(9, 15), at(10, 45)}}, "UTC", sqlmock.AnyArg(), "public").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		body := fmt.Sprintf(`{"title":"Review","duration_hours":1,"organizer_id":%q,"slots":[{"start_time":%d,"end_time":%d}]}`,
+			organizerID, at(9, 7), at(10, 53))
+		rec := httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, jsonRequest(http.MethodPost, "/api/events", strings.NewReader(body)))
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+		var res struct {
+			Response struct {
+				Slots []map[string]any `json:"slots"`
+			} `json:"response"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+		require.Len(t, res.Response.Slots, 1)
+		assert.Equal(t, float64(at(9, 15)), res.Response.Slots[0]["start_time"])
+		assert.Equal(t, float64(at(10, 45)), res.Response.Slots[0]["end_time"])
+	})
+
+	t.Run("slot shorter than the granularity", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setup(t)
+
+		body := fmt.Sprintf(`{"title":"Review","duration_hours":1,"organizer_id":%q,"slots":[{"start_time":%d,"end_time":%d}]}`,
+			uuid.New(), at(9, 1), at(9, 14))
+		rec := httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, jsonRequest(http.MethodPost, "/api/events", strings.NewReader(body)))
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		assert.Contains(t, rec.Body.String(), "slot 0: shorter than the 15m0s slot granularity once rounded")
+	})
 }
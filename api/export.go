@@ -0,0 +1,85 @@
+package api
+
+import (
+	"errors"
+	"events-system/event"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// eventExport is the archive form of an event: the event with its organizer, its slots ranked by how
+// many users can attend them, and the RSVPs received so far.
+type eventExport struct {
+	Event       map[string]any             `json:"event"`
+	RankedSlots []slotAvailabilityResponse `json:"ranked_slots"`
+	Attendees   []event.Attendee           `json:"attendees"`
+	ExportedAt  int64                      `json:"exported_at"`
+}
+
+// getEventExport answers the full export of an event. Only ?format=json exists for now, formats
+// such as ical or csv would render the same eventExport.
+func (a *API) getEventExport(w http.ResponseWriter, r *http.Request) {
+	eventID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		a.Response(w, http.StatusBadRequest, "invalid event ID")
+		return
+	}
+
+	switch r.URL.Query().Get("format") {
+	case "", "json":
+	default:
+		a.Response(w, http.StatusBadRequest, "format must be json")
+		return
+	}
+
+	userAccessor := a.userAccessor()
+	eventAccessor := a.eventAccessor(userAccessor)
+	evt, err := eventAccessor.GetEvent(r.Context(), eventID)
+	if errors.Is(err, event.ErrNotFound) {
+		a.Response(w, http.StatusNotFound, "event not found")
+		return
+	}
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+
+	organizer, err := userAccessor.GetUser(r.Context(), evt.UserID)
+	if err != nil {
+		a.internalError(w, r, fmt.Errorf("get organizer: %w", err))
+		return
+	}
+
+	availability, err := eventAccessor.GetSlotAvailability(r.Context(), evt.ID)
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+	// Best attended first, slots with as many users keep the event's order.
+	slices.SortStableFunc(availability, func(x, y event.SlotAvailability) int { return y.AvailableCount - x.AvailableCount })
+	ranked := make([]slotAvailabilityResponse, 0, len(availability))
+	for _, sa := range availability {
+		ranked = append(ranked, slotAvailabilityResponse{
+			Slot:            sa.Slot,
+			AvailableCount:  sa.AvailableCount,
+			NotWorkingCount: sa.NotWorkingCount,
+		})
+	}
+
+	attendees, err := eventAccessor.GetAttendees(r.Context(), evt.ID)
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+
+	a.Response(w, http.StatusOK, eventExport{
+		Event:       eventResponse(evt, organizer),
+		RankedSlots: ranked,
+		Attendees:   attendees,
+		ExportedAt:  a.clock.Now().Unix(),
+	})
+}
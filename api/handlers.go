@@ -3,8 +3,15 @@ package api
 import (
 	"database/sql"
 	"encoding/json"
+	"events-system/database"
+	"events-system/event"
+	"events-system/user"
+	"fmt"
+	"log"
+	"mime"
 	"net/http"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/gorilla/handlers"
@@ -12,66 +19,293 @@ import (
 )
 
 type API struct {
-	router *mux.Router
-	db     *sql.DB
-	now    time.Time
+	router  *mux.Router
+	db      *sql.DB
+	clock   Clock
+	started time.Time
+	build   buildInfo
+
+	corsOptions []handlers.CORSOption
+	stats       statsCache
+	notifier    Notifier
+	mailer      Mailer
+	rateLimiter RateLimiter
+	metrics     *metrics
+	eventLimits eventLimits
+
+	slotGranularity  time.Duration
+	stmts            *database.Statements
+	lockPossibleSlot bool
+
+	maxBodyBytes int64
+}
+
+// Option configures optional API behaviour.
+type Option func(*API)
+
+// WithCORS enables CORS for the given origins. Empty methods or headers fall back to
+// sensible defaults for a JSON API. Preflight requests are answered with 204.
+func WithCORS(origins, methods, headers []string) Option {
+	return func(a *API) {
+		if len(origins) == 0 {
+			return
+		}
+		if len(methods) == 0 {
+			methods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+		}
+		if len(headers) == 0 {
+			headers = []string{"Content-Type", "Accept"}
+		}
+		a.corsOptions = []handlers.CORSOption{
+			handlers.AllowedOrigins(origins),
+			handlers.AllowedMethods(methods),
+			handlers.AllowedHeaders(headers),
+			// Link carries the pagination links, which browsers hide from scripts unless exposed.
+			handlers.ExposedHeaders([]string{"Link"}),
+			handlers.OptionStatusCode(http.StatusNoContent),
+		}
+	}
 }
 
-func NewAPI(db *sql.DB) *API {
+func NewAPI(db *sql.DB, opts ...Option) *API {
 	r := mux.NewRouter()
 	r = r.PathPrefix("/api").Subrouter()
-	return &API{
-		router: r,
-		db:     db,
-		now:    time.Now(),
+	a := &API{
+		router:   r,
+		db:       db,
+		clock:    realClock{},
+		build:    buildInfo{version: "dev", commit: "unknown"},
+		notifier: noopNotifier{},
+		mailer:   noopMailer{},
+		metrics:  newMetrics(),
+		eventLimits: eventLimits{
+			maxSlots:         defaultMaxEventSlots,
+			maxDurationHours: defaultMaxDurationHours,
+		},
+		maxBodyBytes: defaultMaxBodyBytes,
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	a.started = a.clock.Now()
+	r.Use(requestID, a.metrics.middleware, a.negotiateEnvelope, a.limitBody)
+	r.NotFoundHandler = http.HandlerFunc(a.notFound)
+	r.MethodNotAllowedHandler = http.HandlerFunc(a.methodNotAllowed)
+	return a
+}
+
+// WithPreparedStatements runs the hot queries of the accessors through prepared statements shared
+// by every request, see database.Statements. Close releases them.
+func WithPreparedStatements() Option {
+	return func(a *API) {
+		a.stmts = database.NewStatements(a.db)
+	}
+}
+
+// userAccessor returns a user accessor sharing the API's prepared statements, if any.
+func (a *API) userAccessor() *user.Accessor {
+	return user.NewAccessor(a.db).WithStatements(a.stmts)
+}
+
+// WithPossibleSlotLock serializes concurrent possible-slot searches for the same event with a
+// Postgres advisory lock, at the cost of an extra connection per search.
+func WithPossibleSlotLock() Option {
+	return func(a *API) {
+		a.lockPossibleSlot = true
+	}
+}
+
+// eventAccessor returns an event accessor sharing the API's prepared statements, if any.
+func (a *API) eventAccessor(users event.UserAccessor) *event.Accessor {
+	accessor := event.NewAccessor(a.db, users).WithStatements(a.stmts)
+	if a.lockPossibleSlot {
+		accessor = accessor.WithPossibleSlotLock()
+	}
+	return accessor
+}
+
+// Close releases the prepared statements. The database itself belongs to the caller.
+func (a *API) Close() error {
+	if a.stmts == nil {
+		return nil
 	}
+	return a.stmts.Close()
 }
 
 func (a *API) Handler() http.Handler {
-	return handlers.LoggingHandler(os.Stdout, a.router)
+	var h http.Handler = a.router
+	if a.rateLimiter != nil {
+		h = a.rateLimit(h)
+	}
+	if len(a.corsOptions) > 0 {
+		h = handlers.CORS(a.corsOptions...)(h)
+	}
+
+	// /metrics and the probes sit outside the /api router so scrapers and the kubelet reach
+	// them directly, without rate limiting or CORS.
+	root := http.NewServeMux()
+	root.Handle("/metrics", a.metrics.handler())
+	root.HandleFunc("GET /healthz", a.healthz)
+	root.HandleFunc("GET /readyz", a.readyz)
+	root.Handle("/", h)
+	return handlers.LoggingHandler(os.Stdout, root)
 }
 
 func (a *API) Router() http.Handler {
 	return a.router
 }
 
+// internalError answers 500 with a generic message and logs err, which may contain SQL or
+// other internals that must not reach the client, under the request ID the client is given.
+func (a *API) internalError(w http.ResponseWriter, r *http.Request, err error) {
+	log.Printf("internal error: request %s %s %s: %v", requestIDFrom(r.Context()), r.Method, r.URL.Path, err)
+	a.Response(w, http.StatusInternalServerError, "internal server error")
+}
+
 type Response struct {
 	Status   int `json:"status"`
 	Response any `json:"response"`
 }
 
+// Response writes data in the {status, response} envelope, or bare when the client opted out of it,
+// see negotiateEnvelope.
 func (a *API) Response(w http.ResponseWriter, status int, data any) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
-	response := Response{
-		Status: status,
+	var body any = data
+	if _, bare := w.(bareWriter); !bare {
+		body = Response{Status: status, Response: data}
 	}
 
-	if data != nil {
-		response.Response = data
-	}
-
-	err := json.NewEncoder(w).Encode(response)
+	err := json.NewEncoder(w).Encode(body)
 	if err != nil {
 		http.Error(w, "encode response", http.StatusInternalServerError)
 		return
 	}
 }
 
+// routeMethods are the methods probed when building the Allow header of a 405.
+var routeMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+
+// allowedMethods returns the methods that some route accepts for the request's path.
+func (a *API) allowedMethods(r *http.Request) []string {
+	var allowed []string
+	for _, method := range routeMethods {
+		probe := *r
+		probe.Method = method
+		var match mux.RouteMatch
+		if a.router.Match(&probe, &match) && match.MatchErr == nil {
+			allowed = append(allowed, method)
+		}
+	}
+	return allowed
+}
+
+// methodNotAllowed answers 405 with an Allow header listing the methods the path supports.
+func (a *API) methodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Allow", strings.Join(a.allowedMethods(r), ", "))
+	a.Response(w, http.StatusMethodNotAllowed, "method not allowed")
+}
+
+// notFound answers unknown paths with the usual JSON envelope rather than mux's plain text.
+//
+// It also handles method mismatches: on a subrouter gorilla/mux forgets them as soon as a later
+// route matches the shared /api prefix, so a known path with the wrong method ends up here.
+func (a *API) notFound(w http.ResponseWriter, r *http.Request) {
+	if len(a.allowedMethods(r)) > 0 {
+		a.methodNotAllowed(w, r)
+		return
+	}
+	a.Response(w, http.StatusNotFound, fmt.Sprintf("no route for %s %s, see /api/openapi.json", r.Method, r.URL.Path))
+}
+
+// requireJSON answers 415 Unsupported Media Type when a request carries a body that is not declared
+// as application/json. Requests without a body, such as a plain duplicate, pass through.
+func (a *API) requireJSON(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.ContentLength != 0 {
+			mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+			if err != nil || mediaType != "application/json" {
+				w.Header().Set("Accept", "application/json")
+				a.Response(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+				return
+			}
+		}
+		next(w, r)
+	}
+}
+
+// created answers 201 with the new resource and a Location header pointing at it.
+func (a *API) created(w http.ResponseWriter, location string, data any) {
+	w.Header().Set("Location", location)
+	a.Response(w, http.StatusCreated, data)
+}
+
+// createIfNoneMatch reports whether the client asked, via "If-None-Match: *", to create the resource only if
+// nothing exists under its client-supplied ID yet.
+func createIfNoneMatch(r *http.Request) bool {
+	return strings.TrimSpace(r.Header.Get("If-None-Match")) == "*"
+}
+
 func (a *API) RegisterRoutes() {
 	a.router.HandleFunc("/health", a.health).Methods(http.MethodGet)
+	a.router.HandleFunc("/health/detailed", a.detailedHealth).Methods(http.MethodGet)
+	a.router.HandleFunc("/stats", a.getStats).Methods(http.MethodGet)
+	a.router.HandleFunc("/openapi.json", a.getOpenAPISpec).Methods(http.MethodGet)
 
 	// users
-	a.router.HandleFunc("/users", a.createUser).Methods(http.MethodPost)
+	a.router.HandleFunc("/users", a.requireJSON(a.createUser)).Methods(http.MethodPost)
+	a.router.HandleFunc("/users/bulk", a.requireJSON(a.createUsersBulk)).Methods(http.MethodPost)
+	a.router.HandleFunc("/users/count", a.getUsersCount).Methods(http.MethodGet)
 	a.router.HandleFunc("/users/{id}", a.getUser).Methods(http.MethodGet)
+	a.router.HandleFunc("/users/{id}", a.requireJSON(a.patchUser)).Methods(http.MethodPatch)
 	a.router.HandleFunc("/users", a.getUsers).Methods(http.MethodGet)
-	a.router.HandleFunc("/users/{id}/slots", a.createUserSlots).Methods(http.MethodPost)
+	a.router.HandleFunc("/users.csv", a.getUsersCSV).Methods(http.MethodGet)
+	a.router.HandleFunc("/users/{id}/deactivate", a.deactivateUser).Methods(http.MethodPost)
+	a.router.HandleFunc("/users/{id}/activate", a.activateUser).Methods(http.MethodPost)
+	a.router.HandleFunc("/users/{id}/slots", a.requireJSON(a.createUserSlots)).Methods(http.MethodPost)
 	a.router.HandleFunc("/users/{id}/slots", a.deleteUserSlots).Methods(http.MethodDelete)
+	a.router.HandleFunc("/users/{id}/slots/validate", a.requireJSON(a.validateUserSlots)).Methods(http.MethodPost)
+	a.router.HandleFunc("/users/{id}/recurrences", a.requireJSON(a.createUserRecurrences)).Methods(http.MethodPost)
+	a.router.HandleFunc("/users/{id}/conflicts", a.getUserConflicts).Methods(http.MethodGet)
+	a.router.HandleFunc("/users/{id}/events", a.getUserEvents).Methods(http.MethodGet)
+	a.router.HandleFunc("/users/{id}/eligible-events", a.getUserEligibleEvents).Methods(http.MethodGet)
+	a.router.HandleFunc("/users/{id}/gaps", a.getUserGaps).Methods(http.MethodGet)
+	a.router.HandleFunc("/users/{id}/availability/summary", a.getUserAvailabilitySummary).Methods(http.MethodGet)
+	a.router.HandleFunc("/users/{id}/calendar", a.getUserCalendar).Methods(http.MethodGet)
+	a.router.HandleFunc("/users/{id}/available", a.getUserAvailable).Methods(http.MethodGet)
+	a.router.HandleFunc("/users/{id}/next-available", a.getUserNextAvailable).Methods(http.MethodGet)
 
 	// events
-	a.router.HandleFunc("/events", a.createEvent).Methods(http.MethodPost)
+	a.router.HandleFunc("/events", a.requireJSON(a.createEvent)).Methods(http.MethodPost)
+	a.router.HandleFunc("/events", a.getEvents).Methods(http.MethodGet)
+	a.router.HandleFunc("/events/count", a.getEventsCount).Methods(http.MethodGet)
+	a.router.HandleFunc("/events/search", a.searchEvents).Methods(http.MethodGet)
+	a.router.HandleFunc("/events/possible-slot", a.requireJSON(a.previewPossibleSlot)).Methods(http.MethodPost)
+	a.router.HandleFunc("/events/batch-possible-slot", a.requireJSON(a.batchPossibleSlot)).Methods(http.MethodPost)
 	a.router.HandleFunc("/events/{id}", a.getEvent).Methods(http.MethodGet)
 	a.router.HandleFunc("/events/{id}", a.deleteEvent).Methods(http.MethodDelete)
-	a.router.HandleFunc("/events/{id}", a.updateEvent).Methods(http.MethodPut)
+	a.router.HandleFunc("/events/{id}", a.requireJSON(a.updateEvent)).Methods(http.MethodPut)
+	a.router.HandleFunc("/events/{id}", a.patchEvent).Methods(http.MethodPatch)
+	a.router.HandleFunc("/events/{id}/slots", a.getEventSlots).Methods(http.MethodGet)
+	a.router.HandleFunc("/events/{id}/slots", a.requireJSON(a.putEventSlots)).Methods(http.MethodPut)
+	a.router.HandleFunc("/events/{id}/slots", a.deleteEventSlots).Methods(http.MethodDelete)
+	a.router.HandleFunc("/events/{id}/duplicate", a.requireJSON(a.duplicateEvent)).Methods(http.MethodPost)
+	a.router.HandleFunc("/events/{id}/transfer", a.requireJSON(a.transferEvent)).Methods(http.MethodPost)
 	a.router.HandleFunc("/events/{id}/possible-slot", a.getPossibleEventSlot).Methods(http.MethodGet)
+	a.router.HandleFunc("/events/{id}/attendance-summary", a.getAttendanceSummary).Methods(http.MethodGet)
+	a.router.HandleFunc("/events/{id}/slot-availability", a.getSlotAvailability).Methods(http.MethodGet)
+	a.router.HandleFunc("/events/{id}/ical", a.getEventICal).Methods(http.MethodGet)
+	a.router.HandleFunc("/events/{id}/feed.ics", a.getEventFeed).Methods(http.MethodGet)
+	a.router.HandleFunc("/events/{id}/export", a.getEventExport).Methods(http.MethodGet)
+	a.router.HandleFunc("/events/{id}/notify", a.notifyEvent).Methods(http.MethodPost)
+	a.router.HandleFunc("/events/{id}/rsvp", a.requireJSON(a.setRSVP)).Methods(http.MethodPost)
+	a.router.HandleFunc("/events/{id}/attendees", a.getAttendees).Methods(http.MethodGet)
+
+	// availability
+	a.router.HandleFunc("/availability", a.deleteAvailability).Methods(http.MethodDelete)
+	a.router.HandleFunc("/availability/common", a.requireJSON(a.getCommonAvailability)).Methods(http.MethodPost)
+
+	// organizers
+	a.router.HandleFunc("/organizers/{fromID}/reassign", a.requireJSON(a.reassignEvents)).Methods(http.MethodPost)
 }
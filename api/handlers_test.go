@@ -0,0 +1,348 @@
+package api_test
+
+import (
+	"bytes"
+	"errors"
+	"events-system/api"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCORS(t *testing.T) {
+	t.Parallel()
+
+	db, _, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	a := api.NewAPI(db, api.WithCORS([]string{"https://app.example.com"}, nil, nil))
+	a.RegisterRoutes()
+
+	t.Run("preflight", func(t *testing.T) {
+		t.Parallel()
+
+		req := httptest.NewRequest(http.MethodOptions, "/api/users", nil)
+		req.Header.Set("Origin", "https://app.example.com")
+		req.Header.Set("Access-Control-Request-Method", http.MethodPut)
+		req.Header.Set("Access-Control-Request-Headers", "Content-Type")
+		rec := httptest.NewRecorder()
+
+		a.Handler().ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Equal(t, "https://app.example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, http.MethodPut, rec.Header().Get("Access-Control-Allow-Methods"))
+		assert.Equal(t, "Content-Type", rec.Header().Get("Access-Control-Allow-Headers"))
+	})
+
+	t.Run("disallowed origin", func(t *testing.T) {
+		t.Parallel()
+
+		req := httptest.NewRequest(http.MethodOptions, "/api/users", nil)
+		req.Header.Set("Origin", "https://evil.example.com")
+		req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		rec := httptest.NewRecorder()
+
+		a.Handler().ServeHTTP(rec, req)
+
+		assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"))
+	})
+}
+
+func TestRequireJSON(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name        string
+		contentType string
+		status      int
+	}{
+		{name: "text/plain", contentType: "text/plain", status: http.StatusUnsupportedMediaType},
+		{name: "form", contentType: "application/x-www-form-urlencoded", status: http.StatusUnsupportedMediaType},
+		{name: "missing", contentType: "", status: http.StatusUnsupportedMediaType},
+		{name: "json with charset", contentType: "application/json; charset=utf-8", status: http.StatusUnprocessableEntity},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			db, _, err := sqlmock.New()
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = db.Close() })
+
+			a := api.NewAPI(db)
+			a.RegisterRoutes()
+
+			// An empty user fails validation, so a JSON request stops at 422 without touching the database.
+			req := httptest.NewRequest(http.MethodPost, "/api/users", strings.NewReader(`{}`))
+			if tc.contentType != "" {
+				req.Header.Set("Content-Type", tc.contentType)
+			}
+			rec := httptest.NewRecorder()
+
+			a.Router().ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.status, rec.Code)
+		})
+	}
+}
+
+func TestMaxBodyBytes(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name   string
+		opts   []api.Option
+		body   string
+		status int
+	}{
+		{name: "default cap", body: `{"name":"` + strings.Repeat("a", 1<<20) + `","email":"alice@example.com"}`, status: http.StatusRequestEntityTooLarge},
+		{name: "configured cap", opts: []api.Option{api.WithMaxBodyBytes(32)}, body: `{"name":"Alice","email":"alice@example.com"}`, status: http.StatusRequestEntityTooLarge},
+		{name: "malformed body under the cap", opts: []api.Option{api.WithMaxBodyBytes(32)}, body: `{"name":`, status: http.StatusBadRequest},
+		{name: "cap from the config", opts: []api.Option{api.WithConfig(api.Config{MaxBodyBytes: 32})}, body: `{"name":"Alice","email":"alice@example.com"}`, status: http.StatusRequestEntityTooLarge},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			db, dbMock, err := sqlmock.New()
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = db.Close() })
+
+			a := api.NewAPI(db, tc.opts...)
+			a.RegisterRoutes()
+
+			req := httptest.NewRequest(http.MethodPost, "/api/users", strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			a.Router().ServeHTTP(rec, req)
+
+			require.NoError(t, dbMock.ExpectationsWereMet())
+			assert.Equal(t, tc.status, rec.Code)
+			if tc.status == http.StatusRequestEntityTooLarge {
+				assert.JSONEq(t, `{"status":413,"response":"request body too large"}`, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestInternalErrorsAreHidden(t *testing.T) {
+	t.Parallel()
+
+	dbErr := errors.New(`pq: relation "events" does not exist at SELECT id, title FROM events`)
+	id := uuid.NewString()
+
+	for _, tc := range []struct {
+		name   string
+		method string
+		target string
+		body   string
+	}{
+		{name: "get event", method: http.MethodGet, target: "/api/events/" + id},
+		{name: "get user", method: http.MethodGet, target: "/api/users/" + id},
+		{name: "list users", method: http.MethodGet, target: "/api/users"},
+		{name: "list events", method: http.MethodGet, target: "/api/events"},
+		{name: "count events", method: http.MethodGet, target: "/api/events/count"},
+		{name: "create user", method: http.MethodPost, target: "/api/users", body: `{"name":"Alice","email":"alice@example.com"}`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			db, dbMock, err := sqlmock.New()
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = db.Close() })
+			dbMock.MatchExpectationsInOrder(false)
+			dbMock.ExpectQuery("").WillReturnError(dbErr)
+			dbMock.ExpectExec("").WillReturnError(dbErr)
+
+			a := api.NewAPI(db)
+			a.RegisterRoutes()
+
+			req := httptest.NewRequest(tc.method, tc.target, strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			a.Router().ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusInternalServerError, rec.Code)
+			assert.JSONEq(t, `{"status":500,"response":"internal server error"}`, rec.Body.String())
+			assert.NotContains(t, rec.Body.String(), "pq:")
+			assert.NotContains(t, rec.Body.String(), "SELECT")
+		})
+	}
+}
+
+// TestInternalErrorsAreLogged swaps the global logger, so it must not run in parallel.
+func TestInternalErrorsAreLogged(t *testing.T) {
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	db, dbMock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	a := api.NewAPI(db)
+	a.RegisterRoutes()
+
+	id := uuid.New()
+	for _, tc := range []struct {
+		name      string
+		requestID string
+		reused    bool
+	}{
+		{name: "client request id", requestID: "req-42", reused: true},
+		{name: "generated request id"},
+		{name: "unsafe request id replaced", requestID: "bad id\nforged log line"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			logs.Reset()
+			dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+				WithArgs(id).
+				WillReturnError(errors.New(`pq: relation "users" does not exist at SELECT id FROM users`))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/users/"+id.String(), nil)
+			if tc.requestID != "" {
+				req.Header.Set("X-Request-ID", tc.requestID)
+			}
+			rec := httptest.NewRecorder()
+			a.Router().ServeHTTP(rec, req)
+
+			require.NoError(t, dbMock.ExpectationsWereMet())
+			assert.Equal(t, http.StatusInternalServerError, rec.Code)
+			assert.NotContains(t, rec.Body.String(), "SELECT")
+
+			got := rec.Header().Get("X-Request-ID")
+			require.NotEmpty(t, got)
+			if tc.reused {
+				assert.Equal(t, tc.requestID, got)
+			} else {
+				assert.NotEqual(t, tc.requestID, got)
+			}
+			assert.Contains(t, logs.String(), "request "+got+" GET /api/users/"+id.String())
+			assert.Contains(t, logs.String(), `pq: relation "users" does not exist at SELECT id FROM users`)
+		})
+	}
+}
+
+func TestEnvelope(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.New()
+	user := fmt.Sprintf(`{"id":%q,"name":"Alice","email":"alice@example.com","created_at":"2024-01-01T00:00:00Z","updated_at":"2024-01-01T00:00:00Z"}`, id)
+
+	for _, tc := range []struct {
+		name   string
+		method string
+		query  string
+		found  bool
+		status int
+		body   string
+	}{
+		{name: "enveloped by default", method: http.MethodGet, found: true, status: http.StatusOK, body: `{"status":200,"response":` + user + `}`},
+		{name: "envelope=true", method: http.MethodGet, query: "?envelope=true", found: true, status: http.StatusOK, body: `{"status":200,"response":` + user + `}`},
+		{name: "envelope=false", method: http.MethodGet, query: "?envelope=false", found: true, status: http.StatusOK, body: user},
+		{name: "bare error", method: http.MethodGet, query: "?envelope=false", status: http.StatusNotFound, body: `"user not found"`},
+		{name: "invalid envelope", method: http.MethodGet, query: "?envelope=maybe", status: http.StatusBadRequest, body: `{"status":400,"response":"invalid envelope"}`},
+		{name: "non-GET keeps the envelope", method: http.MethodPatch, query: "?envelope=false", status: http.StatusUnprocessableEntity, body: `{"status":422,"response":"validate: at least one of name or email is required"}`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			db, dbMock, err := sqlmock.New()
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = db.Close() })
+
+			if tc.method == http.MethodGet && tc.status != http.StatusBadRequest {
+				rows := sqlmock.NewRows([]string{"id", "name", "email", "created_at", "updated_at"})
+				if tc.found {
+					createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+					rows.AddRow(id, "Alice", "alice@example.com", createdAt, createdAt)
+				}
+				dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+					WithArgs(id).
+					WillReturnRows(rows)
+			}
+
+			a := api.NewAPI(db)
+			a.RegisterRoutes()
+
+			req := httptest.NewRequest(tc.method, "/api/users/"+id.String()+tc.query, strings.NewReader(`{}`))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			a.Router().ServeHTTP(rec, req)
+
+			require.NoError(t, dbMock.ExpectationsWereMet())
+			assert.Equal(t, tc.status, rec.Code)
+			assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+			assert.JSONEq(t, tc.body, rec.Body.String())
+		})
+	}
+}
+
+func TestMethodNotAllowed(t *testing.T) {
+	t.Parallel()
+
+	db, _, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	a := api.NewAPI(db)
+	a.RegisterRoutes()
+
+	for _, tc := range []struct {
+		name   string
+		target string
+		allow  string
+	}{
+		{name: "user", target: "/api/users/" + uuid.NewString(), allow: "GET, PATCH"},
+		{name: "event", target: "/api/events/" + uuid.NewString(), allow: "GET, PUT, PATCH, DELETE"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			rec := httptest.NewRecorder()
+			a.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, tc.target, nil))
+
+			assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+			assert.Equal(t, tc.allow, rec.Header().Get("Allow"))
+			assert.JSONEq(t, `{"status":405,"response":"method not allowed"}`, rec.Body.String())
+		})
+	}
+
+	t.Run("unknown path is still not found", func(t *testing.T) {
+		t.Parallel()
+
+		rec := httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/does-not-exist", nil))
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func TestNotFound(t *testing.T) {
+	t.Parallel()
+
+	db, _, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	a := api.NewAPI(db)
+	a.RegisterRoutes()
+
+	rec := httptest.NewRecorder()
+	a.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/does-not-exist", nil))
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"status":404,"response":"no route for GET /api/does-not-exist, see /api/openapi.json"}`, rec.Body.String())
+}
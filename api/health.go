@@ -1,8 +1,74 @@
 package api
 
-import "net/http"
+import (
+	"context"
+	"net/http"
+	"time"
+)
 
+// healthPingTimeout bounds the database ping of the readiness probe.
+const healthPingTimeout = 2 * time.Second
+
+// buildInfo identifies the running binary, see WithBuildInfo.
+type buildInfo struct {
+	version string
+	commit  string
+}
+
+// WithBuildInfo sets the version and commit reported by /api/health/detailed. main receives them
+// through -ldflags "-X main.version=... -X main.commit=...".
+func WithBuildInfo(version, commit string) Option {
+	return func(a *API) {
+		a.build = buildInfo{version: version, commit: commit}
+	}
+}
+
+type detailedHealthResponse struct {
+	Version       string `json:"version"`
+	Commit        string `json:"commit"`
+	UptimeSeconds int64  `json:"uptime_seconds"`
+	DBStatus      string `json:"db_status"`
+}
+
+// health is the liveness probe: it answers as long as the process serves HTTP.
 func (a *API) health(w http.ResponseWriter, _ *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	_, _ = w.Write([]byte("OK"))
 }
+
+// detailedHealth is the readiness probe: it pings the database and answers 503 when that fails.
+func (a *API) detailedHealth(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), healthPingTimeout)
+	defer cancel()
+
+	status, dbStatus := http.StatusOK, "ok"
+	if err := a.db.PingContext(ctx); err != nil {
+		status, dbStatus = http.StatusServiceUnavailable, "unavailable"
+	}
+	a.Response(w, status, detailedHealthResponse{
+		Version:       a.build.version,
+		Commit:        a.build.commit,
+		UptimeSeconds: int64(a.clock.Now().Sub(a.started).Seconds()),
+		DBStatus:      dbStatus,
+	})
+}
+
+// healthz is the Kubernetes liveness probe, served outside /api: the process is up.
+func (a *API) healthz(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("OK"))
+}
+
+// readyz is the Kubernetes readiness probe, served outside /api: it answers 503 while the
+// database does not answer a ping, so the pod gets no traffic it could not serve.
+func (a *API) readyz(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), healthPingTimeout)
+	defer cancel()
+
+	if err := a.db.PingContext(ctx); err != nil {
+		http.Error(w, "database unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("OK"))
+}
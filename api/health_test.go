@@ -0,0 +1,104 @@
+package api_test
+
+import (
+	"encoding/json"
+	"errors"
+	"events-system/api"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetailedHealth(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name     string
+		pingErr  error
+		status   int
+		dbStatus string
+	}{
+		{name: "database reachable", status: http.StatusOK, dbStatus: "ok"},
+		{name: "database down", pingErr: errors.New("dial tcp: connection refused"), status: http.StatusServiceUnavailable, dbStatus: "unavailable"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			db, dbMock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = db.Close() })
+			dbMock.ExpectPing().WillReturnError(tc.pingErr)
+
+			a := api.NewAPI(db, api.WithBuildInfo("1.2.3", "abc123"))
+			a.RegisterRoutes()
+
+			rec := httptest.NewRecorder()
+			a.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/health/detailed", nil))
+
+			require.NoError(t, dbMock.ExpectationsWereMet())
+			assert.Equal(t, tc.status, rec.Code)
+
+			var res api.Response
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+			health := res.Response.(map[string]any)
+			assert.Equal(t, tc.dbStatus, health["db_status"])
+			assert.Equal(t, "1.2.3", health["version"])
+			assert.Equal(t, "abc123", health["commit"])
+			assert.Contains(t, health, "uptime_seconds")
+		})
+	}
+}
+
+func TestHealthz(t *testing.T) {
+	t.Parallel()
+
+	// Liveness never touches the database, so an unexpected ping would fail the expectations.
+	db, dbMock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	a := api.NewAPI(db)
+	a.RegisterRoutes()
+
+	rec := httptest.NewRecorder()
+	a.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	require.NoError(t, dbMock.ExpectationsWereMet())
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "OK", rec.Body.String())
+}
+
+func TestReadyz(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name    string
+		pingErr error
+		status  int
+	}{
+		{name: "database reachable", status: http.StatusOK},
+		{name: "database down", pingErr: errors.New("dial tcp: connection refused"), status: http.StatusServiceUnavailable},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			db, dbMock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = db.Close() })
+			dbMock.ExpectPing().WillReturnError(tc.pingErr)
+
+			a := api.NewAPI(db)
+			a.RegisterRoutes()
+
+			rec := httptest.NewRecorder()
+			a.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			require.NoError(t, dbMock.ExpectationsWereMet())
+			assert.Equal(t, tc.status, rec.Code)
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"events-system/event"
+	"events-system/user"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+const (
+	icalTimeFormat = "20060102T150405Z"
+	icalDateFormat = "20060102"
+)
+
+// icalEscaper escapes TEXT values as described in RFC 5545 section 3.3.11.
+var icalEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+
+// icalCalendar renders a single-event VCALENDAR for evt scheduled at slot. An all-day slot is
+// rendered as dates spanning the whole slot, the DTEND date being exclusive.
+func icalCalendar(evt *event.Event, organizer *user.User, slot event.Slot) string {
+	start := "DTSTART:" + slot.StartTime.UTC().Format(icalTimeFormat)
+	end := "DTEND:" + slot.StartTime.Add(time.Duration(evt.DurationHours)*time.Hour).UTC().Format(icalTimeFormat)
+	if slot.AllDay {
+		start = "DTSTART;VALUE=DATE:" + slot.StartTime.UTC().Format(icalDateFormat)
+		end = "DTEND;VALUE=DATE:" + slot.EndTime.UTC().Format(icalDateFormat)
+	}
+
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//events-system//EN",
+		"BEGIN:VEVENT",
+		"UID:" + evt.ID.String(),
+		"DTSTAMP:" + evt.CreatedAt.UTC().Format(icalTimeFormat),
+		start,
+		end,
+		"SUMMARY:" + icalEscaper.Replace(evt.Title),
+		fmt.Sprintf("ORGANIZER;CN=%s:mailto:%s", icalEscaper.Replace(organizer.Name), organizer.Email),
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}
+	return strings.Join(lines, "\r\n") + "\r\n"
+}
+
+func (a *API) getEventICal(w http.ResponseWriter, r *http.Request) {
+	evt, calendar, ok := a.eventCalendar(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.ics"`, evt.ID))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(calendar))
+}
+
+// getEventFeed serves the same calendar as getEventICal for calendar clients to subscribe to. The
+// UID is the event ID, so a client replaces its copy when the best slot moves. Clients must
+// revalidate on every poll, which the ETag makes cheap while the calendar is unchanged.
+func (a *API) getEventFeed(w http.ResponseWriter, r *http.Request) {
+	_, calendar, ok := a.eventCalendar(w, r)
+	if !ok {
+		return
+	}
+
+	sum := sha256.Sum256([]byte(calendar))
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "no-cache")
+	if strings.TrimSpace(r.Header.Get("If-None-Match")) == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(calendar))
+}
+
+// eventCalendar renders the calendar of the event in the request path at its best possible slot,
+// falling back to the first proposed one. It answers the error itself and reports whether to go on.
+func (a *API) eventCalendar(w http.ResponseWriter, r *http.Request) (*event.Event, string, bool) {
+	id := mux.Vars(r)["id"]
+	if id == "" {
+		a.Response(w, http.StatusBadRequest, "event ID is required")
+		return nil, "", false
+	}
+
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		a.Response(w, http.StatusBadRequest, "invalid event ID")
+		return nil, "", false
+	}
+
+	userAccessor := a.userAccessor()
+	eventAccessor := a.eventAccessor(userAccessor)
+	evt, err := eventAccessor.GetEvent(r.Context(), parsedID)
+	if errors.Is(err, event.ErrNotFound) {
+		a.Response(w, http.StatusNotFound, "event not found")
+		return nil, "", false
+	}
+	if err != nil {
+		a.internalError(w, r, err)
+		return nil, "", false
+	}
+	if len(evt.Slots) == 0 {
+		a.Response(w, http.StatusNotFound, "event has no slots")
+		return nil, "", false
+	}
+
+	organizer, err := userAccessor.GetUser(r.Context(), evt.UserID)
+	if err != nil {
+		// A missing organizer is a broken invariant rather than a client error.
+		a.internalError(w, r, fmt.Errorf("get organizer: %w", err))
+		return nil, "", false
+	}
+
+	// Prefer the best possible slot, falling back to the first proposed one.
+	slot := evt.Slots[0]
+	possibleEventSlot, err := eventAccessor.GetPossibleEventSlot(r.Context(), evt.ID)
+	if err != nil {
+		a.internalError(w, r, err)
+		return nil, "", false
+	}
+	if possibleEventSlot != nil {
+		slot = possibleEventSlot.Slot
+	}
+
+	return evt, icalCalendar(evt, organizer, slot), true
+}
@@ -0,0 +1,200 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const jsonPatchMediaType = "application/json-patch+json"
+
+// errPatchTestFailed is returned when a "test" operation does not match the document.
+var errPatchTestFailed = errors.New("test operation failed")
+
+// patchOperation is one RFC 6902 operation. Only add, remove, replace and test are supported.
+type patchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// applyJSONPatch applies ops in order to doc, a value decoded from JSON into any, and returns the
+// patched document. doc is modified in place; nothing is applied past the first failing operation.
+func applyJSONPatch(doc any, ops []patchOperation) (any, error) {
+	for i, op := range ops {
+		tokens, err := parsePointer(op.Path)
+		if err != nil {
+			return nil, fmt.Errorf("operation %d: %w", i, err)
+		}
+		var value any
+		if op.Op == "add" || op.Op == "replace" || op.Op == "test" {
+			if len(op.Value) == 0 {
+				return nil, fmt.Errorf("operation %d: %s requires a value", i, op.Op)
+			}
+			if err := json.Unmarshal(op.Value, &value); err != nil {
+				return nil, fmt.Errorf("operation %d: invalid value: %w", i, err)
+			}
+		}
+
+		switch op.Op {
+		case "add":
+			doc, err = patchAt(doc, tokens, func(parent any, key string) (any, error) { return addAt(parent, key, value) })
+		case "remove":
+			doc, err = patchAt(doc, tokens, removeAt)
+		case "replace":
+			doc, err = patchAt(doc, tokens, func(parent any, key string) (any, error) {
+				parent, err := removeAt(parent, key)
+				if err != nil {
+					return nil, err
+				}
+				return addAt(parent, key, value)
+			})
+		case "test":
+			var current any
+			current, err = valueAt(doc, tokens)
+			if err == nil && !reflect.DeepEqual(current, value) {
+				err = errPatchTestFailed
+			}
+		default:
+			err = fmt.Errorf("unsupported op %q", op.Op)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("operation %d: %w", i, err)
+		}
+	}
+	return doc, nil
+}
+
+// parsePointer splits an RFC 6901 JSON pointer into its unescaped reference tokens.
+func parsePointer(path string) ([]string, error) {
+	if path == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(path, "/") {
+		return nil, fmt.Errorf("invalid path %q", path)
+	}
+	tokens := strings.Split(path[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(t)
+	}
+	return tokens, nil
+}
+
+// patchAt walks to the parent of the last token and replaces it with what fn returns for it.
+func patchAt(doc any, tokens []string, fn func(parent any, key string) (any, error)) (any, error) {
+	if len(tokens) == 0 {
+		return nil, errors.New("cannot patch the whole document")
+	}
+	if len(tokens) == 1 {
+		return fn(doc, tokens[0])
+	}
+	child, err := childAt(doc, tokens[0])
+	if err != nil {
+		return nil, err
+	}
+	child, err = patchAt(child, tokens[1:], fn)
+	if err != nil {
+		return nil, err
+	}
+	return setChild(doc, tokens[0], child)
+}
+
+func valueAt(doc any, tokens []string) (any, error) {
+	for _, t := range tokens {
+		var err error
+		if doc, err = childAt(doc, t); err != nil {
+			return nil, err
+		}
+	}
+	return doc, nil
+}
+
+func childAt(doc any, key string) (any, error) {
+	switch d := doc.(type) {
+	case map[string]any:
+		v, ok := d[key]
+		if !ok {
+			return nil, fmt.Errorf("path member %q not found", key)
+		}
+		return v, nil
+	case []any:
+		i, err := arrayIndex(key, len(d)-1)
+		if err != nil {
+			return nil, err
+		}
+		return d[i], nil
+	default:
+		return nil, fmt.Errorf("cannot index into %q", key)
+	}
+}
+
+func setChild(doc any, key string, child any) (any, error) {
+	switch d := doc.(type) {
+	case map[string]any:
+		d[key] = child
+		return d, nil
+	case []any:
+		i, err := arrayIndex(key, len(d)-1)
+		if err != nil {
+			return nil, err
+		}
+		d[i] = child
+		return d, nil
+	default:
+		return nil, fmt.Errorf("cannot index into %q", key)
+	}
+}
+
+// addAt sets a member of an object, or inserts into an array before index key ("-" appends).
+func addAt(parent any, key string, value any) (any, error) {
+	switch p := parent.(type) {
+	case map[string]any:
+		p[key] = value
+		return p, nil
+	case []any:
+		i := len(p)
+		if key != "-" {
+			var err error
+			if i, err = arrayIndex(key, len(p)); err != nil {
+				return nil, err
+			}
+		}
+		p = append(p, nil)
+		copy(p[i+1:], p[i:])
+		p[i] = value
+		return p, nil
+	default:
+		return nil, fmt.Errorf("cannot add %q to a scalar", key)
+	}
+}
+
+func removeAt(parent any, key string) (any, error) {
+	switch p := parent.(type) {
+	case map[string]any:
+		if _, ok := p[key]; !ok {
+			return nil, fmt.Errorf("path member %q not found", key)
+		}
+		delete(p, key)
+		return p, nil
+	case []any:
+		i, err := arrayIndex(key, len(p)-1)
+		if err != nil {
+			return nil, err
+		}
+		return append(p[:i], p[i+1:]...), nil
+	default:
+		return nil, fmt.Errorf("cannot remove %q from a scalar", key)
+	}
+}
+
+// arrayIndex parses an array index token, which must lie in [0, last].
+func arrayIndex(key string, last int) (int, error) {
+	i, err := strconv.Atoi(key)
+	if err != nil || strings.TrimLeft(key, "0123456789") != "" || (len(key) > 1 && key[0] == '0') || i > last {
+		return 0, fmt.Errorf("array index %q out of range", key)
+	}
+	return i, nil
+}
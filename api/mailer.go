@@ -0,0 +1,53 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/smtp"
+	"strings"
+)
+
+// Mailer delivers a plain-text email to a single recipient.
+type Mailer interface {
+	Send(ctx context.Context, to, subject, body string) error
+}
+
+// WithMailer sets the mailer used by the notify endpoint. Without one, emails are silently dropped.
+func WithMailer(m Mailer) Option {
+	return func(a *API) {
+		a.mailer = m
+	}
+}
+
+type noopMailer struct{}
+
+func (noopMailer) Send(context.Context, string, string, string) error { return nil }
+
+// SMTPMailer sends through an SMTP server, one connection per email.
+type SMTPMailer struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+// NewSMTPMailer sends from the given address through the server at addr (host:port). auth may be
+// nil for servers that accept unauthenticated submissions, e.g. a local relay.
+func NewSMTPMailer(addr, from string, auth smtp.Auth) *SMTPMailer {
+	return &SMTPMailer{addr: addr, from: from, auth: auth}
+}
+
+// Send ignores ctx, net/smtp offers no way to cancel a delivery.
+func (m *SMTPMailer) Send(_ context.Context, to, subject, body string) error {
+	if strings.ContainsAny(to+subject, "\r\n") {
+		return fmt.Errorf("invalid header value")
+	}
+	msg := "From: " + m.from + "\r\n" +
+		"To: " + to + "\r\n" +
+		"Subject: " + subject + "\r\n" +
+		"Content-Type: text/plain; charset=utf-8\r\n" +
+		"\r\n" + body
+	if err := smtp.SendMail(m.addr, m.auth, m.from, []string{to}, []byte(msg)); err != nil {
+		return fmt.Errorf("send mail: %w", err)
+	}
+	return nil
+}
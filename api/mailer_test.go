@@ -0,0 +1,136 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"events-system/api"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeMailer records every email and fails those sent to the addresses in fail.
+type fakeMailer struct {
+	mu   sync.Mutex
+	sent []string
+	fail map[string]bool
+}
+
+func (f *fakeMailer) Send(_ context.Context, to, _, _ string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.fail[to] {
+		return errors.New("mailbox unavailable")
+	}
+	f.sent = append(f.sent, to)
+	return nil
+}
+
+func TestNotifyEvent(t *testing.T) {
+	t.Parallel()
+
+	eventColumns := []string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}
+	getEventQuery := regexp.QuoteMeta(`SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1`)
+
+	setup := func(t *testing.T, mailer *fakeMailer) (*api.API, sqlmock.Sqlmock) {
+		db, dbMock, err := sqlmock.New()
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = db.Close() })
+
+		a := api.NewAPI(db, api.WithMailer(mailer))
+		a.RegisterRoutes()
+		return a, dbMock
+	}
+
+	t.Run("emails the users available for the best slot", func(t *testing.T) {
+		t.Parallel()
+		mailer := &fakeMailer{fail: map[string]bool{"bob@example.com": true}}
+		a, dbMock := setup(t, mailer)
+
+		eventID, organizerID := uuid.New(), uuid.New()
+		aliceID, bobID, carolID := uuid.New(), uuid.New(), uuid.New()
+		now := time.Now()
+		startTime := now.Add(24 * time.Hour).Truncate(time.Second)
+		endTime := startTime.Add(2 * time.Hour)
+		slotsJSON := []byte(`[{"start_time":"` + startTime.Format(time.RFC3339) + `","end_time":"` + endTime.Format(time.RFC3339) + `"}]`)
+
+		for range 2 {
+			dbMock.ExpectQuery(getEventQuery).
+				WithArgs(eventID).
+				WillReturnRows(sqlmock.NewRows(eventColumns).AddRow(eventID, "Planning", 2, organizerID, slotsJSON, "UTC", now, "public"))
+		}
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users`)).
+			WillReturnRows(sqlmock.NewRows(userColumns).
+				AddRow(aliceID, "Alice", "alice@example.com", userCreatedAt, userCreatedAt).
+				AddRow(bobID, "Bob", "bob@example.com", userCreatedAt, userCreatedAt).
+				AddRow(carolID, "Carol", "carol@example.com", userCreatedAt, userCreatedAt))
+		dbMock.ExpectQuery(regexp.QuoteMeta(`FROM events WHERE user_id = $1 AND deleted_at IS NULL`)).
+			WithArgs(organizerID, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows(eventColumns))
+		// Carol is not available, so is not emailed.
+		dbMock.ExpectQuery(`SELECT users\.id, users\.name, users\.email`).
+			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), 2).
+			WillReturnRows(sqlmock.NewRows(userColumns).
+				AddRow(aliceID, "Alice", "alice@example.com", userCreatedAt, userCreatedAt).
+				AddRow(bobID, "Bob", "bob@example.com", userCreatedAt, userCreatedAt))
+		dbMock.ExpectQuery(`FROM users_recurring_availability`).
+			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "created_at", "updated_at", "weekday", "start_minute", "end_minute", "valid_from", "valid_until"}))
+
+		rec := httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/events/"+eventID.String()+"/notify", nil))
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		assert.Equal(t, []string{"alice@example.com"}, mailer.sent)
+
+		var res struct {
+			Response struct {
+				Slot       map[string]any   `json:"slot"`
+				Recipients int              `json:"recipients"`
+				Failed     []map[string]any `json:"failed"`
+			} `json:"response"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+		assert.Equal(t, 2, res.Response.Recipients)
+		assert.Equal(t, float64(startTime.Unix()), res.Response.Slot["start_time"])
+		assert.Equal(t, []map[string]any{
+			{"user_id": bobID.String(), "email": "bob@example.com", "error": "mailbox unavailable"},
+		}, res.Response.Failed)
+	})
+
+	t.Run("event not found", func(t *testing.T) {
+		t.Parallel()
+		mailer := &fakeMailer{}
+		a, dbMock := setup(t, mailer)
+
+		eventID := uuid.New()
+		dbMock.ExpectQuery(getEventQuery).WithArgs(eventID).WillReturnRows(sqlmock.NewRows(eventColumns))
+
+		rec := httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/events/"+eventID.String()+"/notify", nil))
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Empty(t, mailer.sent)
+	})
+
+	t.Run("invalid event ID", func(t *testing.T) {
+		t.Parallel()
+		a, _ := setup(t, &fakeMailer{})
+
+		rec := httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/events/bad-id/notify", nil))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
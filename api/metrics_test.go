@@ -0,0 +1,46 @@
+package api_test
+
+import (
+	"events-system/api"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics(t *testing.T) {
+	t.Parallel()
+
+	db, dbMock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	a := api.NewAPI(db)
+	a.RegisterRoutes()
+
+	dbMock.ExpectQuery("SELECT COUNT").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+	for _, target := range []string{"/api/health", "/api/users/count"} {
+		rec := httptest.NewRecorder()
+		a.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		require.Equal(t, http.StatusOK, rec.Code, target)
+	}
+
+	rec := httptest.NewRecorder()
+	a.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	body, err := io.ReadAll(rec.Body)
+	require.NoError(t, err)
+	metrics := string(body)
+
+	assert.Contains(t, metrics, `http_requests_total{method="GET",route="/api/health",status="200"} 1`)
+	assert.Contains(t, metrics, `http_requests_total{method="GET",route="/api/users/count",status="200"} 1`)
+	assert.Contains(t, metrics, `http_request_duration_seconds_count{method="GET",route="/api/health"} 1`)
+	assert.Contains(t, metrics, "http_requests_in_flight 0")
+	assert.Contains(t, metrics, `db_query_duration_seconds_count{query="user.count_users"}`)
+}
@@ -0,0 +1,118 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"events-system/event"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+// Notifier is told about event lifecycle changes after they are persisted.
+// Implementations must not block the request for long and handle their own failures.
+type Notifier interface {
+	EventCreated(ctx context.Context, evt event.Event)
+	EventUpdated(ctx context.Context, evt event.Event)
+}
+
+// WithNotifier sets the notifier invoked after events are created or updated.
+func WithNotifier(n Notifier) Option {
+	return func(a *API) {
+		a.notifier = n
+	}
+}
+
+type noopNotifier struct{}
+
+func (noopNotifier) EventCreated(context.Context, event.Event) {}
+func (noopNotifier) EventUpdated(context.Context, event.Event) {}
+
+// webhookTimeout bounds a single webhook delivery.
+const webhookTimeout = 10 * time.Second
+
+// WebhookNotifier POSTs a JSON payload to a URL for every notification.
+// Deliveries run in the background and failures are only logged.
+type WebhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{
+		url:    url,
+		client: &http.Client{Timeout: webhookTimeout},
+	}
+}
+
+type webhookPayload struct {
+	Type  string       `json:"type"`
+	Event webhookEvent `json:"event"`
+}
+
+type webhookEvent struct {
+	ID            string       `json:"id"`
+	Title         string       `json:"title"`
+	DurationHours int          `json:"duration_hours"`
+	OrganizerID   string       `json:"organizer_id"`
+	Slots         []event.Slot `json:"slots"`
+	CreatedAt     int64        `json:"created_at"`
+}
+
+func (n *WebhookNotifier) EventCreated(_ context.Context, evt event.Event) {
+	n.send("event.created", evt)
+}
+
+func (n *WebhookNotifier) EventUpdated(_ context.Context, evt event.Event) {
+	n.send("event.updated", evt)
+}
+
+// send delivers asynchronously, detached from the request context which ends with the response.
+func (n *WebhookNotifier) send(typ string, evt event.Event) {
+	payload := webhookPayload{
+		Type: typ,
+		Event: webhookEvent{
+			ID:            evt.ID.String(),
+			Title:         evt.Title,
+			DurationHours: evt.DurationHours,
+			OrganizerID:   evt.UserID.String(),
+			Slots:         evt.Slots,
+			CreatedAt:     evt.CreatedAt.Unix(),
+		},
+	}
+
+	go func() {
+		if err := n.post(payload); err != nil {
+			log.Printf("webhook %s for event %s: %v", typ, evt.ID, err)
+		}
+	}()
+}
+
+func (n *WebhookNotifier) post(payload webhookPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), webhookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("new request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("do: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("unexpected status: %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"events-system/api"
+	"events-system/event"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeNotifier struct {
+	mu      sync.Mutex
+	created []event.Event
+	updated []event.Event
+}
+
+func (f *fakeNotifier) EventCreated(_ context.Context, evt event.Event) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.created = append(f.created, evt)
+}
+
+func (f *fakeNotifier) EventUpdated(_ context.Context, evt event.Event) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.updated = append(f.updated, evt)
+}
+
+func TestNotifier(t *testing.T) {
+	t.Parallel()
+
+	t.Run("notified after create", func(t *testing.T) {
+		t.Parallel()
+
+		db, dbMock, err := sqlmock.New()
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = db.Close() })
+
+		notifier := &fakeNotifier{}
+		a := api.NewAPI(db, api.WithNotifier(notifier))
+		a.RegisterRoutes()
+
+		organizerID := uuid.New()
+		startTime := time.Now().Add(24 * time.Hour)
+		endTime := startTime.Add(2 * time.Hour)
+
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+			WithArgs(organizerID).
+			WillReturnRows(sqlmock.NewRows(userColumns).
+				AddRow(organizerID, "Organizer", "organizer@example.com", userCreatedAt, userCreatedAt))
+		dbMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO events (id, title, duration_hours, user_id, slots, timezone, created_at, visibility) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`)).
+			WithArgs(sqlmock.AnyArg(), "Team Meeting", 2, organizerID, sqlmock.AnyArg(), "UTC", sqlmock.AnyArg(), "public").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		body, _ := json.Marshal(map[string]any{
+			"title":          "Team Meeting",
+			"duration_hours": 2,
+			"organizer_id":   organizerID.String(),
+			"slots":          []map[string]int64{{"start_time": startTime.Unix(), "end_time": endTime.Unix()}},
+		})
+		req := httptest.NewRequest(http.MethodPost, "/api/events", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+
+		a.Router().ServeHTTP(rec, req)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		require.Equal(t, http.StatusCreated, rec.Code)
+
+		var res api.Response
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+		created := res.Response.(map[string]any)
+
+		require.Len(t, notifier.created, 1)
+		assert.Empty(t, notifier.updated)
+		assert.Equal(t, created["id"], notifier.created[0].ID.String())
+		assert.Equal(t, "Team Meeting", notifier.created[0].Title)
+		assert.Equal(t, organizerID, notifier.created[0].UserID)
+	})
+
+	t.Run("not notified when create fails", func(t *testing.T) {
+		t.Parallel()
+
+		db, _, err := sqlmock.New()
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = db.Close() })
+
+		notifier := &fakeNotifier{}
+		a := api.NewAPI(db, api.WithNotifier(notifier))
+		a.RegisterRoutes()
+
+		req := httptest.NewRequest(http.MethodPost, "/api/events", bytes.NewBufferString(`{"title":""}`))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+
+		a.Router().ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		assert.Empty(t, notifier.created)
+	})
+
+	t.Run("webhook delivery", func(t *testing.T) {
+		t.Parallel()
+
+		received := make(chan map[string]any, 1)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var payload map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&payload)
+			received <- payload
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		t.Cleanup(srv.Close)
+
+		evt := event.Event{ID: uuid.New(), Title: "Team Meeting", DurationHours: 1, UserID: uuid.New(), CreatedAt: time.Now()}
+		api.NewWebhookNotifier(srv.URL).EventUpdated(t.Context(), evt)
+
+		select {
+		case payload := <-received:
+			assert.Equal(t, "event.updated", payload["type"])
+			assert.Equal(t, evt.ID.String(), payload["event"].(map[string]any)["id"])
+		case <-time.After(5 * time.Second):
+			t.Fatal("webhook was not delivered")
+		}
+	})
+}
@@ -0,0 +1,65 @@
+package api_test
+
+import (
+	"encoding/json"
+	"events-system/api"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAPISpec(t *testing.T) {
+	t.Parallel()
+
+	db, _, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	a := api.NewAPI(db)
+	a.RegisterRoutes()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil)
+	rec := httptest.NewRecorder()
+
+	a.Router().ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var spec struct {
+		OpenAPI string                               `json:"openapi"`
+		Paths   map[string]map[string]map[string]any `json:"paths"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&spec))
+	assert.True(t, strings.HasPrefix(spec.OpenAPI, "3."))
+	assert.Contains(t, spec.Paths, "/users")
+	assert.Contains(t, spec.Paths, "/users/{id}")
+	assert.Contains(t, spec.Paths, "/events")
+	assert.Contains(t, spec.Paths, "/events/{id}")
+
+	// Every registered route must be documented.
+	router, ok := a.Router().(*mux.Router)
+	require.True(t, ok)
+	err = router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		tpl, err := route.GetPathTemplate()
+		if err != nil {
+			return nil
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			return nil
+		}
+		path := strings.TrimPrefix(tpl, "/api")
+		for _, m := range methods {
+			assert.Contains(t, spec.Paths[path], strings.ToLower(m), "undocumented route %s %s", m, path)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+}
@@ -0,0 +1,100 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
+
+// PagedResponse is one page of a list together with the paging parameters that produced it and the
+// total number of items, which is enough for clients to build next and previous links.
+type PagedResponse[T any] struct {
+	Items  []T `json:"items"`
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+	Total  int `json:"total"`
+}
+
+// CursorPage is one page of a list paged by key. NextCursor is sent back as ?after= to get the
+// following page and is omitted on the last one.
+type CursorPage[T any] struct {
+	Items      []T    `json:"items"`
+	Limit      int    `json:"limit"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// parsePagination reads ?limit= and ?offset= from the query string. limit defaults to
+// defaultPageLimit and is capped at maxPageLimit.
+func parsePagination(r *http.Request) (limit, offset int, err error) {
+	limit = defaultPageLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit < 1 {
+			return 0, 0, errors.New("limit must be a positive integer")
+		}
+		limit = min(limit, maxPageLimit)
+	}
+	if v := r.URL.Query().Get("offset"); v != "" {
+		offset, err = strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			return 0, 0, errors.New("offset must be a non-negative integer")
+		}
+	}
+	return limit, offset, nil
+}
+
+// parseOptionalPagination is parsePagination for lists that are returned whole unless paged:
+// without ?limit= the limit is 0, meaning no limit.
+func parseOptionalPagination(r *http.Request) (limit, offset int, err error) {
+	limit, offset, err = parsePagination(r)
+	if err != nil {
+		return 0, 0, err
+	}
+	if !r.URL.Query().Has("limit") {
+		limit = 0
+	}
+	return limit, offset, nil
+}
+
+// setPageLinks sets an RFC 8288 Link header pointing at the prev, next and last pages of an
+// offset-paged list, so that generic clients can walk it without reading the body. The links keep
+// the request's other query parameters.
+func setPageLinks(w http.ResponseWriter, r *http.Request, limit, offset, total int) {
+	var links []string
+	if offset > 0 {
+		links = append(links, pageLink(r, "prev", map[string]string{
+			"limit": strconv.Itoa(limit), "offset": strconv.Itoa(max(offset-limit, 0)),
+		}))
+	}
+	if offset+limit < total {
+		links = append(links, pageLink(r, "next", map[string]string{
+			"limit": strconv.Itoa(limit), "offset": strconv.Itoa(offset + limit),
+		}))
+	}
+	last := 0
+	if total > 0 {
+		last = (total - 1) / limit * limit
+	}
+	links = append(links, pageLink(r, "last", map[string]string{
+		"limit": strconv.Itoa(limit), "offset": strconv.Itoa(last),
+	}))
+	w.Header().Set("Link", strings.Join(links, ", "))
+}
+
+// pageLink formats one Link header entry for the request's URL with params replacing its own.
+func pageLink(r *http.Request, rel string, params map[string]string) string {
+	q := r.URL.Query()
+	for k, v := range params {
+		q.Set(k, v)
+	}
+	u := *r.URL
+	u.RawQuery = q.Encode()
+	return fmt.Sprintf(`<%s>; rel="%s"`, u.RequestURI(), rel)
+}
@@ -0,0 +1,108 @@
+package api
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimiter decides whether a client identified by key may make another request.
+// When it may not, retryAfter is how long the client should wait.
+type RateLimiter interface {
+	Allow(key string) (ok bool, retryAfter time.Duration)
+}
+
+// WithRateLimiter rejects requests the limiter does not allow with 429 Too Many Requests.
+func WithRateLimiter(l RateLimiter) Option {
+	return func(a *API) {
+		a.rateLimiter = l
+	}
+}
+
+// TokenBucketLimiter is an in-memory RateLimiter with one token bucket per key.
+type TokenBucketLimiter struct {
+	rate  float64
+	burst float64
+	now   func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewTokenBucketLimiter allows rps requests per second per key, with bursts of up to burst requests.
+// now is the clock to use, nil means time.Now.
+func NewTokenBucketLimiter(rps float64, burst int, now func() time.Time) *TokenBucketLimiter {
+	if now == nil {
+		now = time.Now
+	}
+	return &TokenBucketLimiter{
+		rate:    rps,
+		burst:   float64(burst),
+		now:     now,
+		buckets: map[string]*tokenBucket{},
+	}
+}
+
+func (l *TokenBucketLimiter) Allow(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &tokenBucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	return false, time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+}
+
+// sweep drops buckets that have refilled completely, they are equivalent to new ones.
+func (l *TokenBucketLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < time.Minute {
+		return
+	}
+	l.lastSweep = now
+	refill := time.Duration(l.burst / l.rate * float64(time.Second))
+	for key, b := range l.buckets {
+		if now.Sub(b.last) >= refill {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// rateLimit keys requests by client IP. X-Request-ID is not used as it is chosen by the client,
+// which could then bypass the limit by sending a new one with every request.
+func (a *API) rateLimit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			key = r.RemoteAddr
+		}
+
+		if ok, retryAfter := a.rateLimiter.Allow(key); !ok {
+			seconds := int(math.Ceil(retryAfter.Seconds()))
+			w.Header().Set("Retry-After", strconv.Itoa(max(seconds, 1)))
+			a.Response(w, http.StatusTooManyRequests, "rate limit exceeded")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
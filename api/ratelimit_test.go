@@ -0,0 +1,54 @@
+package api_test
+
+import (
+	"events-system/api"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimit(t *testing.T) {
+	t.Parallel()
+
+	db, _, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	now := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	a := api.NewAPI(db, api.WithRateLimiter(api.NewTokenBucketLimiter(0.5, 2, clock)))
+	a.RegisterRoutes()
+
+	get := func(remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/health", nil)
+		req.RemoteAddr = remoteAddr
+		rec := httptest.NewRecorder()
+		a.Handler().ServeHTTP(rec, req)
+		return rec
+	}
+
+	// The burst is spent, then the bucket refills one token every two seconds.
+	assert.Equal(t, http.StatusOK, get("10.0.0.1:1234").Code)
+	assert.Equal(t, http.StatusOK, get("10.0.0.1:1234").Code)
+
+	rec := get("10.0.0.1:5678")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "2", rec.Header().Get("Retry-After"))
+
+	// Other clients have their own bucket.
+	assert.Equal(t, http.StatusOK, get("10.0.0.2:1234").Code)
+
+	now = now.Add(time.Second)
+	rec = get("10.0.0.1:1234")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+
+	now = now.Add(time.Second)
+	assert.Equal(t, http.StatusOK, get("10.0.0.1:1234").Code)
+	assert.Equal(t, http.StatusTooManyRequests, get("10.0.0.1:1234").Code)
+}
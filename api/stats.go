@@ -0,0 +1,109 @@
+package api
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// statsCacheTTL bounds how stale the dashboard numbers can get.
+const statsCacheTTL = 30 * time.Second
+
+type statsResponse struct {
+	TotalUsers               int     `json:"total_users"`
+	TotalEvents              int     `json:"total_events"`
+	EventsWithViableSlot     int     `json:"events_with_viable_slot"`
+	AvgAttendeesPerBestSlot  float64 `json:"avg_attendees_per_best_slot"`
+	UsersWithoutAvailability int     `json:"users_without_availability"`
+}
+
+type countResponse struct {
+	Count int `json:"count"`
+}
+
+type statsCache struct {
+	mu        sync.Mutex
+	stats     *statsResponse
+	expiresAt time.Time
+}
+
+func (a *API) getStats(w http.ResponseWriter, r *http.Request) {
+	a.stats.mu.Lock()
+	defer a.stats.mu.Unlock()
+
+	if a.stats.stats != nil && a.clock.Now().Before(a.stats.expiresAt) {
+		a.Response(w, http.StatusOK, a.stats.stats)
+		return
+	}
+
+	userAccessor := a.userAccessor()
+	eventAccessor := a.eventAccessor(userAccessor)
+
+	totalUsers, err := userAccessor.CountUsers(r.Context())
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+	usersWithoutAvailability, err := userAccessor.CountUsersWithoutAvailability(r.Context())
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+	// The totals cover every event, private ones included.
+	totalEvents, err := eventAccessor.WithPrivate().CountEvents(r.Context())
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+	slotStats, err := eventAccessor.GetBestSlotStats(r.Context())
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+
+	a.stats.stats = &statsResponse{
+		TotalUsers:               totalUsers,
+		TotalEvents:              totalEvents,
+		EventsWithViableSlot:     slotStats.ViableEvents,
+		AvgAttendeesPerBestSlot:  slotStats.AvgAttendees,
+		UsersWithoutAvailability: usersWithoutAvailability,
+	}
+	a.stats.expiresAt = a.clock.Now().Add(statsCacheTTL)
+
+	a.Response(w, http.StatusOK, a.stats.stats)
+}
+
+func (a *API) getUsersCount(w http.ResponseWriter, r *http.Request) {
+	count, err := a.userAccessor().CountUsers(r.Context())
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+
+	a.Response(w, http.StatusOK, countResponse{Count: count})
+}
+
+func (a *API) getEventsCount(w http.ResponseWriter, r *http.Request) {
+	eventAccessor := a.eventAccessor(a.userAccessor())
+
+	var count int
+	var err error
+	if organizer := r.URL.Query().Get("organizer_id"); organizer != "" {
+		organizerID, parseErr := uuid.Parse(organizer)
+		if parseErr != nil {
+			a.Response(w, http.StatusBadRequest, "invalid organizer_id")
+			return
+		}
+		count, err = eventAccessor.CountEventsByOrganizer(r.Context(), organizerID)
+	} else {
+		count, err = eventAccessor.CountEvents(r.Context())
+	}
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+
+	a.Response(w, http.StatusOK, countResponse{Count: count})
+}
@@ -0,0 +1,142 @@
+package api_test
+
+import (
+	"encoding/json"
+	"events-system/api"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatsAPI(t *testing.T) {
+	t.Parallel()
+
+	db, dbMock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	a := api.NewAPI(db)
+	a.RegisterRoutes()
+
+	dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM users`)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(4))
+	// Deactivated users are left out of both the users without availability and the attendees.
+	dbMock.ExpectQuery(`SELECT COUNT\(\*\) FROM users\s+WHERE NOT EXISTS[\s\S]*\) AND users\.active$`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM events`)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	dbMock.ExpectQuery(`WITH slot_attendees AS[\s\S]*JOIN users ON users\.id = users_availability\.user_id AND users\.active`).
+		WillReturnRows(sqlmock.NewRows([]string{"count", "avg"}).AddRow(2, 2.5))
+
+	expected := map[string]any{
+		"total_users":                 float64(4),
+		"total_events":                float64(3),
+		"events_with_viable_slot":     float64(2),
+		"avg_attendees_per_best_slot": 2.5,
+		"users_without_availability":  float64(1),
+	}
+
+	for _, name := range []string{"computed", "cached"} {
+		req := httptest.NewRequest(http.MethodGet, "/api/stats", nil)
+		rec := httptest.NewRecorder()
+
+		a.Router().ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code, name)
+		var res api.Response
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+		assert.Equal(t, expected, res.Response, name)
+	}
+
+	// The second request must be served from the cache.
+	require.NoError(t, dbMock.ExpectationsWereMet())
+}
+
+func TestCountAPI(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name   string
+		url    string
+		expect func(sqlmock.Sqlmock)
+		count  float64
+	}{
+		{
+			name: "users",
+			url:  "/api/users/count",
+			expect: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM users`)).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(4))
+			},
+			count: 4,
+		},
+		{
+			name: "events",
+			url:  "/api/events/count",
+			expect: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM events WHERE deleted_at IS NULL`)).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+			},
+			count: 3,
+		},
+		{
+			name: "events by organizer",
+			url:  "/api/events/count?organizer_id=8c5e5b8e-5f3a-4d2a-9f6e-1b2c3d4e5f60",
+			expect: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM events WHERE deleted_at IS NULL AND user_id = $1`)).
+					WithArgs(uuid.MustParse("8c5e5b8e-5f3a-4d2a-9f6e-1b2c3d4e5f60")).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+			},
+			count: 1,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			db, dbMock, err := sqlmock.New()
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = db.Close() })
+
+			a := api.NewAPI(db)
+			a.RegisterRoutes()
+			tc.expect(dbMock)
+
+			req := httptest.NewRequest(http.MethodGet, tc.url, nil)
+			rec := httptest.NewRecorder()
+
+			a.Router().ServeHTTP(rec, req)
+
+			require.NoError(t, dbMock.ExpectationsWereMet())
+			assert.Equal(t, http.StatusOK, rec.Code)
+			var res api.Response
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+			assert.Equal(t, map[string]any{"count": tc.count}, res.Response)
+		})
+	}
+
+	t.Run("invalid organizer", func(t *testing.T) {
+		t.Parallel()
+
+		db, _, err := sqlmock.New()
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = db.Close() })
+
+		a := api.NewAPI(db)
+		a.RegisterRoutes()
+
+		req := httptest.NewRequest(http.MethodGet, "/api/events/count?organizer_id=nope", nil)
+		rec := httptest.NewRecorder()
+
+		a.Router().ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
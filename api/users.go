@@ -1,10 +1,18 @@
 package api
 
 import (
+	"encoding/csv"
 	"encoding/json"
+	"errors"
+	"events-system/event"
 	"events-system/user"
+	"events-system/validation"
 	"fmt"
+	"log"
+	"mime"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -15,23 +23,136 @@ func (a *API) createUser(w http.ResponseWriter, r *http.Request) {
 	var payload user.User
 
 	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
-		http.Error(w, "invalid request body", http.StatusBadRequest)
+		a.invalidBody(w, err)
 		return
 	}
 
 	if err := payload.Validate(); err != nil {
-		a.Response(w, http.StatusBadRequest, fmt.Errorf("validate: %w", err))
+		a.invalidPayload(w, fmt.Errorf("validate: %w", err))
 		return
 	}
 
-	userAccessor := user.NewAccessor(a.db)
+	userAccessor := a.userAccessor()
 
-	user, err := userAccessor.CreateUser(r.Context(), payload)
+	if v := r.URL.Query().Get("upsert"); v != "" {
+		upsert, err := strconv.ParseBool(v)
+		if err != nil {
+			a.Response(w, http.StatusBadRequest, "invalid upsert")
+			return
+		}
+		if upsert {
+			a.upsertUser(w, r, userAccessor, payload)
+			return
+		}
+	}
+
+	if createIfNoneMatch(r) {
+		if payload.ID == uuid.Nil {
+			a.Response(w, http.StatusBadRequest, "id is required with If-None-Match")
+			return
+		}
+		_, err := userAccessor.GetUser(r.Context(), payload.ID)
+		if err == nil {
+			a.Response(w, http.StatusPreconditionFailed, "user already exists")
+			return
+		}
+		if !errors.Is(err, user.ErrNotFound) {
+			a.internalError(w, r, err)
+			return
+		}
+	}
+
+	created, err := userAccessor.CreateUser(r.Context(), payload, a.clock.Now())
+	// The ID was taken since the If-None-Match check, or the email belongs to another user.
+	if errors.Is(err, user.ErrAlreadyExists) {
+		status := http.StatusConflict
+		if createIfNoneMatch(r) {
+			status = http.StatusPreconditionFailed
+		}
+		a.Response(w, status, user.ErrAlreadyExists.Error())
+		return
+	}
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+	a.created(w, "/api/users/"+created.ID.String(), created)
+}
+
+// upsertUser creates the user, answering 201, or renames the user already having its email,
+// answering 200, for integrators syncing users from another system by email. An id belonging to a
+// user with another email is refused with 409 before anything is written.
+func (a *API) upsertUser(w http.ResponseWriter, r *http.Request, userAccessor *user.Accessor, payload user.User) {
+	if payload.ID != uuid.Nil {
+		existing, err := userAccessor.GetUser(r.Context(), payload.ID)
+		if err == nil && !strings.EqualFold(existing.Email, payload.Email) {
+			a.Response(w, http.StatusConflict, user.ErrIDTaken.Error())
+			return
+		}
+		if err != nil && !errors.Is(err, user.ErrNotFound) {
+			a.internalError(w, r, err)
+			return
+		}
+	}
+
+	u, created, err := userAccessor.UpsertUser(r.Context(), payload, a.clock.Now())
+	// The id was taken between the check and the write.
+	if errors.Is(err, user.ErrIDTaken) {
+		a.Response(w, http.StatusConflict, user.ErrIDTaken.Error())
+		return
+	}
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+	if created {
+		a.created(w, "/api/users/"+u.ID.String(), u)
+		return
+	}
+	a.Response(w, http.StatusOK, u)
+}
+
+func (a *API) createUsersBulk(w http.ResponseWriter, r *http.Request) {
+	var payload []user.User
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		a.invalidBody(w, err)
+		return
+	}
+	if len(payload) == 0 {
+		a.invalidPayload(w, errors.New("at least one user is required"))
+		return
+	}
+
+	// Only the first invalid user is reported, its fields named after its index in the body.
+	for i := range payload {
+		err := payload[i].Validate()
+		if err == nil {
+			continue
+		}
+		var fields, nested validation.Errors
+		if errors.As(err, &fields) {
+			nested.Nest(fmt.Sprintf("[%d]", i), fmt.Sprintf("user %d", i), fields)
+			err = nested
+		} else {
+			err = fmt.Errorf("user %d: %w", i, err)
+		}
+		a.invalidPayload(w, err)
+		return
+	}
+
+	users, err := a.userAccessor().CreateUsers(r.Context(), payload, a.clock.Now())
+	var duplicateErr *user.DuplicateUserError
+	if errors.As(err, &duplicateErr) {
+		var errs validation.Errors
+		errs.Add(fmt.Sprintf("[%d].%s", duplicateErr.Index, duplicateErr.Field), duplicateErr.Error())
+		a.invalidPayload(w, errs)
+		return
+	}
 	if err != nil {
-		a.Response(w, http.StatusInternalServerError, err.Error())
+		a.internalError(w, r, err)
 		return
 	}
-	a.Response(w, http.StatusCreated, user)
+	a.Response(w, http.StatusCreated, users)
 }
 
 func (a *API) getUser(w http.ResponseWriter, r *http.Request) {
@@ -47,35 +168,206 @@ func (a *API) getUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	userAccessor := user.NewAccessor(a.db)
-	user, err := userAccessor.GetUser(r.Context(), parsedID)
+	userAccessor := a.userAccessor()
+	u, err := userAccessor.GetUser(r.Context(), parsedID)
+	if errors.Is(err, user.ErrNotFound) {
+		a.Response(w, http.StatusNotFound, "user not found")
+		return
+	}
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+
+	a.Response(w, http.StatusOK, u)
+}
+
+// patchUserRequest uses pointers to tell omitted fields apart from empty ones.
+type patchUserRequest struct {
+	Name  *string `json:"name"`
+	Email *string `json:"email"`
+}
+
+func (a *API) patchUser(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(mux.Vars(r)["id"])
 	if err != nil {
-		a.Response(w, http.StatusInternalServerError, err.Error())
+		a.Response(w, http.StatusBadRequest, "invalid user ID")
+		return
+	}
+
+	var req patchUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		a.invalidBody(w, err)
 		return
 	}
-	if user == nil {
+
+	patch := user.UserPatch{Name: req.Name, Email: req.Email}
+	if err := patch.Validate(); err != nil {
+		a.invalidPayload(w, fmt.Errorf("validate: %w", err))
+		return
+	}
+
+	userAccessor := a.userAccessor()
+	u, err := userAccessor.PatchUser(r.Context(), userID, patch, a.clock.Now())
+	if errors.Is(err, user.ErrNotFound) {
 		a.Response(w, http.StatusNotFound, "user not found")
 		return
 	}
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+
+	a.Response(w, http.StatusOK, u)
+}
+
+// getUsers lists users by name with ?limit= and ?offset= paging, or looks one up by ?email=.
+// Clients accepting application/x-ndjson get every user streamed instead, see getUsersNDJSON.
+func (a *API) getUsers(w http.ResponseWriter, r *http.Request) {
+	userAccessor := a.userAccessor()
+
+	if r.URL.Query().Has("email") {
+		email := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("email")))
+		if err := user.ValidateEmail(email); err != nil {
+			a.Response(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		u, err := userAccessor.GetUserByEmail(r.Context(), email)
+		if errors.Is(err, user.ErrNotFound) {
+			a.Response(w, http.StatusNotFound, "user not found")
+			return
+		}
+		if err != nil {
+			a.internalError(w, r, err)
+			return
+		}
+		a.Response(w, http.StatusOK, u)
+		return
+	}
+
+	if v := r.URL.Query().Get("include_inactive"); v != "" {
+		includeInactive, err := strconv.ParseBool(v)
+		if err != nil {
+			a.Response(w, http.StatusBadRequest, "invalid include_inactive")
+			return
+		}
+		if includeInactive {
+			userAccessor = userAccessor.WithInactive()
+		}
+	}
+
+	if acceptsNDJSON(r) {
+		a.getUsersNDJSON(w, r, userAccessor)
+		return
+	}
 
-	a.Response(w, http.StatusOK, user)
+	limit, offset, err := parsePagination(r)
+	if err != nil {
+		a.Response(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	users, err := userAccessor.GetUsersPage(r.Context(), limit, offset)
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+	total, err := userAccessor.CountUsers(r.Context())
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+	setPageLinks(w, r, limit, offset, total)
+	a.Response(w, http.StatusOK, PagedResponse[user.User]{Items: users, Limit: limit, Offset: offset, Total: total})
 }
 
-type getUsersResponse struct {
-	Users []user.User `json:"users"`
+// acceptsNDJSON reports whether the Accept header asks for newline-delimited JSON.
+func acceptsNDJSON(r *http.Request) bool {
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accept))
+		if err == nil && mediaType == "application/x-ndjson" {
+			return true
+		}
+	}
+	return false
 }
 
-func (a *API) getUsers(w http.ResponseWriter, r *http.Request) {
-	userAccessor := user.NewAccessor(a.db)
-	users, err := userAccessor.GetUsers(r.Context())
+// getUsersNDJSON streams every user as one JSON object per line, writing each row as it is scanned.
+func (a *API) getUsersNDJSON(w http.ResponseWriter, r *http.Request, userAccessor *user.Accessor) {
+	// Headers are only sent with the first user, so a failing query can still answer 500.
+	started := false
+	start := func() {
+		if !started {
+			started = true
+			w.Header().Set("Content-Type", "application/x-ndjson")
+			w.WriteHeader(http.StatusOK)
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	err := userAccessor.EachUser(r.Context(), func(u user.User) error {
+		start()
+		return enc.Encode(u)
+	})
 	if err != nil {
-		a.Response(w, http.StatusInternalServerError, err.Error())
+		if !started {
+			a.internalError(w, r, err)
+			return
+		}
+		log.Printf("stream users: %v", err)
 		return
 	}
-	response := getUsersResponse{
-		Users: users,
+	start()
+}
+
+// getUsersCSV streams every user as a CSV record, in the order of the JSON listing, writing each
+// row as it is scanned.
+func (a *API) getUsersCSV(w http.ResponseWriter, r *http.Request) {
+	cw := csv.NewWriter(w)
+	// As for NDJSON, headers are only sent with the first user, so a failing query can still answer 500.
+	started := false
+	start := func() error {
+		if started {
+			return nil
+		}
+		started = true
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="users.csv"`)
+		w.WriteHeader(http.StatusOK)
+		return cw.Write([]string{"id", "name", "email"})
+	}
+
+	err := a.userAccessor().EachUser(r.Context(), func(u user.User) error {
+		if err := start(); err != nil {
+			return err
+		}
+		if err := cw.Write([]string{u.ID.String(), u.Name, u.Email}); err != nil {
+			return err
+		}
+		cw.Flush()
+		return cw.Error()
+	})
+	// Without any user the header row is still owed.
+	if err == nil {
+		err = start()
+	}
+	if err == nil {
+		cw.Flush()
+		err = cw.Error()
+	}
+	if err != nil {
+		if !started {
+			a.internalError(w, r, err)
+			return
+		}
+		log.Printf("stream users csv: %v", err)
 	}
-	a.Response(w, http.StatusOK, response)
+}
+
+// slotConflictResponse lists the existing slots that a rejected createUserSlots request overlapped.
+type slotConflictResponse struct {
+	Error     string      `json:"error"`
+	Conflicts []user.Slot `json:"conflicts"`
 }
 
 func (a *API) createUserSlots(w http.ResponseWriter, r *http.Request) {
@@ -92,41 +384,224 @@ func (a *API) createUserSlots(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// get user
-	userAccessor := user.NewAccessor(a.db)
-	u, err := userAccessor.GetUser(r.Context(), userID)
+	userAccessor := a.userAccessor()
+	_, err = userAccessor.GetUser(r.Context(), userID)
+	if errors.Is(err, user.ErrNotFound) {
+		a.Response(w, http.StatusNotFound, "user not found")
+		return
+	}
 	if err != nil {
-		a.Response(w, http.StatusInternalServerError, err.Error())
+		a.internalError(w, r, err)
 		return
 	}
-	if u == nil {
-		a.Response(w, http.StatusNotFound, "user not found")
+
+	var slots []user.Slot
+	if err := json.NewDecoder(r.Body).Decode(&slots); err != nil {
+		a.invalidBody(w, err)
 		return
 	}
+	for i, s := range slots {
+		if err := validateSlotBounds(s.StartTime, s.EndTime); err != nil {
+			a.invalidPayload(w, fmt.Errorf("slot %d: %w", i, err))
+			return
+		}
+	}
 
-	var req []slot
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		a.Response(w, http.StatusBadRequest, "invalid request body")
+	switch r.URL.Query().Get("on_overlap") {
+	case "", "reject":
+	case "merge":
+		userAccessor = userAccessor.WithMergeSlots()
+	default:
+		a.Response(w, http.StatusBadRequest, "on_overlap must be reject or merge")
 		return
 	}
 
-	// Convert int64 epoch timestamps to time.Time
-	slots := make([]user.Slot, len(req))
-	for i, s := range req {
-		slots[i] = user.Slot{
-			StartTime: time.Unix(s.StartTime, 0).UTC(),
-			EndTime:   time.Unix(s.EndTime, 0).UTC(),
+	switch r.URL.Query().Get("on_duplicate") {
+	case "", "reject":
+	case "skip":
+		userAccessor = userAccessor.WithSkipDuplicateSlots()
+	default:
+		a.Response(w, http.StatusBadRequest, "on_duplicate must be reject or skip")
+		return
+	}
+
+	// replace swaps the whole availability for the given slots, so there is nothing left to overlap.
+	switch r.URL.Query().Get("mode") {
+	case "", "append":
+	case "replace":
+		replacedSlots, err := userAccessor.ReplaceUserSlots(r.Context(), userID, slots)
+		if a.duplicateSlot(w, err) {
+			return
+		}
+		if err != nil {
+			a.internalError(w, r, err)
+			return
 		}
+		a.Response(w, http.StatusOK, replacedSlots)
+		return
+	default:
+		a.Response(w, http.StatusBadRequest, "mode must be append or replace")
+		return
 	}
 
 	createdSlots, err := userAccessor.CreateUserSlots(r.Context(), userID, slots)
+	var conflictErr *user.SlotConflictError
+	if errors.As(err, &conflictErr) {
+		a.Response(w, http.StatusConflict, slotConflictResponse{
+			Error:     "slots overlap existing availability",
+			Conflicts: conflictErr.Conflicts,
+		})
+		return
+	}
+	if a.duplicateSlot(w, err) {
+		return
+	}
 	if err != nil {
-		a.Response(w, http.StatusInternalServerError, err.Error())
+		a.internalError(w, r, err)
 		return
 	}
 
 	a.Response(w, http.StatusCreated, createdSlots)
 }
 
+// duplicateSlot answers 409 when err is a user.DuplicateSlotError, listing the slot that already
+// existed, and reports whether it did.
+func (a *API) duplicateSlot(w http.ResponseWriter, err error) bool {
+	var duplicateErr *user.DuplicateSlotError
+	if !errors.As(err, &duplicateErr) {
+		return false
+	}
+	a.Response(w, http.StatusConflict, slotConflictResponse{
+		Error:     "slot already exists",
+		Conflicts: []user.Slot{duplicateErr.Slot},
+	})
+	return true
+}
+
+// validateUserSlots checks a body as createUserSlots takes it against the user's availability,
+// without storing anything, and more strictly than create does, see user.ValidateSlots. It answers
+// the slots as they would be stored, or 422 listing every problem; ?on_overlap=merge joins
+// overlapping slots instead of reporting them.
+func (a *API) validateUserSlots(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		a.Response(w, http.StatusBadRequest, "invalid user ID")
+		return
+	}
+
+	var merge bool
+	switch r.URL.Query().Get("on_overlap") {
+	case "", "reject":
+	case "merge":
+		merge = true
+	default:
+		a.Response(w, http.StatusBadRequest, "on_overlap must be reject or merge")
+		return
+	}
+
+	userAccessor := a.userAccessor()
+	_, err = userAccessor.GetUser(r.Context(), userID)
+	if errors.Is(err, user.ErrNotFound) {
+		a.Response(w, http.StatusNotFound, "user not found")
+		return
+	}
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+
+	var slots []user.Slot
+	if err := json.NewDecoder(r.Body).Decode(&slots); err != nil {
+		a.invalidBody(w, err)
+		return
+	}
+	for i, s := range slots {
+		if err := validateSlotBounds(s.StartTime, s.EndTime); err != nil {
+			a.invalidPayload(w, fmt.Errorf("slot %d: %w", i, err))
+			return
+		}
+	}
+
+	existing, err := userAccessor.GetUserSlots(r.Context(), userID)
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+	normalized, err := user.ValidateSlots(slots, existing, a.clock.Now(), merge)
+	if err != nil {
+		a.invalidPayload(w, err)
+		return
+	}
+	a.Response(w, http.StatusOK, normalized)
+}
+
+// recurrenceRequest is a weekly availability rule, valid_from and valid_until are "YYYY-MM-DD" dates.
+type recurrenceRequest struct {
+	Weekday     int    `json:"weekday"`
+	StartMinute int    `json:"start_minute"`
+	EndMinute   int    `json:"end_minute"`
+	ValidFrom   string `json:"valid_from"`
+	ValidUntil  string `json:"valid_until,omitempty"`
+}
+
+func (a *API) createUserRecurrences(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		a.Response(w, http.StatusBadRequest, "invalid user ID")
+		return
+	}
+
+	userAccessor := a.userAccessor()
+	_, err = userAccessor.GetUser(r.Context(), userID)
+	if errors.Is(err, user.ErrNotFound) {
+		a.Response(w, http.StatusNotFound, "user not found")
+		return
+	}
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+
+	var req []recurrenceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		a.invalidBody(w, err)
+		return
+	}
+
+	recurrences := make([]user.Recurrence, len(req))
+	for i, rr := range req {
+		validFrom, err := time.Parse(time.DateOnly, rr.ValidFrom)
+		if err != nil {
+			a.Response(w, http.StatusBadRequest, fmt.Sprintf("recurrence %d: valid_from must be a YYYY-MM-DD date", i))
+			return
+		}
+		recurrences[i] = user.Recurrence{
+			Weekday:     time.Weekday(rr.Weekday),
+			StartMinute: rr.StartMinute,
+			EndMinute:   rr.EndMinute,
+			ValidFrom:   validFrom,
+		}
+		if rr.ValidUntil != "" {
+			validUntil, err := time.Parse(time.DateOnly, rr.ValidUntil)
+			if err != nil {
+				a.Response(w, http.StatusBadRequest, fmt.Sprintf("recurrence %d: valid_until must be a YYYY-MM-DD date", i))
+				return
+			}
+			recurrences[i].ValidUntil = &validUntil
+		}
+		if err := recurrences[i].Validate(); err != nil {
+			a.invalidPayload(w, fmt.Errorf("recurrence %d: %w", i, err))
+			return
+		}
+	}
+
+	if _, err := userAccessor.CreateUserRecurrences(r.Context(), userID, recurrences); err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+	a.Response(w, http.StatusCreated, req)
+}
+
 func (a *API) deleteUserSlots(w http.ResponseWriter, r *http.Request) {
 	id := mux.Vars(r)["id"]
 	if id == "" {
@@ -140,21 +615,416 @@ func (a *API) deleteUserSlots(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	userAccessor := user.NewAccessor(a.db)
+	userAccessor := a.userAccessor()
+	_, err = userAccessor.GetUser(r.Context(), userID)
+	if errors.Is(err, user.ErrNotFound) {
+		a.Response(w, http.StatusNotFound, "user not found")
+		return
+	}
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+
+	err = userAccessor.DeleteUserSlots(r.Context(), userID)
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+	a.Response(w, http.StatusNoContent, nil)
+}
+
+// deactivateUser hides the user from listings and availability lookups, for instance when they leave,
+// while keeping them and their history for audits. They remain fetchable by ID.
+func (a *API) deactivateUser(w http.ResponseWriter, r *http.Request) {
+	a.setUserActive(w, r, false)
+}
+
+// activateUser undoes deactivateUser.
+func (a *API) activateUser(w http.ResponseWriter, r *http.Request) {
+	a.setUserActive(w, r, true)
+}
+
+func (a *API) setUserActive(w http.ResponseWriter, r *http.Request, active bool) {
+	userID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		a.Response(w, http.StatusBadRequest, "invalid user ID")
+		return
+	}
+
+	err = a.userAccessor().SetUserActive(r.Context(), userID, active, a.clock.Now())
+	if errors.Is(err, user.ErrNotFound) {
+		a.Response(w, http.StatusNotFound, "user not found")
+		return
+	}
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+	a.Response(w, http.StatusNoContent, nil)
+}
+
+// getUserEvents lists the events organized by the user, with ?limit= and ?offset= paging.
+func (a *API) getUserEvents(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		a.Response(w, http.StatusBadRequest, "invalid user ID")
+		return
+	}
+	limit, offset, err := parsePagination(r)
+	if err != nil {
+		a.Response(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userAccessor := a.userAccessor()
+	u, err := userAccessor.GetUser(r.Context(), userID)
+	if errors.Is(err, user.ErrNotFound) {
+		a.Response(w, http.StatusNotFound, "user not found")
+		return
+	}
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+
+	eventAccessor := a.eventAccessor(userAccessor)
+	events, err := eventAccessor.GetEventsByOrganizer(r.Context(), userID, limit, offset)
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+	total, err := eventAccessor.CountEventsByOrganizer(r.Context(), userID)
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+
+	res := PagedResponse[map[string]any]{Items: make([]map[string]any, 0, len(events)), Limit: limit, Offset: offset, Total: total}
+	for i := range events {
+		res.Items = append(res.Items, eventResponse(&events[i], u))
+	}
+	setPageLinks(w, r, limit, offset, total)
+	a.Response(w, http.StatusOK, res)
+}
+
+type getUserConflictsResponse struct {
+	Conflicts []map[string]any `json:"conflicts"`
+}
+
+// parseWindow reads the ?from= and ?to= epoch seconds of a [from, to) window.
+func parseWindow(r *http.Request) (from, to time.Time, err error) {
+	fromUnix, err := strconv.ParseInt(r.URL.Query().Get("from"), 10, 64)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("from must be a unix timestamp")
+	}
+	toUnix, err := strconv.ParseInt(r.URL.Query().Get("to"), 10, 64)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("to must be a unix timestamp")
+	}
+	if toUnix <= fromUnix {
+		return time.Time{}, time.Time{}, errors.New("to must be after from")
+	}
+	return time.Unix(fromUnix, 0).UTC(), time.Unix(toUnix, 0).UTC(), nil
+}
+
+// getUserConflicts lists the events organized by the user that overlap the [from, to) window, given in epoch seconds.
+func (a *API) getUserConflicts(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		a.Response(w, http.StatusBadRequest, "invalid user ID")
+		return
+	}
+
+	from, to, err := parseWindow(r)
+	if err != nil {
+		a.Response(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userAccessor := a.userAccessor()
 	u, err := userAccessor.GetUser(r.Context(), userID)
+	if errors.Is(err, user.ErrNotFound) {
+		a.Response(w, http.StatusNotFound, "user not found")
+		return
+	}
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+
+	slot := event.Slot{StartTime: from, EndTime: to}
+	conflicts, err := a.eventAccessor(userAccessor).GetUserEventConflicts(r.Context(), userID, slot)
 	if err != nil {
-		a.Response(w, http.StatusInternalServerError, err.Error())
+		a.internalError(w, r, err)
 		return
 	}
-	if u == nil {
+
+	res := getUserConflictsResponse{Conflicts: make([]map[string]any, 0, len(conflicts))}
+	for i := range conflicts {
+		res.Conflicts = append(res.Conflicts, eventResponse(&conflicts[i], u))
+	}
+	a.Response(w, http.StatusOK, res)
+}
+
+type getUserEligibleEventsResponse struct {
+	Events []map[string]any `json:"events"`
+}
+
+// getUserEligibleEvents lists the upcoming events with a slot that fits the user's availability, the inverse
+// of an event's possible-slot.
+func (a *API) getUserEligibleEvents(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		a.Response(w, http.StatusBadRequest, "invalid user ID")
+		return
+	}
+
+	userAccessor := a.userAccessor()
+	_, err = userAccessor.GetUser(r.Context(), userID)
+	if errors.Is(err, user.ErrNotFound) {
 		a.Response(w, http.StatusNotFound, "user not found")
 		return
 	}
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
 
-	err = userAccessor.DeleteUserSlots(r.Context(), userID)
+	events, err := a.eventAccessor(userAccessor).GetEligibleEvents(r.Context(), userID, a.clock.Now())
 	if err != nil {
-		a.Response(w, http.StatusInternalServerError, err.Error())
+		a.internalError(w, r, err)
 		return
 	}
-	a.Response(w, http.StatusNoContent, nil)
+
+	res := getUserEligibleEventsResponse{Events: make([]map[string]any, 0, len(events))}
+	for i := range events {
+		e := eventResponse(&events[i].Event, &events[i].Organizer)
+		e["eligible_slots"] = localSlotsResponse(events[i].EligibleSlots, events[i].Location())
+		res.Events = append(res.Events, e)
+	}
+	a.Response(w, http.StatusOK, res)
+}
+
+type getUserGapsResponse struct {
+	Gaps []user.Slot `json:"gaps"`
+}
+
+// getUserGaps returns the parts of the [from, to) window, given in epoch seconds, that the user's
+// availability slots do not cover.
+func (a *API) getUserGaps(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		a.Response(w, http.StatusBadRequest, "invalid user ID")
+		return
+	}
+	from, to, err := parseWindow(r)
+	if err != nil {
+		a.Response(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userAccessor := a.userAccessor()
+	_, err = userAccessor.GetUser(r.Context(), userID)
+	if errors.Is(err, user.ErrNotFound) {
+		a.Response(w, http.StatusNotFound, "user not found")
+		return
+	}
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+
+	slots, err := userAccessor.GetUserSlots(r.Context(), userID)
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+
+	a.Response(w, http.StatusOK, getUserGapsResponse{Gaps: user.Gaps(slots, from, to)})
+}
+
+// getUserAvailabilitySummary totals the user's availability slots by weekday. A user without slots
+// gets all zeros.
+func (a *API) getUserAvailabilitySummary(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		a.Response(w, http.StatusBadRequest, "invalid user ID")
+		return
+	}
+
+	userAccessor := a.userAccessor()
+	_, err = userAccessor.GetUser(r.Context(), userID)
+	if errors.Is(err, user.ErrNotFound) {
+		a.Response(w, http.StatusNotFound, "user not found")
+		return
+	}
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+
+	slots, err := userAccessor.GetUserSlots(r.Context(), userID)
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+
+	a.Response(w, http.StatusOK, user.Summarize(slots))
+}
+
+// getUserCalendar lays the user's availability out on an hourly grid of the week containing
+// ?week=YYYY-MM-DD, or of the current week. Weeks start on Monday, UTC.
+func (a *API) getUserCalendar(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		a.Response(w, http.StatusBadRequest, "invalid user ID")
+		return
+	}
+
+	week := a.clock.Now()
+	if v := r.URL.Query().Get("week"); v != "" {
+		week, err = time.Parse(time.DateOnly, v)
+		if err != nil {
+			a.Response(w, http.StatusBadRequest, "week must be a date in YYYY-MM-DD format")
+			return
+		}
+	}
+
+	userAccessor := a.userAccessor()
+	_, err = userAccessor.GetUser(r.Context(), userID)
+	if errors.Is(err, user.ErrNotFound) {
+		a.Response(w, http.StatusNotFound, "user not found")
+		return
+	}
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+
+	slots, err := userAccessor.GetUserSlots(r.Context(), userID)
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+
+	a.Response(w, http.StatusOK, user.Calendar(slots, user.WeekStart(week)))
+}
+
+type getUserAvailableResponse struct {
+	Available bool `json:"available"`
+}
+
+// getUserAvailable tells whether the user is free for the [start, end) slot, given in epoch seconds,
+// under the same rule as possible-slot: an availability window, one-off or recurring, must contain the
+// slot and be at least ?duration_hours= long, which defaults to 0.
+func (a *API) getUserAvailable(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		a.Response(w, http.StatusBadRequest, "invalid user ID")
+		return
+	}
+
+	query := r.URL.Query()
+	start, err := strconv.ParseInt(query.Get("start"), 10, 64)
+	if err != nil {
+		a.Response(w, http.StatusBadRequest, "start must be a unix timestamp")
+		return
+	}
+	end, err := strconv.ParseInt(query.Get("end"), 10, 64)
+	if err != nil {
+		a.Response(w, http.StatusBadRequest, "end must be a unix timestamp")
+		return
+	}
+	if end <= start {
+		a.Response(w, http.StatusBadRequest, "end must be after start")
+		return
+	}
+	durationHours, err := parseDurationHours(r)
+	if err != nil {
+		a.Response(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userAccessor := a.userAccessor()
+	_, err = userAccessor.GetUser(r.Context(), userID)
+	if errors.Is(err, user.ErrNotFound) {
+		a.Response(w, http.StatusNotFound, "user not found")
+		return
+	}
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+
+	// The user was asked for by ID, so a deactivated one is answered from their availability too.
+	slot := user.Slot{StartTime: time.Unix(start, 0).UTC(), EndTime: time.Unix(end, 0).UTC()}
+	users, err := userAccessor.WithInactive().GetUsersForSlot(r.Context(), slot, durationHours, userID)
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+
+	a.Response(w, http.StatusOK, getUserAvailableResponse{Available: len(users) > 0})
+}
+
+// parseDurationHours reads the optional ?duration_hours= minimum length of an availability window,
+// 0 when absent.
+func parseDurationHours(r *http.Request) (int, error) {
+	v := r.URL.Query().Get("duration_hours")
+	if v == "" {
+		return 0, nil
+	}
+	durationHours, err := strconv.Atoi(v)
+	if err != nil || durationHours < 0 {
+		return 0, errors.New("duration_hours must be a non-negative integer")
+	}
+	return durationHours, nil
+}
+
+// getUserNextAvailable returns the user's earliest availability slot starting at or after ?after=,
+// in epoch seconds and by default now, that is at least ?duration_hours= long.
+func (a *API) getUserNextAvailable(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		a.Response(w, http.StatusBadRequest, "invalid user ID")
+		return
+	}
+
+	after := a.clock.Now()
+	if v := r.URL.Query().Get("after"); v != "" {
+		afterUnix, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			a.Response(w, http.StatusBadRequest, "after must be a unix timestamp")
+			return
+		}
+		after = time.Unix(afterUnix, 0).UTC()
+	}
+	durationHours, err := parseDurationHours(r)
+	if err != nil {
+		a.Response(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userAccessor := a.userAccessor()
+	_, err = userAccessor.GetUser(r.Context(), userID)
+	if errors.Is(err, user.ErrNotFound) {
+		a.Response(w, http.StatusNotFound, "user not found")
+		return
+	}
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+
+	slot, err := userAccessor.GetNextAvailableSlot(r.Context(), userID, after, durationHours)
+	if err != nil {
+		a.internalError(w, r, err)
+		return
+	}
+	if slot == nil {
+		a.Response(w, http.StatusNotFound, "no available slot found")
+		return
+	}
+	a.Response(w, http.StatusOK, slot)
 }
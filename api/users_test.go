@@ -5,19 +5,30 @@ import (
 	"database/sql"
 	"encoding/json"
 	"events-system/api"
+	"events-system/user"
+	"events-system/validation"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"regexp"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+// userColumns are the columns the user accessor selects, userCreatedAt stamps the mocked rows.
+var (
+	userColumns   = []string{"id", "name", "email", "created_at", "updated_at"}
+	userCreatedAt = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+)
+
 func setupUsersAPI(t *testing.T) (*api.API, sqlmock.Sqlmock) {
 	t.Helper()
 	db, dbMock, err := sqlmock.New()
@@ -36,9 +47,9 @@ func TestUsersAPI(t *testing.T) {
 		t.Parallel()
 		a, dbMock := setupUsersAPI(t)
 
-		insertQuery := `INSERT INTO users \(id, name, email\) VALUES \(\$1, \$2, \$3\)`
+		insertQuery := `INSERT INTO users \(id, name, email, created_at, updated_at\) VALUES \(\$1, \$2, \$3, \$4, \$5\)`
 		dbMock.ExpectExec(insertQuery).
-			WithArgs(sqlmock.AnyArg(), "Alice", "alice@example.com").
+			WithArgs(sqlmock.AnyArg(), "Alice", "alice@example.com", sqlmock.AnyArg(), sqlmock.AnyArg()).
 			WillReturnResult(sqlmock.NewResult(1, 1))
 
 		body := `{"name":"Alice","email":"alice@example.com"}`
@@ -59,6 +70,9 @@ func TestUsersAPI(t *testing.T) {
 		assert.Equal(t, "Alice", created["name"])
 		assert.Equal(t, "alice@example.com", created["email"])
 		assert.NotEmpty(t, created["id"])
+		assert.NotEmpty(t, created["created_at"])
+		assert.Equal(t, created["created_at"], created["updated_at"])
+		assert.Equal(t, "/api/users/"+created["id"].(string), rec.Header().Get("Location"))
 	})
 
 	t.Run("create user invalid body", func(t *testing.T) {
@@ -85,6 +99,216 @@ func TestUsersAPI(t *testing.T) {
 
 		a.Router().ServeHTTP(rec, req)
 
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	})
+
+	t.Run("create user reports every invalid field", func(t *testing.T) {
+		t.Parallel()
+		a, _ := setupUsersAPI(t)
+
+		rec := httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, jsonRequest(http.MethodPost, "/api/users", strings.NewReader(`{"name":"","email":"not-an-email"}`)))
+
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		var res struct {
+			Response struct {
+				Error  string                  `json:"error"`
+				Errors []validation.FieldError `json:"errors"`
+			} `json:"response"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+		require.Len(t, res.Response.Errors, 2)
+		assert.Equal(t, "name", res.Response.Errors[0].Field)
+		assert.Equal(t, "email", res.Response.Errors[1].Field)
+		assert.True(t, strings.HasPrefix(res.Response.Error, "validate: name is required; "))
+	})
+
+	t.Run("create user if none match", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupUsersAPI(t)
+
+		userID := uuid.New()
+		selectQuery := regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)
+		insertQuery := regexp.QuoteMeta(`INSERT INTO users (id, name, email, created_at, updated_at) VALUES ($1, $2, $3, $4, $5)`)
+		body := `{"id":"` + userID.String() + `","name":"Alice","email":"alice@example.com"}`
+
+		// first attempt creates the user
+		dbMock.ExpectQuery(selectQuery).
+			WithArgs(userID).
+			WillReturnError(sql.ErrNoRows)
+		dbMock.ExpectExec(insertQuery).
+			WithArgs(userID, "Alice", "alice@example.com", sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		req := httptest.NewRequest(http.MethodPost, "/api/users", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-None-Match", "*")
+		rec := httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+
+		// retry finds it and fails the precondition
+		dbMock.ExpectQuery(selectQuery).
+			WithArgs(userID).
+			WillReturnRows(sqlmock.NewRows(userColumns).
+				AddRow(userID, "Alice", "alice@example.com", userCreatedAt, userCreatedAt))
+
+		req = httptest.NewRequest(http.MethodPost, "/api/users", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-None-Match", "*")
+		rec = httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("create user with a taken id or email", func(t *testing.T) {
+		t.Parallel()
+
+		for _, tc := range []struct {
+			name        string
+			ifNoneMatch string
+			status      int
+		}{
+			// Another request stored the user between the If-None-Match check and the insert.
+			{name: "if none match race", ifNoneMatch: "*", status: http.StatusPreconditionFailed},
+			// The id or the email belongs to another user.
+			{name: "taken", status: http.StatusConflict},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+				a, dbMock := setupUsersAPI(t)
+
+				userID := uuid.New()
+				if tc.ifNoneMatch != "" {
+					dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+						WithArgs(userID).
+						WillReturnError(sql.ErrNoRows)
+				}
+				dbMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO users`)).
+					WithArgs(userID, "Alice", "alice@example.com", sqlmock.AnyArg(), sqlmock.AnyArg()).
+					WillReturnError(&pq.Error{Code: "23505"})
+
+				body := `{"id":"` + userID.String() + `","name":"Alice","email":"alice@example.com"}`
+				req := jsonRequest(http.MethodPost, "/api/users", strings.NewReader(body))
+				if tc.ifNoneMatch != "" {
+					req.Header.Set("If-None-Match", tc.ifNoneMatch)
+				}
+				rec := httptest.NewRecorder()
+				a.Router().ServeHTTP(rec, req)
+
+				require.NoError(t, dbMock.ExpectationsWereMet())
+				assert.Equal(t, tc.status, rec.Code, rec.Body.String())
+			})
+		}
+	})
+
+	t.Run("create user if none match without id", func(t *testing.T) {
+		t.Parallel()
+		a, _ := setupUsersAPI(t)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/users", bytes.NewBufferString(`{"name":"Alice","email":"alice@example.com"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-None-Match", "*")
+		rec := httptest.NewRecorder()
+
+		a.Router().ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("upsert user", func(t *testing.T) {
+		t.Parallel()
+
+		upsertQuery := regexp.QuoteMeta(`ON CONFLICT (email) DO UPDATE SET name = EXCLUDED.name`)
+		columns := append(slices.Clone(userColumns), "inserted")
+		existingID := uuid.New()
+		for _, tc := range []struct {
+			name     string
+			inserted bool
+			status   int
+		}{
+			{name: "create", inserted: true, status: http.StatusCreated},
+			{name: "update", inserted: false, status: http.StatusOK},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+				a, dbMock := setupUsersAPI(t)
+
+				dbMock.ExpectQuery(upsertQuery).
+					WithArgs(sqlmock.AnyArg(), "Alice Smith", "alice@example.com", sqlmock.AnyArg(), sqlmock.AnyArg()).
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow(existingID, "Alice Smith", "alice@example.com", userCreatedAt, userCreatedAt, tc.inserted))
+
+				rec := httptest.NewRecorder()
+				a.Router().ServeHTTP(rec, jsonRequest(http.MethodPost, "/api/users?upsert=true", strings.NewReader(`{"name":"Alice Smith","email":"alice@example.com"}`)))
+
+				require.NoError(t, dbMock.ExpectationsWereMet())
+				assert.Equal(t, tc.status, rec.Code)
+				var res api.Response
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+				u := res.Response.(map[string]any)
+				assert.Equal(t, existingID.String(), u["id"])
+				assert.Equal(t, "Alice Smith", u["name"])
+				if tc.inserted {
+					assert.Equal(t, "/api/users/"+existingID.String(), rec.Header().Get("Location"))
+				} else {
+					assert.Empty(t, rec.Header().Get("Location"))
+				}
+			})
+		}
+	})
+
+	t.Run("upsert user with another user's id", func(t *testing.T) {
+		t.Parallel()
+
+		otherID := uuid.New()
+		body := `{"id":"` + otherID.String() + `","name":"Alice","email":"alice@example.com"}`
+		getQuery := regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)
+
+		t.Run("refused before writing", func(t *testing.T) {
+			t.Parallel()
+			a, dbMock := setupUsersAPI(t)
+
+			dbMock.ExpectQuery(getQuery).
+				WithArgs(otherID).
+				WillReturnRows(sqlmock.NewRows(userColumns).
+					AddRow(otherID, "Bob", "bob@example.com", userCreatedAt, userCreatedAt))
+
+			rec := httptest.NewRecorder()
+			a.Router().ServeHTTP(rec, jsonRequest(http.MethodPost, "/api/users?upsert=true", strings.NewReader(body)))
+
+			require.NoError(t, dbMock.ExpectationsWereMet())
+			assert.Equal(t, http.StatusConflict, rec.Code)
+		})
+
+		t.Run("taken concurrently", func(t *testing.T) {
+			t.Parallel()
+			a, dbMock := setupUsersAPI(t)
+
+			dbMock.ExpectQuery(getQuery).
+				WithArgs(otherID).
+				WillReturnError(sql.ErrNoRows)
+			dbMock.ExpectQuery(regexp.QuoteMeta(`ON CONFLICT (email) DO UPDATE SET name = EXCLUDED.name`)).
+				WithArgs(otherID, "Alice", "alice@example.com", sqlmock.AnyArg(), sqlmock.AnyArg()).
+				WillReturnError(&pq.Error{Code: "23505", Constraint: "users_pkey"})
+
+			rec := httptest.NewRecorder()
+			a.Router().ServeHTTP(rec, jsonRequest(http.MethodPost, "/api/users?upsert=true", strings.NewReader(body)))
+
+			require.NoError(t, dbMock.ExpectationsWereMet())
+			assert.Equal(t, http.StatusConflict, rec.Code)
+		})
+	})
+
+	t.Run("upsert user invalid param", func(t *testing.T) {
+		t.Parallel()
+		a, _ := setupUsersAPI(t)
+
+		rec := httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, jsonRequest(http.MethodPost, "/api/users?upsert=maybe", strings.NewReader(`{"name":"Alice","email":"alice@example.com"}`)))
+
 		assert.Equal(t, http.StatusBadRequest, rec.Code)
 	})
 
@@ -93,11 +317,11 @@ func TestUsersAPI(t *testing.T) {
 		a, dbMock := setupUsersAPI(t)
 
 		userID := uuid.New()
-		selectQuery := regexp.QuoteMeta(`SELECT id, name, email FROM users WHERE id = $1`)
+		selectQuery := regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)
 		dbMock.ExpectQuery(selectQuery).
 			WithArgs(userID).
-			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email"}).
-				AddRow(userID, "Bob", "bob@example.com"))
+			WillReturnRows(sqlmock.NewRows(userColumns).
+				AddRow(userID, "Bob", "bob@example.com", userCreatedAt, userCreatedAt))
 
 		req := httptest.NewRequest(http.MethodGet, "/api/users/"+userID.String(), nil)
 		rec := httptest.NewRecorder()
@@ -122,7 +346,7 @@ func TestUsersAPI(t *testing.T) {
 		a, dbMock := setupUsersAPI(t)
 
 		userID := uuid.New()
-		selectQuery := regexp.QuoteMeta(`SELECT id, name, email FROM users WHERE id = $1`)
+		selectQuery := regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)
 		dbMock.ExpectQuery(selectQuery).
 			WithArgs(userID).
 			WillReturnError(sql.ErrNoRows)
@@ -148,17 +372,102 @@ func TestUsersAPI(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, rec.Code)
 	})
 
+	t.Run("patch user name only", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupUsersAPI(t)
+
+		userID := uuid.New()
+		dbMock.ExpectQuery(regexp.QuoteMeta(`UPDATE users SET name = $1, updated_at = $2 WHERE id = $3 RETURNING id, name, email, created_at, updated_at`)).
+			WithArgs("Alicia", sqlmock.AnyArg(), userID).
+			WillReturnRows(sqlmock.NewRows(userColumns).
+				AddRow(userID, "Alicia", "alice@example.com", userCreatedAt, userCreatedAt.Add(time.Hour)))
+
+		req := httptest.NewRequest(http.MethodPatch, "/api/users/"+userID.String(), bytes.NewBufferString(`{"name":"Alicia"}`))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+
+		a.Router().ServeHTTP(rec, req)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var res api.Response
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+		u := res.Response.(map[string]any)
+		assert.Equal(t, "Alicia", u["name"])
+		assert.Equal(t, "alice@example.com", u["email"])
+		assert.Equal(t, "2024-01-01T00:00:00Z", u["created_at"])
+		assert.Equal(t, "2024-01-01T01:00:00Z", u["updated_at"])
+	})
+
+	t.Run("patch user email only", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupUsersAPI(t)
+
+		userID := uuid.New()
+		dbMock.ExpectQuery(regexp.QuoteMeta(`UPDATE users SET email = $1, updated_at = $2 WHERE id = $3 RETURNING id, name, email, created_at, updated_at`)).
+			WithArgs("alicia@example.com", sqlmock.AnyArg(), userID).
+			WillReturnRows(sqlmock.NewRows(userColumns).
+				AddRow(userID, "Alice", "alicia@example.com", userCreatedAt, userCreatedAt))
+
+		req := httptest.NewRequest(http.MethodPatch, "/api/users/"+userID.String(), bytes.NewBufferString(`{"email":"alicia@example.com"}`))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+
+		a.Router().ServeHTTP(rec, req)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("patch user rejects empty and invalid payloads", func(t *testing.T) {
+		t.Parallel()
+		a, _ := setupUsersAPI(t)
+
+		for _, body := range []string{`{}`, `{"email":"not-an-email"}`, `{"name":""}`} {
+			req := httptest.NewRequest(http.MethodPatch, "/api/users/"+uuid.New().String(), bytes.NewBufferString(body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+
+			a.Router().ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusUnprocessableEntity, rec.Code, body)
+		}
+	})
+
+	t.Run("patch user not found", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupUsersAPI(t)
+
+		userID := uuid.New()
+		dbMock.ExpectQuery(`UPDATE users SET name`).
+			WithArgs("Alicia", sqlmock.AnyArg(), userID).
+			WillReturnError(sql.ErrNoRows)
+
+		req := httptest.NewRequest(http.MethodPatch, "/api/users/"+userID.String(), bytes.NewBufferString(`{"name":"Alicia"}`))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+
+		a.Router().ServeHTTP(rec, req)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
 	t.Run("get users", func(t *testing.T) {
 		t.Parallel()
 		a, dbMock := setupUsersAPI(t)
 
 		userID1 := uuid.New()
 		userID2 := uuid.New()
-		selectQuery := regexp.QuoteMeta(`SELECT id, name, email FROM users`)
+		selectQuery := regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE users.active ORDER BY name, id LIMIT $1 OFFSET $2`)
 		dbMock.ExpectQuery(selectQuery).
-			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email"}).
-				AddRow(userID1, "Alice", "alice@example.com").
-				AddRow(userID2, "Bob", "bob@example.com"))
+			WithArgs(20, 0).
+			WillReturnRows(sqlmock.NewRows(userColumns).
+				AddRow(userID1, "Alice", "alice@example.com", userCreatedAt, userCreatedAt).
+				AddRow(userID2, "Bob", "bob@example.com", userCreatedAt, userCreatedAt))
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM users`)).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
 
 		req := httptest.NewRequest(http.MethodGet, "/api/users", nil)
 		rec := httptest.NewRecorder()
@@ -173,33 +482,153 @@ func TestUsersAPI(t *testing.T) {
 		assert.Equal(t, http.StatusOK, res.Status)
 		respMap, ok := res.Response.(map[string]any)
 		require.True(t, ok)
-		users, ok := respMap["users"].([]any)
+		users, ok := respMap["items"].([]any)
 		require.True(t, ok)
 		assert.Len(t, users, 2)
 	})
 
-	t.Run("create user slots", func(t *testing.T) {
+	t.Run("get users partial page", func(t *testing.T) {
 		t.Parallel()
 		a, dbMock := setupUsersAPI(t)
 
-		userID := uuid.New()
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE users.active ORDER BY name, id LIMIT $1 OFFSET $2`)).
+			WithArgs(2, 4).
+			WillReturnRows(sqlmock.NewRows(userColumns).
+				AddRow(uuid.New(), "Eve", "eve@example.com", userCreatedAt, userCreatedAt))
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM users`)).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(5))
 
-		getUserQuery := regexp.QuoteMeta(`SELECT id, name, email FROM users WHERE id = $1`)
-		dbMock.ExpectQuery(getUserQuery).
-			WithArgs(userID).
-			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email"}).
-				AddRow(userID, "Alice", "alice@example.com"))
+		rec := httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/users?limit=2&offset=4", nil))
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		var res struct {
+			Response api.PagedResponse[map[string]any] `json:"response"`
+		}
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+		assert.Len(t, res.Response.Items, 1)
+		assert.Equal(t, 2, res.Response.Limit)
+		assert.Equal(t, 4, res.Response.Offset)
+		assert.Equal(t, 5, res.Response.Total)
+		require.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("get users ndjson", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupUsersAPI(t)
+
+		userID1 := uuid.New()
+		userID2 := uuid.New()
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users`)).
+			WillReturnRows(sqlmock.NewRows(userColumns).
+				AddRow(userID1, "Alice", "alice@example.com", userCreatedAt, userCreatedAt).
+				AddRow(userID2, "Bob", "bob@example.com", userCreatedAt, userCreatedAt))
+
+		req := httptest.NewRequest(http.MethodGet, "/api/users", nil)
+		req.Header.Set("Accept", "application/x-ndjson")
+		rec := httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, req)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/x-ndjson", rec.Header().Get("Content-Type"))
+
+		lines := strings.Split(strings.TrimSuffix(rec.Body.String(), "\n"), "\n")
+		require.Len(t, lines, 2)
+		assert.JSONEq(t, fmt.Sprintf(`{"id":%q,"name":"Alice","email":"alice@example.com","created_at":"2024-01-01T00:00:00Z","updated_at":"2024-01-01T00:00:00Z"}`, userID1), lines[0])
+		assert.JSONEq(t, fmt.Sprintf(`{"id":%q,"name":"Bob","email":"bob@example.com","created_at":"2024-01-01T00:00:00Z","updated_at":"2024-01-01T00:00:00Z"}`, userID2), lines[1])
+	})
+
+	t.Run("get users ndjson query error", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupUsersAPI(t)
+
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users`)).
+			WillReturnError(sql.ErrConnDone)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/users", nil)
+		req.Header.Set("Accept", "application/x-ndjson")
+		rec := httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	})
+
+	t.Run("get users csv", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupUsersAPI(t)
+
+		userID1 := uuid.New()
+		userID2 := uuid.New()
+		// Same order as the JSON listing.
+		selectQuery := regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE users.active ORDER BY name, id`)
+		dbMock.ExpectQuery(selectQuery).
+			WillReturnRows(sqlmock.NewRows(userColumns).
+				AddRow(userID1, "Alice", "alice@example.com", userCreatedAt, userCreatedAt).
+				AddRow(userID2, "Bob, Jr.", "bob@example.com", userCreatedAt, userCreatedAt))
+
+		req := httptest.NewRequest(http.MethodGet, "/api/users.csv", nil)
+		rec := httptest.NewRecorder()
+
+		a.Router().ServeHTTP(rec, req)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "text/csv; charset=utf-8", rec.Header().Get("Content-Type"))
+		assert.Equal(t, `attachment; filename="users.csv"`, rec.Header().Get("Content-Disposition"))
+
+		expected := "id,name,email\n" +
+			userID1.String() + ",Alice,alice@example.com\n" +
+			userID2.String() + ",\"Bob, Jr.\",bob@example.com\n"
+		assert.Equal(t, expected, rec.Body.String())
+	})
+
+	t.Run("get users csv without users", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupUsersAPI(t)
+
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users`)).
+			WillReturnRows(sqlmock.NewRows(userColumns))
+
+		rec := httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/users.csv", nil))
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "id,name,email\n", rec.Body.String())
+	})
+
+	t.Run("get users csv query error", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupUsersAPI(t)
+
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users`)).
+			WillReturnError(sql.ErrConnDone)
+
+		rec := httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/users.csv", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	})
 
+	t.Run("create users bulk", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupUsersAPI(t)
+
+		insertQuery := regexp.QuoteMeta(`INSERT INTO users (id, name, email, created_at, updated_at) VALUES ($1, $2, $3, $4, $5)`)
 		dbMock.ExpectBegin()
-		dbMock.ExpectExec(regexp.QuoteMeta("INSERT INTO users_availability (user_id, start_time, end_time) VALUES ($1, $2, $3)")).
-			WithArgs(userID, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		dbMock.ExpectExec(insertQuery).
+			WithArgs(sqlmock.AnyArg(), "Alice", "alice@example.com", sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		dbMock.ExpectExec(insertQuery).
+			WithArgs(sqlmock.AnyArg(), "Bob", "bob@example.com", sqlmock.AnyArg(), sqlmock.AnyArg()).
 			WillReturnResult(sqlmock.NewResult(1, 1))
 		dbMock.ExpectCommit()
 
-		startTime := time.Now().Add(24 * time.Hour)
-		endTime := startTime.Add(2 * time.Hour)
-		body := `[{"start_time":` + fmt.Sprintf("%d", startTime.Unix()) + `,"end_time":` + fmt.Sprintf("%d", endTime.Unix()) + `}]`
-		req := httptest.NewRequest(http.MethodPost, "/api/users/"+userID.String()+"/slots", bytes.NewBufferString(body))
+		body := `[{"name": "Alice", "email": "alice@example.com"}, {"name": "Bob", "email": "bob@example.com"}]`
+		req := httptest.NewRequest(http.MethodPost, "/api/users/bulk", strings.NewReader(body))
 		req.Header.Set("Content-Type", "application/json")
 		rec := httptest.NewRecorder()
 
@@ -207,24 +636,112 @@ func TestUsersAPI(t *testing.T) {
 
 		require.NoError(t, dbMock.ExpectationsWereMet())
 		assert.Equal(t, http.StatusCreated, rec.Code)
+
+		var res api.Response
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+		users := res.Response.([]any)
+		require.Len(t, users, 2)
+		for _, u := range users {
+			assert.NotEqual(t, uuid.Nil.String(), u.(map[string]any)["id"])
+		}
 	})
 
-	t.Run("create user slots user not found", func(t *testing.T) {
+	t.Run("create users bulk invalid entry", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupUsersAPI(t)
+
+		body := `[{"name": "Alice", "email": "alice@example.com"}, {"name": "Bob", "email": "not-an-email"}]`
+		req := httptest.NewRequest(http.MethodPost, "/api/users/bulk", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+
+		a.Router().ServeHTTP(rec, req)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+		assert.JSONEq(t, `{"status":422,"response":{"error":"user 1: invalid email \"not-an-email\"","errors":[
+			{"field":"[1].email","message":"user 1: invalid email \"not-an-email\""}
+		]}}`, rec.Body.String())
+	})
+
+	t.Run("create users bulk duplicate", func(t *testing.T) {
+		t.Parallel()
+
+		for _, tc := range []struct {
+			name       string
+			constraint string
+			field      string
+		}{
+			{name: "email", constraint: "users_email_key", field: "[1].email"},
+			{name: "id", constraint: "users_pkey", field: "[1].id"},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+				a, dbMock := setupUsersAPI(t)
+
+				insertQuery := regexp.QuoteMeta(`INSERT INTO users (id, name, email, created_at, updated_at) VALUES ($1, $2, $3, $4, $5)`)
+				dbMock.ExpectBegin()
+				dbMock.ExpectExec(insertQuery).
+					WithArgs(sqlmock.AnyArg(), "Alice", "alice@example.com", sqlmock.AnyArg(), sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				dbMock.ExpectExec(insertQuery).
+					WithArgs(sqlmock.AnyArg(), "Bob", "alice@example.com", sqlmock.AnyArg(), sqlmock.AnyArg()).
+					WillReturnError(&pq.Error{Code: "23505", Constraint: tc.constraint})
+				dbMock.ExpectRollback()
+
+				body := `[{"name": "Alice", "email": "alice@example.com"}, {"name": "Bob", "email": "alice@example.com"}]`
+				rec := httptest.NewRecorder()
+				a.Router().ServeHTTP(rec, jsonRequest(http.MethodPost, "/api/users/bulk", strings.NewReader(body)))
+
+				require.NoError(t, dbMock.ExpectationsWereMet())
+				assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+				var res struct {
+					Response struct {
+						Errors []validation.FieldError `json:"errors"`
+					} `json:"response"`
+				}
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+				require.Len(t, res.Response.Errors, 1)
+				assert.Equal(t, tc.field, res.Response.Errors[0].Field)
+			})
+		}
+	})
+
+	t.Run("get user by email", func(t *testing.T) {
 		t.Parallel()
 		a, dbMock := setupUsersAPI(t)
 
 		userID := uuid.New()
-		getUserQuery := regexp.QuoteMeta(`SELECT id, name, email FROM users WHERE id = $1`)
-		dbMock.ExpectQuery(getUserQuery).
-			WithArgs(userID).
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE lower(email) = lower($1)`)).
+			WithArgs("alice@example.com").
+			WillReturnRows(sqlmock.NewRows(userColumns).
+				AddRow(userID, "Alice", "alice@example.com", userCreatedAt, userCreatedAt))
+
+		req := httptest.NewRequest(http.MethodGet, "/api/users?email=%20Alice@Example.com%20", nil)
+		rec := httptest.NewRecorder()
+
+		a.Router().ServeHTTP(rec, req)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var res api.Response
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+		u := res.Response.(map[string]any)
+		assert.Equal(t, userID.String(), u["id"])
+	})
+
+	t.Run("get user by email not found", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupUsersAPI(t)
+
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE lower(email) = lower($1)`)).
+			WithArgs("nobody@example.com").
 			WillReturnError(sql.ErrNoRows)
 
-		// Use epoch time (int64) for start_time and end_time
-		startTime := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
-		endTime := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
-		body := `[{"start_time":` + fmt.Sprintf("%d", startTime.Unix()) + `,"end_time":` + fmt.Sprintf("%d", endTime.Unix()) + `}]`
-		req := httptest.NewRequest(http.MethodPost, "/api/users/"+userID.String()+"/slots", bytes.NewBufferString(body))
-		req.Header.Set("Content-Type", "application/json")
+		req := httptest.NewRequest(http.MethodGet, "/api/users?email=nobody@example.com", nil)
 		rec := httptest.NewRecorder()
 
 		a.Router().ServeHTTP(rec, req)
@@ -233,17 +750,1008 @@ func TestUsersAPI(t *testing.T) {
 		assert.Equal(t, http.StatusNotFound, rec.Code)
 	})
 
+	t.Run("get user by malformed email", func(t *testing.T) {
+		t.Parallel()
+		a, _ := setupUsersAPI(t)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/users?email=not-an-email", nil)
+		rec := httptest.NewRecorder()
+
+		a.Router().ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("create user slots", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupUsersAPI(t)
+
+		userID := uuid.New()
+
+		getUserQuery := regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)
+		dbMock.ExpectQuery(getUserQuery).
+			WithArgs(userID).
+			WillReturnRows(sqlmock.NewRows(userColumns).
+				AddRow(userID, "Alice", "alice@example.com", userCreatedAt, userCreatedAt))
+
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec(regexp.QuoteMeta(`SELECT 1 FROM users WHERE id = $1 FOR UPDATE`)).
+			WithArgs(userID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT start_time, end_time FROM users_availability WHERE user_id = $1`)).
+			WithArgs(userID).
+			WillReturnRows(sqlmock.NewRows([]string{"start_time", "end_time"}))
+		dbMock.ExpectExec(regexp.QuoteMeta("INSERT INTO users_availability (user_id, start_time, end_time) VALUES ($1, $2, $3)")).
+			WithArgs(userID, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		dbMock.ExpectCommit()
+
+		startTime := time.Now().Add(24 * time.Hour)
+		endTime := startTime.Add(2 * time.Hour)
+		body := `[{"start_time":` + fmt.Sprintf("%d", startTime.Unix()) + `,"end_time":` + fmt.Sprintf("%d", endTime.Unix()) + `}]`
+		req := httptest.NewRequest(http.MethodPost, "/api/users/"+userID.String()+"/slots", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+
+		a.Router().ServeHTTP(rec, req)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		assert.Equal(t, http.StatusCreated, rec.Code)
+	})
+
+	t.Run("validate user slots", func(t *testing.T) {
+		t.Parallel()
+
+		at := func(hour int) int64 { return time.Date(2030, 1, 7, hour, 0, 0, 0, time.UTC).Unix() }
+		slot := func(start, end int) string {
+			return fmt.Sprintf(`{"start_time":%d,"end_time":%d}`, at(start), at(end))
+		}
+		for _, tc := range []struct {
+			name   string
+			query  string
+			body   string
+			status int
+			want   string
+		}{
+			{
+				name:   "valid slots are answered sorted",
+				body:   "[" + slot(14, 16) + "," + slot(9, 12) + "]",
+				status: http.StatusOK,
+				want:   `{"status":200,"response":[` + slot(9, 12) + "," + slot(14, 16) + `]}`,
+			},
+			{
+				name:   "overlapping and reversed slots are listed",
+				body:   "[" + slot(9, 12) + "," + slot(11, 13) + "," + slot(16, 15) + "," + slot(17, 19) + "]",
+				status: http.StatusUnprocessableEntity,
+				want: `{"status":422,"response":{"error":"slot 2: end time must be after start time; slot 1: overlaps slot 0; slot 3: overlaps existing availability from 2030-01-07T18:00:00Z to 2030-01-07T20:00:00Z","errors":[
+					{"field":"[2].end_time","message":"slot 2: end time must be after start time"},
+					{"field":"[1]","message":"slot 1: overlaps slot 0"},
+					{"field":"[3]","message":"slot 3: overlaps existing availability from 2030-01-07T18:00:00Z to 2030-01-07T20:00:00Z"}]}}`,
+			},
+			{
+				name:   "merge joins overlapping slots",
+				query:  "?on_overlap=merge",
+				body:   "[" + slot(9, 12) + "," + slot(11, 13) + "," + slot(17, 19) + "]",
+				status: http.StatusOK,
+				want:   `{"status":200,"response":[` + slot(9, 13) + "," + slot(17, 20) + `]}`,
+			},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+				a, dbMock := setupUsersAPI(t)
+
+				userID := uuid.New()
+				dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+					WithArgs(userID).
+					WillReturnRows(sqlmock.NewRows(userColumns).
+						AddRow(userID, "Alice", "alice@example.com", userCreatedAt, userCreatedAt))
+				// Nothing is written, the existing slots are only read.
+				dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT start_time, end_time FROM users_availability WHERE user_id = $1`)).
+					WithArgs(userID).
+					WillReturnRows(sqlmock.NewRows([]string{"start_time", "end_time"}).
+						AddRow(time.Unix(at(18), 0).UTC(), time.Unix(at(20), 0).UTC()))
+
+				rec := httptest.NewRecorder()
+				a.Router().ServeHTTP(rec, jsonRequest(http.MethodPost, "/api/users/"+userID.String()+"/slots/validate"+tc.query, strings.NewReader(tc.body)))
+
+				require.NoError(t, dbMock.ExpectationsWereMet())
+				assert.Equal(t, tc.status, rec.Code, rec.Body.String())
+				assert.JSONEq(t, tc.want, rec.Body.String())
+			})
+		}
+	})
+
+	t.Run("validate user slots invalid requests", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupUsersAPI(t)
+
+		userID := uuid.New()
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+			WithArgs(userID).
+			WillReturnRows(sqlmock.NewRows(userColumns))
+
+		for url, status := range map[string]int{
+			"/api/users/bad-id/slots/validate":                               http.StatusBadRequest,
+			"/api/users/" + userID.String() + "/slots/validate?on_overlap=x": http.StatusBadRequest,
+			"/api/users/" + userID.String() + "/slots/validate":              http.StatusNotFound,
+		} {
+			rec := httptest.NewRecorder()
+			a.Router().ServeHTTP(rec, jsonRequest(http.MethodPost, url, strings.NewReader(`[]`)))
+			assert.Equal(t, status, rec.Code, url)
+		}
+		require.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("create user slots by mode", func(t *testing.T) {
+		t.Parallel()
+
+		startTime := time.Date(2030, 1, 7, 9, 0, 0, 0, time.UTC)
+		endTime := startTime.Add(2 * time.Hour)
+		body := fmt.Sprintf(`[{"start_time":%d,"end_time":%d}]`, startTime.Unix(), endTime.Unix())
+		insertQuery := regexp.QuoteMeta("INSERT INTO users_availability (user_id, start_time, end_time) VALUES ($1, $2, $3)")
+
+		for _, tc := range []struct {
+			name   string
+			mode   string
+			expect func(dbMock sqlmock.Sqlmock, userID uuid.UUID)
+			status int
+		}{
+			{
+				name: "append keeps the existing slots",
+				mode: "append",
+				expect: func(dbMock sqlmock.Sqlmock, userID uuid.UUID) {
+					dbMock.ExpectBegin()
+					dbMock.ExpectExec(regexp.QuoteMeta(`SELECT 1 FROM users WHERE id = $1 FOR UPDATE`)).
+						WithArgs(userID).
+						WillReturnResult(sqlmock.NewResult(0, 1))
+					dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT start_time, end_time FROM users_availability WHERE user_id = $1`)).
+						WithArgs(userID).
+						WillReturnRows(sqlmock.NewRows([]string{"start_time", "end_time"}).
+							AddRow(startTime.Add(-24*time.Hour), endTime.Add(-24*time.Hour)))
+					dbMock.ExpectExec(insertQuery).WithArgs(userID, startTime, endTime).WillReturnResult(sqlmock.NewResult(1, 1))
+					dbMock.ExpectCommit()
+				},
+				status: http.StatusCreated,
+			},
+			{
+				name: "replace deletes the existing slots first",
+				mode: "replace",
+				expect: func(dbMock sqlmock.Sqlmock, userID uuid.UUID) {
+					dbMock.ExpectBegin()
+					dbMock.ExpectExec(regexp.QuoteMeta(`DELETE FROM users_availability WHERE user_id = $1`)).
+						WithArgs(userID).
+						WillReturnResult(sqlmock.NewResult(0, 1))
+					dbMock.ExpectExec(insertQuery).WithArgs(userID, startTime, endTime).WillReturnResult(sqlmock.NewResult(1, 1))
+					dbMock.ExpectCommit()
+				},
+				status: http.StatusOK,
+			},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+				a, dbMock := setupUsersAPI(t)
+
+				userID := uuid.New()
+				dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+					WithArgs(userID).
+					WillReturnRows(sqlmock.NewRows(userColumns).AddRow(userID, "Alice", "alice@example.com", userCreatedAt, userCreatedAt))
+				tc.expect(dbMock, userID)
+
+				rec := httptest.NewRecorder()
+				a.Router().ServeHTTP(rec, jsonRequest(http.MethodPost, "/api/users/"+userID.String()+"/slots?mode="+tc.mode, strings.NewReader(body)))
+
+				require.NoError(t, dbMock.ExpectationsWereMet())
+				require.Equal(t, tc.status, rec.Code, rec.Body.String())
+				assert.JSONEq(t, fmt.Sprintf(`{"status":%d,"response":%s}`, tc.status, body), rec.Body.String())
+			})
+		}
+	})
+
+	t.Run("create user slots duplicated concurrently", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupUsersAPI(t)
+
+		userID := uuid.New()
+		startTime := time.Date(2030, 1, 7, 9, 0, 0, 0, time.UTC)
+		endTime := startTime.Add(2 * time.Hour)
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+			WithArgs(userID).
+			WillReturnRows(sqlmock.NewRows(userColumns).AddRow(userID, "Alice", "alice@example.com", userCreatedAt, userCreatedAt))
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec(regexp.QuoteMeta(`SELECT 1 FROM users WHERE id = $1 FOR UPDATE`)).
+			WithArgs(userID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT start_time, end_time FROM users_availability WHERE user_id = $1`)).
+			WithArgs(userID).
+			WillReturnRows(sqlmock.NewRows([]string{"start_time", "end_time"}))
+		dbMock.ExpectExec(regexp.QuoteMeta("INSERT INTO users_availability (user_id, start_time, end_time) VALUES ($1, $2, $3)")).
+			WithArgs(userID, startTime, endTime).
+			WillReturnError(&pq.Error{Code: "23505"})
+		dbMock.ExpectRollback()
+
+		body := fmt.Sprintf(`[{"start_time":%d,"end_time":%d}]`, startTime.Unix(), endTime.Unix())
+		rec := httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, jsonRequest(http.MethodPost, "/api/users/"+userID.String()+"/slots", strings.NewReader(body)))
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		assert.JSONEq(t, fmt.Sprintf(`{"status":409,"response":{"error":"slot already exists","conflicts":%s}}`, body), rec.Body.String())
+	})
+
+	t.Run("create user slots invalid mode", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupUsersAPI(t)
+
+		userID := uuid.New()
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+			WithArgs(userID).
+			WillReturnRows(sqlmock.NewRows(userColumns).AddRow(userID, "Alice", "alice@example.com", userCreatedAt, userCreatedAt))
+
+		rec := httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, jsonRequest(http.MethodPost, "/api/users/"+userID.String()+"/slots?mode=merge", strings.NewReader(`[]`)))
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("create user slots overlapping existing ones", func(t *testing.T) {
+		t.Parallel()
+
+		userID := uuid.New()
+		existingStart := time.Date(2030, 3, 1, 10, 0, 0, 0, time.UTC)
+		existingEnd := existingStart.Add(2 * time.Hour)
+		newStart, newEnd := existingStart.Add(time.Hour), existingEnd.Add(time.Hour)
+		body := fmt.Sprintf(`[{"start_time":%d,"end_time":%d}]`, newStart.Unix(), newEnd.Unix())
+
+		setup := func(t *testing.T) (*api.API, sqlmock.Sqlmock) {
+			a, dbMock := setupUsersAPI(t)
+			dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+				WithArgs(userID).
+				WillReturnRows(sqlmock.NewRows(userColumns).AddRow(userID, "Alice", "alice@example.com", userCreatedAt, userCreatedAt))
+			dbMock.ExpectBegin()
+			dbMock.ExpectExec(regexp.QuoteMeta(`SELECT 1 FROM users WHERE id = $1 FOR UPDATE`)).
+				WithArgs(userID).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT start_time, end_time FROM users_availability WHERE user_id = $1`)).
+				WithArgs(userID).
+				WillReturnRows(sqlmock.NewRows([]string{"start_time", "end_time"}).AddRow(existingStart, existingEnd))
+			return a, dbMock
+		}
+
+		t.Run("reject", func(t *testing.T) {
+			t.Parallel()
+			a, dbMock := setup(t)
+			dbMock.ExpectRollback()
+
+			req := httptest.NewRequest(http.MethodPost, "/api/users/"+userID.String()+"/slots", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+
+			a.Router().ServeHTTP(rec, req)
+
+			require.NoError(t, dbMock.ExpectationsWereMet())
+			assert.Equal(t, http.StatusConflict, rec.Code)
+			assert.JSONEq(t, fmt.Sprintf(`{"status":409,"response":{"error":"slots overlap existing availability","conflicts":[{"start_time":%d,"end_time":%d}]}}`,
+				existingStart.Unix(), existingEnd.Unix()), rec.Body.String())
+		})
+
+		t.Run("merge", func(t *testing.T) {
+			t.Parallel()
+			a, dbMock := setup(t)
+			dbMock.ExpectExec(regexp.QuoteMeta(`DELETE FROM users_availability WHERE user_id = $1 AND start_time = $2 AND end_time = $3`)).
+				WithArgs(userID, existingStart, existingEnd).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			dbMock.ExpectExec(regexp.QuoteMeta("INSERT INTO users_availability (user_id, start_time, end_time) VALUES ($1, $2, $3)")).
+				WithArgs(userID, existingStart, newEnd).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+			dbMock.ExpectCommit()
+
+			req := httptest.NewRequest(http.MethodPost, "/api/users/"+userID.String()+"/slots?on_overlap=merge", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+
+			a.Router().ServeHTTP(rec, req)
+
+			require.NoError(t, dbMock.ExpectationsWereMet())
+			assert.Equal(t, http.StatusCreated, rec.Code)
+			assert.JSONEq(t, fmt.Sprintf(`{"status":201,"response":[{"start_time":%d,"end_time":%d}]}`,
+				existingStart.Unix(), newEnd.Unix()), rec.Body.String())
+		})
+
+		t.Run("invalid mode", func(t *testing.T) {
+			t.Parallel()
+			a, dbMock := setupUsersAPI(t)
+			dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+				WithArgs(userID).
+				WillReturnRows(sqlmock.NewRows(userColumns).AddRow(userID, "Alice", "alice@example.com", userCreatedAt, userCreatedAt))
+
+			req := httptest.NewRequest(http.MethodPost, "/api/users/"+userID.String()+"/slots?on_overlap=ignore", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+
+			a.Router().ServeHTTP(rec, req)
+
+			require.NoError(t, dbMock.ExpectationsWereMet())
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		})
+	})
+
+	t.Run("create user slots user not found", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupUsersAPI(t)
+
+		userID := uuid.New()
+		getUserQuery := regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)
+		dbMock.ExpectQuery(getUserQuery).
+			WithArgs(userID).
+			WillReturnError(sql.ErrNoRows)
+
+		// Use epoch time (int64) for start_time and end_time
+		startTime := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+		endTime := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+		body := `[{"start_time":` + fmt.Sprintf("%d", startTime.Unix()) + `,"end_time":` + fmt.Sprintf("%d", endTime.Unix()) + `}]`
+		req := httptest.NewRequest(http.MethodPost, "/api/users/"+userID.String()+"/slots", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+
+		a.Router().ServeHTTP(rec, req)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("get user conflicts", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupUsersAPI(t)
+
+		userID := uuid.New()
+		eventID := uuid.New()
+		start := time.Date(2030, 1, 1, 10, 0, 0, 0, time.UTC)
+		end := start.Add(2 * time.Hour)
+		slotsJSON := []byte(`[{"start_time":"` + start.Add(time.Hour).Format(time.RFC3339) + `","end_time":"` + end.Add(time.Hour).Format(time.RFC3339) + `"}]`)
+
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+			WithArgs(userID).
+			WillReturnRows(sqlmock.NewRows(userColumns).AddRow(userID, "Alice", "alice@example.com", userCreatedAt, userCreatedAt))
+		dbMock.ExpectQuery(regexp.QuoteMeta(`FROM events WHERE user_id = $1 AND deleted_at IS NULL`)).
+			WithArgs(userID, start, end).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}).
+				AddRow(eventID, "Standup", 2, userID, slotsJSON, "UTC", start, "public"))
+
+		url := fmt.Sprintf("/api/users/%s/conflicts?from=%d&to=%d", userID, start.Unix(), end.Unix())
+		req := httptest.NewRequest(http.MethodGet, url, nil)
+		rec := httptest.NewRecorder()
+
+		a.Router().ServeHTTP(rec, req)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var res api.Response
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+		conflicts := res.Response.(map[string]any)["conflicts"].([]any)
+		require.Len(t, conflicts, 1)
+		assert.Equal(t, eventID.String(), conflicts[0].(map[string]any)["id"])
+	})
+
+	t.Run("get user events", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupUsersAPI(t)
+
+		aliceID, bobID := uuid.New(), uuid.New()
+		seeded := map[uuid.UUID][]string{aliceID: {"Planning", "Retro"}, bobID: {"Standup"}}
+		names := map[uuid.UUID]string{aliceID: "Alice", bobID: "Bob"}
+
+		for _, organizerID := range []uuid.UUID{aliceID, bobID} {
+			dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+				WithArgs(organizerID).
+				WillReturnRows(sqlmock.NewRows(userColumns).AddRow(organizerID, names[organizerID], "x@example.com", userCreatedAt, userCreatedAt))
+			rows := sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"})
+			for _, title := range seeded[organizerID] {
+				rows.AddRow(uuid.New(), title, 1, organizerID, []byte("[]"), "UTC", time.Now(), "public")
+			}
+			dbMock.ExpectQuery(regexp.QuoteMeta(`FROM events
+	WHERE user_id = $1 AND deleted_at IS NULL
+	ORDER BY created_at, id
+	LIMIT $2 OFFSET $3`)).
+				WithArgs(organizerID, 20, 0).
+				WillReturnRows(rows)
+			dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM events WHERE deleted_at IS NULL AND user_id = $1`)).
+				WithArgs(organizerID).
+				WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(len(seeded[organizerID])))
+
+			rec := httptest.NewRecorder()
+			a.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/users/"+organizerID.String()+"/events", nil))
+			require.Equal(t, http.StatusOK, rec.Code)
+
+			var res struct {
+				Response struct {
+					Items []struct {
+						Title       string `json:"title"`
+						OrganizerID string `json:"organizer_id"`
+					} `json:"items"`
+					Total int `json:"total"`
+				} `json:"response"`
+			}
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+			assert.Equal(t, len(seeded[organizerID]), res.Response.Total)
+			var titles []string
+			for _, evt := range res.Response.Items {
+				assert.Equal(t, organizerID.String(), evt.OrganizerID)
+				titles = append(titles, evt.Title)
+			}
+			assert.Equal(t, seeded[organizerID], titles)
+		}
+		require.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("get user events unknown user", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupUsersAPI(t)
+
+		userID := uuid.New()
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+			WithArgs(userID).
+			WillReturnError(sql.ErrNoRows)
+
+		rec := httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/users/"+userID.String()+"/events?limit=5", nil))
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		require.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("get user events invalid pagination", func(t *testing.T) {
+		t.Parallel()
+		a, _ := setupUsersAPI(t)
+
+		rec := httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/users/"+uuid.NewString()+"/events?limit=abc", nil))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("get user gaps", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupUsersAPI(t)
+
+		userID := uuid.New()
+		from := time.Date(2030, 1, 1, 9, 0, 0, 0, time.UTC)
+		to := from.Add(8 * time.Hour)
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+			WithArgs(userID).
+			WillReturnRows(sqlmock.NewRows(userColumns).AddRow(userID, "Alice", "alice@example.com", userCreatedAt, userCreatedAt))
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT start_time, end_time FROM users_availability WHERE user_id = $1`)).
+			WithArgs(userID).
+			WillReturnRows(sqlmock.NewRows([]string{"start_time", "end_time"}).
+				AddRow(from.Add(time.Hour), from.Add(3*time.Hour)))
+
+		url := fmt.Sprintf("/api/users/%s/gaps?from=%d&to=%d", userID, from.Unix(), to.Unix())
+		rec := httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, url, nil))
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, fmt.Sprintf(`{"status":200,"response":{"gaps":[{"start_time":%d,"end_time":%d},{"start_time":%d,"end_time":%d}]}}`,
+			from.Unix(), from.Add(time.Hour).Unix(), from.Add(3*time.Hour).Unix(), to.Unix()), rec.Body.String())
+		require.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("get user gaps invalid window", func(t *testing.T) {
+		t.Parallel()
+		a, _ := setupUsersAPI(t)
+
+		rec := httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/users/"+uuid.NewString()+"/gaps?from=2000&to=1000", nil))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("get user availability summary", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupUsersAPI(t)
+
+		userID := uuid.New()
+		monday := time.Date(2030, 1, 7, 9, 0, 0, 0, time.UTC)
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+			WithArgs(userID).
+			WillReturnRows(sqlmock.NewRows(userColumns).AddRow(userID, "Alice", "alice@example.com", userCreatedAt, userCreatedAt))
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT start_time, end_time FROM users_availability WHERE user_id = $1`)).
+			WithArgs(userID).
+			WillReturnRows(sqlmock.NewRows([]string{"start_time", "end_time"}).
+				AddRow(monday, monday.Add(3*time.Hour)).
+				AddRow(monday.AddDate(0, 0, 2), monday.AddDate(0, 0, 2).Add(90*time.Minute)))
+
+		rec := httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/users/"+userID.String()+"/availability/summary", nil))
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"status":200,"response":{"total_hours":4.5,"weekdays":[
+			{"weekday":0,"hours":0},{"weekday":1,"hours":3},{"weekday":2,"hours":0},{"weekday":3,"hours":1.5},
+			{"weekday":4,"hours":0},{"weekday":5,"hours":0},{"weekday":6,"hours":0}
+		]}}`, rec.Body.String())
+	})
+
+	t.Run("get user availability summary not found", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupUsersAPI(t)
+
+		userID := uuid.New()
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+			WithArgs(userID).
+			WillReturnError(sql.ErrNoRows)
+
+		rec := httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/users/"+userID.String()+"/availability/summary", nil))
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("get user calendar", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupUsersAPI(t)
+
+		userID := uuid.New()
+		tuesday := time.Date(2030, 1, 8, 9, 30, 0, 0, time.UTC)
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+			WithArgs(userID).
+			WillReturnRows(sqlmock.NewRows(userColumns).AddRow(userID, "Alice", "alice@example.com", userCreatedAt, userCreatedAt))
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT start_time, end_time FROM users_availability WHERE user_id = $1`)).
+			WithArgs(userID).
+			WillReturnRows(sqlmock.NewRows([]string{"start_time", "end_time"}).
+				AddRow(tuesday, tuesday.Add(2*time.Hour)).
+				AddRow(tuesday.AddDate(0, 0, 7), tuesday.AddDate(0, 0, 7).Add(time.Hour)))
+
+		rec := httptest.NewRecorder()
+		// Any day of the week selects it, here the Thursday.
+		a.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/users/"+userID.String()+"/calendar?week=2030-01-10", nil))
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		require.Equal(t, http.StatusOK, rec.Code)
+		var res struct {
+			Response user.WeekCalendar `json:"response"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+		assert.Equal(t, "2030-01-07", res.Response.WeekStart)
+		require.Len(t, res.Response.Days, 7)
+		assert.Equal(t, "2030-01-08", res.Response.Days[1].Date)
+		var marked []string
+		for _, day := range res.Response.Days {
+			for hour, set := range day.Hours {
+				if set {
+					marked = append(marked, fmt.Sprintf("%s %02d", day.Date, hour))
+				}
+			}
+		}
+		// 09:30 to 11:30 overlaps three cells, next week's slot is left out.
+		assert.Equal(t, []string{"2030-01-08 09", "2030-01-08 10", "2030-01-08 11"}, marked)
+	})
+
+	t.Run("get user calendar invalid week", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupUsersAPI(t)
+
+		rec := httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/users/"+uuid.NewString()+"/calendar?week=next", nil))
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("get user available", func(t *testing.T) {
+		t.Parallel()
+
+		start := time.Date(2030, 1, 7, 10, 0, 0, 0, time.UTC)
+		end := start.Add(2 * time.Hour)
+		for _, tc := range []struct {
+			name      string
+			available bool
+		}{
+			{name: "available", available: true},
+			{name: "not available", available: false},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+				a, dbMock := setupUsersAPI(t)
+
+				userID := uuid.New()
+				dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+					WithArgs(userID).
+					WillReturnRows(sqlmock.NewRows(userColumns).AddRow(userID, "Alice", "alice@example.com", userCreatedAt, userCreatedAt))
+				slotUsers := sqlmock.NewRows(userColumns)
+				if tc.available {
+					slotUsers.AddRow(userID, "Alice", "alice@example.com", userCreatedAt, userCreatedAt)
+				}
+				// Deactivated users are not filtered out, the user is asked for by ID.
+				dbMock.ExpectQuery(regexp.QuoteMeta(`make_interval(hours => $3) AND users.id = ANY($4)`)).
+					WithArgs(start, end, 1, sqlmock.AnyArg()).
+					WillReturnRows(slotUsers)
+				dbMock.ExpectQuery(`FROM users_recurring_availability`).
+					WithArgs(start, end, sqlmock.AnyArg()).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "created_at", "updated_at", "weekday", "start_minute", "end_minute", "valid_from", "valid_until"}))
+
+				rec := httptest.NewRecorder()
+				url := fmt.Sprintf("/api/users/%s/available?start=%d&end=%d&duration_hours=1", userID, start.Unix(), end.Unix())
+				a.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, url, nil))
+
+				require.NoError(t, dbMock.ExpectationsWereMet())
+				require.Equal(t, http.StatusOK, rec.Code)
+				assert.JSONEq(t, fmt.Sprintf(`{"status":200,"response":{"available":%t}}`, tc.available), rec.Body.String())
+			})
+		}
+	})
+
+	t.Run("get user available not found", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupUsersAPI(t)
+
+		userID := uuid.New()
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+			WithArgs(userID).
+			WillReturnError(sql.ErrNoRows)
+
+		rec := httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/users/"+userID.String()+"/available?start=1000&end=2000", nil))
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("get user available invalid query", func(t *testing.T) {
+		t.Parallel()
+		a, _ := setupUsersAPI(t)
+
+		for _, query := range []string{"end=2000", "start=1000", "start=2000&end=1000", "start=1000&end=2000&duration_hours=-1"} {
+			rec := httptest.NewRecorder()
+			a.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/users/"+uuid.NewString()+"/available?"+query, nil))
+			assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+		}
+	})
+
+	t.Run("get user next available", func(t *testing.T) {
+		t.Parallel()
+
+		after := time.Date(2030, 1, 7, 9, 0, 0, 0, time.UTC)
+		start := after.Add(26 * time.Hour)
+		for _, tc := range []struct {
+			name   string
+			rows   *sqlmock.Rows
+			status int
+			body   string
+		}{
+			{
+				name:   "match",
+				rows:   sqlmock.NewRows([]string{"start_time", "end_time"}).AddRow(start, start.Add(3*time.Hour)),
+				status: http.StatusOK,
+				body:   fmt.Sprintf(`{"status":200,"response":{"start_time":%d,"end_time":%d}}`, start.Unix(), start.Add(3*time.Hour).Unix()),
+			},
+			{
+				name:   "no match",
+				rows:   sqlmock.NewRows([]string{"start_time", "end_time"}),
+				status: http.StatusNotFound,
+				body:   `{"status":404,"response":"no available slot found"}`,
+			},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+				a, dbMock := setupUsersAPI(t)
+
+				userID := uuid.New()
+				dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+					WithArgs(userID).
+					WillReturnRows(sqlmock.NewRows(userColumns).AddRow(userID, "Alice", "alice@example.com", userCreatedAt, userCreatedAt))
+				dbMock.ExpectQuery(regexp.QuoteMeta(`ORDER BY start_time
+	LIMIT 1`)).
+					WithArgs(userID, after, 2).
+					WillReturnRows(tc.rows)
+
+				rec := httptest.NewRecorder()
+				url := fmt.Sprintf("/api/users/%s/next-available?after=%d&duration_hours=2", userID, after.Unix())
+				a.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, url, nil))
+
+				require.NoError(t, dbMock.ExpectationsWereMet())
+				assert.Equal(t, tc.status, rec.Code)
+				assert.JSONEq(t, tc.body, rec.Body.String())
+			})
+		}
+	})
+
+	t.Run("get user next available not found", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupUsersAPI(t)
+
+		userID := uuid.New()
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+			WithArgs(userID).
+			WillReturnError(sql.ErrNoRows)
+
+		rec := httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/users/"+userID.String()+"/next-available", nil))
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		assert.JSONEq(t, `{"status":404,"response":"user not found"}`, rec.Body.String())
+	})
+
+	t.Run("get user next available invalid query", func(t *testing.T) {
+		t.Parallel()
+		a, _ := setupUsersAPI(t)
+
+		for _, query := range []string{"after=tomorrow", "duration_hours=two"} {
+			rec := httptest.NewRecorder()
+			a.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/users/"+uuid.NewString()+"/next-available?"+query, nil))
+			assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+		}
+	})
+
+	t.Run("get user eligible events", func(t *testing.T) {
+		t.Parallel()
+
+		eligibleColumns := []string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "id", "name", "email", "eligible_slots"}
+		start := time.Date(2030, 1, 1, 10, 0, 0, 0, time.UTC)
+		slotsJSON := []byte(`[{"start_time":"` + start.Format(time.RFC3339) + `","end_time":"` + start.Add(2*time.Hour).Format(time.RFC3339) + `"},` +
+			`{"start_time":"` + start.Add(24*time.Hour).Format(time.RFC3339) + `","end_time":"` + start.Add(26*time.Hour).Format(time.RFC3339) + `"}]`)
+		eligibleJSON := []byte(`[{"start_time":"` + start.Add(24*time.Hour).Format(time.RFC3339) + `","end_time":"` + start.Add(26*time.Hour).Format(time.RFC3339) + `"}]`)
+
+		for _, tc := range []struct {
+			name   string
+			rows   func(organizerID uuid.UUID) *sqlmock.Rows
+			events int
+		}{
+			{
+				name: "fits some",
+				rows: func(organizerID uuid.UUID) *sqlmock.Rows {
+					return sqlmock.NewRows(eligibleColumns).
+						AddRow(uuid.New(), "Standup", 2, organizerID, slotsJSON, "UTC", start, organizerID, "Bob", "bob@example.com", eligibleJSON)
+				},
+				events: 1,
+			},
+			{
+				name:   "fits none",
+				rows:   func(uuid.UUID) *sqlmock.Rows { return sqlmock.NewRows(eligibleColumns) },
+				events: 0,
+			},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+				a, dbMock := setupUsersAPI(t)
+
+				userID, organizerID := uuid.New(), uuid.New()
+				dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+					WithArgs(userID).
+					WillReturnRows(sqlmock.NewRows(userColumns).AddRow(userID, "Alice", "alice@example.com", userCreatedAt, userCreatedAt))
+				dbMock.ExpectQuery(regexp.QuoteMeta(`CROSS JOIN LATERAL jsonb_array_elements(events.slots) AS slot(value)`)).
+					WithArgs(userID, sqlmock.AnyArg()).
+					WillReturnRows(tc.rows(organizerID))
+
+				req := httptest.NewRequest(http.MethodGet, "/api/users/"+userID.String()+"/eligible-events", nil)
+				rec := httptest.NewRecorder()
+
+				a.Router().ServeHTTP(rec, req)
+
+				require.NoError(t, dbMock.ExpectationsWereMet())
+				assert.Equal(t, http.StatusOK, rec.Code)
+
+				var res api.Response
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+				events := res.Response.(map[string]any)["events"].([]any)
+				require.Len(t, events, tc.events)
+				if tc.events > 0 {
+					e := events[0].(map[string]any)
+					assert.Equal(t, organizerID.String(), e["organizer_id"])
+					assert.Len(t, e["slots"], 2)
+					eligible := e["eligible_slots"].([]any)
+					require.Len(t, eligible, 1)
+					assert.InDelta(t, float64(start.Add(24*time.Hour).Unix()), eligible[0].(map[string]any)["start_time"], 0)
+				}
+			})
+		}
+	})
+
+	t.Run("get user eligible events user not found", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupUsersAPI(t)
+
+		userID := uuid.New()
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+			WithArgs(userID).
+			WillReturnError(sql.ErrNoRows)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/users/"+userID.String()+"/eligible-events", nil)
+		rec := httptest.NewRecorder()
+
+		a.Router().ServeHTTP(rec, req)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("get user conflicts none", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupUsersAPI(t)
+
+		userID := uuid.New()
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+			WithArgs(userID).
+			WillReturnRows(sqlmock.NewRows(userColumns).AddRow(userID, "Alice", "alice@example.com", userCreatedAt, userCreatedAt))
+		dbMock.ExpectQuery(regexp.QuoteMeta(`FROM events WHERE user_id = $1 AND deleted_at IS NULL`)).
+			WithArgs(userID, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}))
+
+		req := httptest.NewRequest(http.MethodGet, "/api/users/"+userID.String()+"/conflicts?from=1000&to=2000", nil)
+		rec := httptest.NewRecorder()
+
+		a.Router().ServeHTTP(rec, req)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var res api.Response
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+		assert.Equal(t, map[string]any{"conflicts": []any{}}, res.Response)
+	})
+
+	t.Run("get user conflicts invalid window", func(t *testing.T) {
+		t.Parallel()
+		a, _ := setupUsersAPI(t)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/users/"+uuid.NewString()+"/conflicts?from=2000&to=1000", nil)
+		rec := httptest.NewRecorder()
+
+		a.Router().ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("create user slots with zero timestamp", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupUsersAPI(t)
+
+		userID := uuid.New()
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+			WithArgs(userID).
+			WillReturnRows(sqlmock.NewRows(userColumns).AddRow(userID, "Alice", "alice@example.com", userCreatedAt, userCreatedAt))
+
+		body := `[{"start_time": 0, "end_time": 7200}]`
+		req := httptest.NewRequest(http.MethodPost, "/api/users/"+userID.String()+"/slots", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+
+		a.Router().ServeHTTP(rec, req)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+		var res api.Response
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+		assert.Contains(t, res.Response, "slot 0:")
+	})
+
+	t.Run("create user recurrences", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupUsersAPI(t)
+
+		userID := uuid.New()
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+			WithArgs(userID).
+			WillReturnRows(sqlmock.NewRows(userColumns).AddRow(userID, "Alice", "alice@example.com", userCreatedAt, userCreatedAt))
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO users_recurring_availability (user_id, weekday, start_minute, end_minute, valid_from, valid_until) VALUES ($1, $2, $3, $4, $5, $6)`)).
+			WithArgs(userID, 1, 540, 1020, time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC), nil).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		dbMock.ExpectCommit()
+
+		body := `[{"weekday": 1, "start_minute": 540, "end_minute": 1020, "valid_from": "2030-01-01"}]`
+		req := httptest.NewRequest(http.MethodPost, "/api/users/"+userID.String()+"/recurrences", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+
+		a.Router().ServeHTTP(rec, req)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		assert.Equal(t, http.StatusCreated, rec.Code)
+	})
+
+	t.Run("create user recurrences invalid rule", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupUsersAPI(t)
+
+		userID := uuid.New()
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+			WithArgs(userID).
+			WillReturnRows(sqlmock.NewRows(userColumns).AddRow(userID, "Alice", "alice@example.com", userCreatedAt, userCreatedAt))
+
+		body := `[{"weekday": 7, "start_minute": 540, "end_minute": 1020, "valid_from": "2030-01-01"}]`
+		req := httptest.NewRequest(http.MethodPost, "/api/users/"+userID.String()+"/recurrences", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+
+		a.Router().ServeHTTP(rec, req)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	})
+
+	t.Run("deactivate and activate user", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupUsersAPI(t)
+
+		userID := uuid.New()
+		for _, tc := range []struct {
+			path   string
+			active bool
+		}{
+			{path: "/deactivate", active: false},
+			{path: "/activate", active: true},
+		} {
+			dbMock.ExpectExec(regexp.QuoteMeta(`UPDATE users SET active = $1, updated_at = $2 WHERE id = $3`)).
+				WithArgs(tc.active, sqlmock.AnyArg(), userID).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			rec := httptest.NewRecorder()
+			a.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/users/"+userID.String()+tc.path, nil))
+			assert.Equal(t, http.StatusNoContent, rec.Code, tc.path)
+		}
+
+		// A deactivated user is still fetched by ID.
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+			WithArgs(userID).
+			WillReturnRows(sqlmock.NewRows(userColumns).AddRow(userID, "Alice", "alice@example.com", userCreatedAt, userCreatedAt))
+		rec := httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/users/"+userID.String(), nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("deactivate unknown user", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupUsersAPI(t)
+
+		userID := uuid.New()
+		dbMock.ExpectExec(regexp.QuoteMeta(`UPDATE users SET active = $1`)).
+			WithArgs(false, sqlmock.AnyArg(), userID).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		rec := httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/users/"+userID.String()+"/deactivate", nil))
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		require.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("get users including inactive", func(t *testing.T) {
+		t.Parallel()
+		a, dbMock := setupUsersAPI(t)
+
+		dbMock.ExpectQuery(`FROM users ORDER BY name, id LIMIT \$1 OFFSET \$2`).
+			WithArgs(20, 0).
+			WillReturnRows(sqlmock.NewRows(userColumns).AddRow(uuid.New(), "Former", "former@example.com", userCreatedAt, userCreatedAt))
+		dbMock.ExpectQuery(`^SELECT COUNT\(\*\) FROM users$`).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+		rec := httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/users?include_inactive=true", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		rec = httptest.NewRecorder()
+		a.Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/users?include_inactive=maybe", nil))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
 	t.Run("delete user slots", func(t *testing.T) {
 		t.Parallel()
 		a, dbMock := setupUsersAPI(t)
 
 		userID := uuid.New()
 
-		getUserQuery := regexp.QuoteMeta(`SELECT id, name, email FROM users WHERE id = $1`)
+		getUserQuery := regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)
 		dbMock.ExpectQuery(getUserQuery).
 			WithArgs(userID).
-			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email"}).
-				AddRow(userID, "Alice", "alice@example.com"))
+			WillReturnRows(sqlmock.NewRows(userColumns).
+				AddRow(userID, "Alice", "alice@example.com", userCreatedAt, userCreatedAt))
 
 		deleteQuery := regexp.QuoteMeta(`DELETE FROM users_availability WHERE user_id = $1`)
 		dbMock.ExpectExec(deleteQuery).
@@ -264,7 +1772,7 @@ func TestUsersAPI(t *testing.T) {
 		a, dbMock := setupUsersAPI(t)
 
 		userID := uuid.New()
-		getUserQuery := regexp.QuoteMeta(`SELECT id, name, email FROM users WHERE id = $1`)
+		getUserQuery := regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)
 		dbMock.ExpectQuery(getUserQuery).
 			WithArgs(userID).
 			WillReturnError(sql.ErrNoRows)
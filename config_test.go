@@ -0,0 +1,115 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"events-system/api"
+	"events-system/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfig(t *testing.T) {
+	t.Parallel()
+
+	env := func(vars map[string]string) func(string) string {
+		return func(name string) string { return vars[name] }
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		t.Parallel()
+		cfg, err := LoadConfig(env(nil))
+		require.NoError(t, err)
+		assert.Equal(t, Config{
+			Port:        "8080",
+			ReadRetries: database.DefaultRetry.Attempts - 1,
+			DB:          database.Config{DSN: defaultDSN},
+		}, cfg)
+	})
+
+	t.Run("parses every variable", func(t *testing.T) {
+		t.Parallel()
+		cfg, err := LoadConfig(env(map[string]string{
+			"PORT":                     "9090",
+			"RUN_MIGRATIONS":           "true",
+			"DB_READ_RETRIES":          "0",
+			"POSTGRES_DSN":             "postgres://db/events",
+			"DB_STATEMENT_TIMEOUT":     "30s",
+			"DB_MAX_OPEN_CONNS":        "20",
+			"DB_MAX_IDLE_CONNS":        "10",
+			"DB_CONN_MAX_LIFETIME":     "5m",
+			"CORS_ALLOWED_ORIGINS":     "https://a.example.com, ,https://b.example.com",
+			"WEBHOOK_URL":              "https://hooks.example.com/events",
+			"SMTP_ADDR":                "smtp.example.com:587",
+			"SMTP_FROM":                "events@example.com",
+			"RATE_LIMIT_RPS":           "2.5",
+			"MAX_EVENT_SLOTS":          "20",
+			"MAX_DURATION_HOURS":       "8",
+			"SLOT_GRANULARITY_MINUTES": "15",
+			"MAX_BODY_BYTES":           "65536",
+			"POSSIBLE_SLOT_LOCK":       "1",
+		}))
+		require.NoError(t, err)
+		assert.Equal(t, Config{
+			Port:          "9090",
+			RunMigrations: true,
+			ReadRetries:   0,
+			DB: database.Config{
+				DSN:              "postgres://db/events",
+				StatementTimeout: 30 * time.Second,
+				MaxOpenConns:     20,
+				MaxIdleConns:     10,
+				ConnMaxLifetime:  5 * time.Minute,
+			},
+			API: api.Config{
+				CORSAllowedOrigins: []string{"https://a.example.com", "https://b.example.com"},
+				WebhookURL:         "https://hooks.example.com/events",
+				SMTPAddr:           "smtp.example.com:587",
+				SMTPFrom:           "events@example.com",
+				RateLimitRPS:       2.5,
+				MaxEventSlots:      20,
+				MaxDurationHours:   8,
+				SlotGranularity:    15 * time.Minute,
+				MaxBodyBytes:       65536,
+				PossibleSlotLock:   true,
+			},
+		}, cfg)
+	})
+
+	t.Run("reports every invalid variable", func(t *testing.T) {
+		t.Parallel()
+		_, err := LoadConfig(env(map[string]string{
+			"PORT":                 "http",
+			"RUN_MIGRATIONS":       "yes please",
+			"DB_READ_RETRIES":      "-1",
+			"DB_STATEMENT_TIMEOUT": "30",
+			"DB_MAX_OPEN_CONNS":    "5",
+			"DB_MAX_IDLE_CONNS":    "10",
+			"SMTP_ADDR":            "smtp.example.com",
+			"RATE_LIMIT_BURST":     "10",
+			"MAX_EVENT_SLOTS":      "0",
+		}))
+		require.Error(t, err)
+		for _, msg := range []string{
+			"parse PORT: must be a port number",
+			"parse RUN_MIGRATIONS: must be true or false",
+			"parse DB_READ_RETRIES: must be a non-negative integer",
+			"parse DB_STATEMENT_TIMEOUT: must be a non-negative duration, e.g. 30s",
+			"parse DB_MAX_IDLE_CONNS: must not exceed DB_MAX_OPEN_CONNS",
+			"parse SMTP_FROM: is required with SMTP_ADDR",
+			"parse SMTP_ADDR: must be host:port",
+			"parse RATE_LIMIT_BURST: requires RATE_LIMIT_RPS",
+			"parse MAX_EVENT_SLOTS: must be a positive integer",
+		} {
+			assert.Contains(t, err.Error(), msg)
+		}
+	})
+
+	t.Run("rejects a relative webhook URL", func(t *testing.T) {
+		t.Parallel()
+		_, err := LoadConfig(env(map[string]string{"WEBHOOK_URL": "/hooks"}))
+		assert.EqualError(t, err, "parse WEBHOOK_URL: must be an absolute URL")
+	})
+}
@@ -0,0 +1,11 @@
+package database
+
+import "context"
+
+// WithCancelledContext returns a copy of ctx that is already cancelled, for checking that an
+// accessor gives up with context.Canceled instead of reaching the database.
+func WithCancelledContext(ctx context.Context) context.Context {
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+	return ctx
+}
@@ -3,18 +3,79 @@ package database
 import (
 	"database/sql"
 	"fmt"
+	"net/url"
+	"strings"
+	"time"
 
 	_ "github.com/lib/pq"
 )
 
-func Connect(dsn string) (*sql.DB, error) {
+// DefaultMaxIdleConns is how many idle connections the pool keeps when Config does not say.
+const DefaultMaxIdleConns = 5
+
+// Config is how to reach the database and how to size the connection pool.
+type Config struct {
+	DSN string
+	// StatementTimeout, when positive, is applied to every connection as the Postgres statement_timeout.
+	StatementTimeout time.Duration
+	// MaxOpenConns caps the open connections, 0 means no cap.
+	MaxOpenConns int
+	// MaxIdleConns is how many idle connections are kept, 0 means DefaultMaxIdleConns.
+	MaxIdleConns int
+	// ConnMaxLifetime closes connections once they are that old, 0 means they are reused forever.
+	ConnMaxLifetime time.Duration
+}
+
+// Connect opens the database pool. A positive StatementTimeout is applied to every
+// new connection as the Postgres statement_timeout, so the server itself aborts
+// queries that run longer than that.
+//
+// This complements context cancellation rather than replacing it: a cancelled
+// context makes lib/pq send a cancel request for the running query, but only the
+// server-side timeout protects against queries issued without a deadline.
+func Connect(cfg Config) (*sql.DB, error) {
+	dsn, err := WithStatementTimeout(cfg.DSN, cfg.StatementTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("statement timeout: %w", err)
+	}
+
 	db, err := sql.Open("postgres", dsn)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
 
 	// Set connection pool settings
-	db.SetMaxIdleConns(5)
+	maxIdleConns := cfg.MaxIdleConns
+	if maxIdleConns <= 0 {
+		maxIdleConns = DefaultMaxIdleConns
+	}
+	db.SetMaxIdleConns(maxIdleConns)
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
 
 	return db, nil
 }
+
+// WithStatementTimeout returns the DSN with the statement_timeout runtime parameter
+// set in milliseconds. lib/pq forwards unknown parameters to the server on startup,
+// so it applies to each new connection. Both URL and key/value DSNs are supported.
+// A non-positive timeout returns the DSN unchanged.
+func WithStatementTimeout(dsn string, timeout time.Duration) (string, error) {
+	if timeout <= 0 {
+		return dsn, nil
+	}
+	ms := fmt.Sprintf("%d", timeout.Milliseconds())
+
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		u, err := url.Parse(dsn)
+		if err != nil {
+			return "", fmt.Errorf("parse dsn: %w", err)
+		}
+		q := u.Query()
+		q.Set("statement_timeout", ms)
+		u.RawQuery = q.Encode()
+		return u.String(), nil
+	}
+
+	return strings.TrimSpace(dsn + " statement_timeout=" + ms), nil
+}
@@ -0,0 +1,45 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// WrapError prefixes a failed operation's error with the operation and the identifiers it was
+// called with, so that logs tell which call failed, e.g. "user.get_user 6ba7b810-...: scan: ...".
+// Deferred with a named error result it covers every return of a method:
+//
+//	defer database.WrapError(&err, "user.get_user", id)
+//
+// errors.Is and errors.As still see the original error. Identifiers must never be PII, such as
+// an email.
+func WrapError(err *error, op string, ids ...any) {
+	if *err == nil {
+		return
+	}
+	parts := make([]string, 0, len(ids)+1)
+	parts = append(parts, op)
+	for _, id := range ids {
+		parts = append(parts, fmt.Sprint(id))
+	}
+	*err = fmt.Errorf("%s: %w", strings.Join(parts, " "), *err)
+}
+
+// IsUniqueViolation reports whether err is Postgres rejecting a row that duplicates a unique key.
+func IsUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
+// ViolatedConstraint returns the name of the constraint a Postgres error was raised for, e.g.
+// "users_email_key", or "" if err carries none.
+func ViolatedConstraint(err error) string {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return ""
+	}
+	return pqErr.Constraint
+}
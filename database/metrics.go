@@ -0,0 +1,26 @@
+package database
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// QueryDuration records how long each accessor query takes, labelled by a short
+// "package.operation" name. It is not registered anywhere by default; the API adds
+// it to the registry it serves on /metrics.
+var QueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "db_query_duration_seconds",
+	Help:    "Duration of database queries issued by the accessors.",
+	Buckets: prometheus.DefBuckets,
+}, []string{"query"})
+
+// ObserveQuery starts timing the named query and returns the function that records it:
+//
+//	defer database.ObserveQuery("user.get_user")()
+func ObserveQuery(name string) func() {
+	start := time.Now()
+	return func() {
+		QueryDuration.WithLabelValues(name).Observe(time.Since(start).Seconds())
+	}
+}
@@ -1,3 +1,6 @@
+-- Initial schema, the one the old init.sql created. Databases built from init.sql already match it,
+-- so it is IF NOT EXISTS throughout; everything added since comes in the later migrations.
+
 -- Create users table
 CREATE TABLE IF NOT EXISTS users (
     id UUID PRIMARY KEY,
@@ -22,4 +25,4 @@ CREATE TABLE IF NOT EXISTS users_availability (
     start_time TIMESTAMPTZ NOT NULL,
     end_time TIMESTAMPTZ NOT NULL,
     PRIMARY KEY (user_id, start_time, end_time)
-);
\ No newline at end of file
+);
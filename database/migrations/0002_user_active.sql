@@ -0,0 +1,3 @@
+-- Users are deactivated rather than deleted so that their history stays auditable. Inactive users
+-- are left out of user listings and availability lookups, but can still be fetched by ID.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS active BOOLEAN NOT NULL DEFAULT TRUE;
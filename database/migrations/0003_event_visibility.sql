@@ -0,0 +1,4 @@
+-- Private events are left out of event listings unless they are scoped to their organizer. They can
+-- still be fetched by ID.
+ALTER TABLE events ADD COLUMN IF NOT EXISTS visibility VARCHAR(16) NOT NULL DEFAULT 'public'
+	CHECK (visibility IN ('public', 'private'));
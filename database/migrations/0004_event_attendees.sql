@@ -0,0 +1,7 @@
+-- Users' RSVPs to events.
+CREATE TABLE IF NOT EXISTS event_attendees (
+    event_id UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status VARCHAR(16) NOT NULL CHECK (status IN ('yes', 'no', 'maybe')),
+    PRIMARY KEY (event_id, user_id)
+);
@@ -0,0 +1,2 @@
+-- Set when the event is soft-deleted; deleted events are left out of every read but GET /events/{id}?include_deleted=true.
+ALTER TABLE events ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
@@ -0,0 +1,10 @@
+-- Weekly availability rules, counted alongside the one-off slots of users_availability.
+CREATE TABLE IF NOT EXISTS users_recurring_availability (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    weekday SMALLINT NOT NULL CHECK (weekday BETWEEN 0 AND 6), -- 0 is Sunday
+    start_minute SMALLINT NOT NULL CHECK (start_minute >= 0), -- Minutes since midnight UTC
+    end_minute SMALLINT NOT NULL CHECK (end_minute > start_minute AND end_minute <= 1440),
+    valid_from DATE NOT NULL,
+    valid_until DATE,
+    PRIMARY KEY (user_id, weekday, start_minute, end_minute, valid_from)
+);
@@ -0,0 +1,2 @@
+-- IANA name used to render slots, which are stored in UTC. Existing events were created in UTC.
+ALTER TABLE events ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT 'UTC';
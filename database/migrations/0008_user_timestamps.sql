@@ -0,0 +1,9 @@
+-- Users are read with both timestamps, so neither may be NULL. Rows from before created_at had a
+-- default are stamped now, and updated_at starts out as created_at. updated_at is bumped on every
+-- update.
+UPDATE users SET created_at = CURRENT_TIMESTAMP WHERE created_at IS NULL;
+ALTER TABLE users ALTER COLUMN created_at SET NOT NULL;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP;
+UPDATE users SET updated_at = created_at WHERE updated_at IS NULL;
+ALTER TABLE users ALTER COLUMN updated_at SET DEFAULT CURRENT_TIMESTAMP;
+ALTER TABLE users ALTER COLUMN updated_at SET NOT NULL;
@@ -0,0 +1,67 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Retry retries idempotent reads that fail with a transient error, waiting BaseDelay before the
+// second attempt and doubling the wait, up to MaxDelay, before each following one. Writes are not
+// retried, since a write whose outcome is unknown may have been applied already.
+type Retry struct {
+	Attempts  int // total attempts, values below 1 mean a single one
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+// DefaultRetry is the policy accessors start with. main sets Attempts from DB_READ_RETRIES.
+var DefaultRetry = Retry{Attempts: 3, BaseDelay: 50 * time.Millisecond, MaxDelay: time.Second}
+
+// Do calls fn until it succeeds, fails with an error that IsTransient rejects, or runs out of
+// attempts. It gives up early, returning fn's last error, when ctx is done or its deadline would
+// pass before the next attempt.
+func (r Retry) Do(ctx context.Context, fn func() error) error {
+	delay := r.BaseDelay
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= r.Attempts || !IsTransient(err) {
+			return err
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return err
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		delay *= 2
+		if r.MaxDelay > 0 && delay > r.MaxDelay {
+			delay = r.MaxDelay
+		}
+	}
+}
+
+// IsTransient reports whether err is worth retrying: a broken connection, a serialization failure
+// or deadlock, or the server shutting down or still starting up.
+func IsTransient(err error) bool {
+	if errors.Is(err, driver.ErrBadConn) {
+		return true
+	}
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+	switch pqErr.Code {
+	case "40001", "40P01", "57P01", "57P02", "57P03":
+		return true
+	}
+	return pqErr.Code.Class() == "08"
+}
@@ -0,0 +1,91 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrStatementsClosed is returned by Prepare once the cache is closed.
+var ErrStatementsClosed = errors.New("statements closed")
+
+// Statements caches prepared statements by query text, so that hot queries are parsed and planned
+// once per connection instead of on every call. Statements are prepared on first use and shared
+// by every accessor given the cache; it is safe for concurrent use.
+type Statements struct {
+	db *sql.DB
+
+	mu     sync.RWMutex
+	stmts  map[string]*sql.Stmt
+	closed bool
+}
+
+func NewStatements(db *sql.DB) *Statements {
+	return &Statements{db: db, stmts: map[string]*sql.Stmt{}}
+}
+
+// Prepare returns the cached statement for query, preparing it on first use.
+func (s *Statements) Prepare(ctx context.Context, query string) (*sql.Stmt, error) {
+	s.mu.RLock()
+	stmt, ok := s.stmts[query]
+	closed := s.closed
+	s.mu.RUnlock()
+	if ok {
+		return stmt, nil
+	}
+	if closed {
+		return nil, ErrStatementsClosed
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil, ErrStatementsClosed
+	}
+	// Another caller may have prepared it while the lock was released.
+	if stmt, ok := s.stmts[query]; ok {
+		return stmt, nil
+	}
+	stmt, err := s.db.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("prepare: %w", err)
+	}
+	s.stmts[query] = stmt
+	return stmt, nil
+}
+
+// QueryContext runs query through its cached statement.
+func (s *Statements) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	stmt, err := s.Prepare(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return stmt.QueryContext(ctx, args...)
+}
+
+// QueryRowContext runs query through its cached statement. Since a *sql.Row cannot carry an error
+// of its own making, a query that fails to prepare runs unprepared and reports through the row.
+func (s *Statements) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	stmt, err := s.Prepare(ctx, query)
+	if err != nil {
+		return s.db.QueryRowContext(ctx, query, args...)
+	}
+	return stmt.QueryRowContext(ctx, args...)
+}
+
+// Close closes every cached statement. Later calls to Prepare fail with ErrStatementsClosed.
+func (s *Statements) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	var errs []error
+	for query, stmt := range s.stmts {
+		if err := stmt.Close(); err != nil {
+			errs = append(errs, err)
+		}
+		delete(s.stmts, query)
+	}
+	return errors.Join(errs...)
+}
@@ -3,22 +3,122 @@ package event
 import (
 	"context"
 	"database/sql"
+	"events-system/database"
 	"events-system/user"
+	"fmt"
+
+	"github.com/google/uuid"
 )
 
 type UserAccessor interface {
 	GetUsers(ctx context.Context) ([]user.User, error)
-	GetUsersForSlot(ctx context.Context, slot user.Slot, durationHours int) ([]user.User, error)
+	GetUsersForSlot(ctx context.Context, slot user.Slot, durationHours int, userIDs ...uuid.UUID) ([]user.User, error)
+	GetUsersForSlotOverlap(ctx context.Context, slot user.Slot, durationHours int, userIDs ...uuid.UUID) ([]user.User, error)
+	GetUsersSlots(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID][]user.Slot, error)
+	GetUsersRecurrences(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID][]user.Recurrence, error)
 }
 
 type Accessor struct {
 	db           *sql.DB
 	userAccessor UserAccessor
+	overlap      bool
+	// trim makes possible-slot searches narrow each slot to the window most users share, see WithTrim.
+	trim bool
+	// lockPossibleSlot makes possible-slot searches take an advisory lock on each event they search.
+	lockPossibleSlot bool
+	// includePrivate and organizerID widen or narrow the event listings, see visibleOnly.
+	includePrivate bool
+	organizerID    uuid.UUID
+	// retry applies to the reads that are safe to repeat, currently GetEvent.
+	retry database.Retry
+	// stmts, when set, serves the hot queries from prepared statements, currently GetEvent.
+	stmts *database.Statements
 }
 
 func NewAccessor(db *sql.DB, userAccessor UserAccessor) *Accessor {
 	return &Accessor{
 		db:           db,
 		userAccessor: userAccessor,
+		retry:        database.DefaultRetry,
+	}
+}
+
+// WithRetry returns a copy of the accessor that retries reads with the given policy.
+func (a *Accessor) WithRetry(retry database.Retry) *Accessor {
+	c := *a
+	c.retry = retry
+	return &c
+}
+
+// WithStatements returns a copy of the accessor that runs its hot queries through stmts. A nil
+// stmts runs every query unprepared.
+func (a *Accessor) WithStatements(stmts *database.Statements) *Accessor {
+	c := *a
+	c.stmts = stmts
+	return &c
+}
+
+// queryRowContext runs query through the prepared statement cache when the accessor has one.
+func (a *Accessor) queryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	if a.stmts != nil {
+		return a.stmts.QueryRowContext(ctx, query, args...)
+	}
+	return a.db.QueryRowContext(ctx, query, args...)
+}
+
+// WithOverlap returns a copy of the accessor that treats users as available when their availability
+// overlaps a slot by the event duration, rather than fully containing it.
+func (a *Accessor) WithOverlap() *Accessor {
+	c := *a
+	c.overlap = true
+	return &c
+}
+
+// WithTrim returns a copy of the accessor that, like WithOverlap, counts users whose availability
+// overlaps a slot by the event duration, then trims the slot to the window of at least the duration
+// shared by the most of them. Only those users count as available, and the window is reported as
+// the possible slot's Window.
+func (a *Accessor) WithTrim() *Accessor {
+	c := *a
+	c.overlap = true
+	c.trim = true
+	return &c
+}
+
+// WithPossibleSlotLock returns a copy of the accessor whose possible-slot searches hold a Postgres
+// advisory lock on the event for their duration, so that concurrent searches for the same event run
+// one after the other. Each search then holds an extra connection, which is why it is opt-in.
+func (a *Accessor) WithPossibleSlotLock() *Accessor {
+	c := *a
+	c.lockPossibleSlot = true
+	return &c
+}
+
+// WithPrivate returns a copy of the accessor whose event listings and counts include private events.
+func (a *Accessor) WithPrivate() *Accessor {
+	c := *a
+	c.includePrivate = true
+	return &c
+}
+
+// WithOrganizer returns a copy of the accessor whose event listings and counts are limited to the
+// events organized by organizerID, private ones included.
+func (a *Accessor) WithOrganizer(organizerID uuid.UUID) *Accessor {
+	c := *a
+	c.organizerID = organizerID
+	return &c
+}
+
+// visibleOnly returns the condition, prefixed with " AND ", restricting a query on events to the
+// ones the accessor lists, with args extended by the parameters it refers to. Without WithPrivate or
+// WithOrganizer, that is the public events.
+func (a *Accessor) visibleOnly(args []any) (string, []any) {
+	if a.organizerID != uuid.Nil {
+		args = append(args, a.organizerID)
+		return fmt.Sprintf(" AND events.user_id = $%d", len(args)), args
+	}
+	if a.includePrivate {
+		return "", args
 	}
+	return " AND events.visibility = 'public'", args
 }
@@ -4,28 +4,81 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"events-system/database"
 	"events-system/user"
 	"fmt"
+	"log"
 	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 )
 
-func (a *Accessor) GetEvents(ctx context.Context) ([]Event, error) {
-	query := `SELECT id, title, duration_hours, user_id, slots, created_at FROM events`
-	rows, err := a.db.QueryContext(ctx, query)
+// GetEventsWithOrganizers returns a page of the listed events, newest first, with each event's
+// organizer loaded in the same query. Listed events are the public ones, unless the accessor is made
+// WithPrivate or WithOrganizer. Events created at the same instant are ordered by ID, so that the
+// order is the same on every call, and the same as SearchEvents.
+func (a *Accessor) GetEventsWithOrganizers(ctx context.Context, limit, offset int) (_ []EventWithOrganizer, err error) {
+	defer database.ObserveQuery("event.get_events_with_organizers")()
+	defer database.WrapError(&err, "event.get_events_with_organizers")
+	visible, args := a.visibleOnly([]any{limit, offset})
+	query := `SELECT events.id, events.title, events.duration_hours, events.user_id, events.slots, events.timezone, events.visibility, events.created_at,
+		users.id, users.name, users.email
+	FROM events
+	JOIN users ON users.id = events.user_id
+	WHERE events.deleted_at IS NULL` + visible + `
+	ORDER BY events.created_at DESC, events.id
+	LIMIT $1 OFFSET $2`
+	rows, err := a.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("query: %w", err)
 	}
 	defer rows.Close()
 
-	events := []Event{}
+	return scanEventsWithOrganizers(rows)
+}
+
+// GetEventsWithOrganizersAfter is GetEventsWithOrganizers paged by key rather than offset: it returns
+// up to limit events that come after the cursor, or the first ones when after is nil. Unlike an
+// offset, the cursor does not drift when events are inserted or deleted between pages.
+func (a *Accessor) GetEventsWithOrganizersAfter(ctx context.Context, after *Cursor, limit int) (_ []EventWithOrganizer, err error) {
+	defer database.ObserveQuery("event.get_events_with_organizers_after")()
+	defer database.WrapError(&err, "event.get_events_with_organizers_after")
+	query := `SELECT events.id, events.title, events.duration_hours, events.user_id, events.slots, events.timezone, events.visibility, events.created_at,
+		users.id, users.name, users.email
+	FROM events
+	JOIN users ON users.id = events.user_id
+	WHERE events.deleted_at IS NULL`
+	args := []any{limit}
+	if after != nil {
+		// The listing runs newest first but breaks ties by ascending ID, which a row comparison cannot express.
+		query += ` AND (events.created_at < $2 OR (events.created_at = $2 AND events.id > $3))`
+		args = append(args, after.CreatedAt, after.ID)
+	}
+	visible, args := a.visibleOnly(args)
+	query += visible + `
+	ORDER BY events.created_at DESC, events.id
+	LIMIT $1`
+	rows, err := a.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+	defer rows.Close()
+
+	return scanEventsWithOrganizers(rows)
+}
+
+func scanEventsWithOrganizers(rows *sql.Rows) ([]EventWithOrganizer, error) {
+	events := []EventWithOrganizer{}
 	for rows.Next() {
-		var event Event
-		if err := rows.Scan(&event.ID, &event.Title, &event.DurationHours, &event.UserID, &event.Slots, &event.CreatedAt); err != nil {
+		var event EventWithOrganizer
+		var slotsCol SlotsColumn
+		if err := rows.Scan(&event.ID, &event.Title, &event.DurationHours, &event.UserID, &slotsCol, &event.Timezone, &event.Visibility, &event.CreatedAt,
+			&event.Organizer.ID, &event.Organizer.Name, &event.Organizer.Email); err != nil {
 			return nil, fmt.Errorf("scan: %w", err)
 		}
+		event.Slots = []Slot(slotsCol)
 		events = append(events, event)
 	}
 	if err := rows.Err(); err != nil {
@@ -35,36 +88,147 @@ func (a *Accessor) GetEvents(ctx context.Context) ([]Event, error) {
 	return events, nil
 }
 
-func (a *Accessor) CreateEvent(ctx context.Context, event Event, now time.Time) (*Event, error) {
+func (a *Accessor) CreateEvent(ctx context.Context, event Event, now time.Time) (_ *Event, err error) {
+	defer database.ObserveQuery("event.create_event")()
+	defer database.WrapError(&err, "event.create_event", event.UserID)
+	if err := event.Validate(); err != nil {
+		return nil, fmt.Errorf("validate: %w", err)
+	}
+
+	// Honour a client-supplied ID so creates can be made conditional on it. Taking an existing
+	// event's ID fails with ErrAlreadyExists.
+	id := event.ID
+	if id == uuid.Nil {
+		id = uuid.New()
+	}
+
+	query := `INSERT INTO events (id, title, duration_hours, user_id, slots, timezone, created_at, visibility) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	if _, err := a.db.ExecContext(ctx, query, id, event.Title, event.DurationHours, event.UserID, SlotsColumn(event.Slots), event.Timezone, now, event.Visibility.orDefault()); err != nil {
+		if database.IsUniqueViolation(err) {
+			return nil, ErrAlreadyExists
+		}
+		return nil, fmt.Errorf("exec context: %w", err)
+	}
+
+	return &Event{
+		ID:            id,
+		Title:         event.Title,
+		DurationHours: event.DurationHours,
+		UserID:        event.UserID,
+		Slots:         event.Slots,
+		Timezone:      event.Timezone,
+		Visibility:    event.Visibility.orDefault(),
+		CreatedAt:     now,
+	}, nil
+}
+
+// CreateEventIfOrganizerAvailable is CreateEvent that first checks that the organizer is available for at least one of the event's slots under the rule of
+// GetUsersForSlot: an availability window, one-off or recurring, must contain the slot and be at least
+// duration_hours long. It returns ErrOrganizerUnavailable otherwise. Recurring rules are read first and
+// are not locked. When none of them matches, the matching one-off availability rows stay locked until the
+// event is stored, so they cannot be deleted in between.
+func (a *Accessor) CreateEventIfOrganizerAvailable(ctx context.Context, event Event, now time.Time) (_ *Event, err error) {
+	defer database.ObserveQuery("event.create_event_if_organizer_available")()
+	defer database.WrapError(&err, "event.create_event_if_organizer_available", event.UserID)
 	if err := event.Validate(); err != nil {
 		return nil, fmt.Errorf("validate: %w", err)
 	}
 
-	id := uuid.New()
+	id := event.ID
+	if id == uuid.Nil {
+		id = uuid.New()
+	}
+
+	// Read outside the transaction, which must not wait on a second connection from the pool.
+	recurring, err := a.organizerRecurringAvailable(ctx, event)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := a.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() {
+		err := tx.Rollback()
+		if err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Printf("rollback tx: %v", err)
+		}
+	}()
+
+	if !recurring {
+		availableQuery := `SELECT 1 FROM users_availability
+		CROSS JOIN LATERAL jsonb_array_elements($2::jsonb) AS slot(value)
+		WHERE users_availability.user_id = $1
+			AND users_availability.start_time <= (slot.value->>'start_time')::timestamptz
+			AND users_availability.end_time >= (slot.value->>'end_time')::timestamptz
+			AND users_availability.end_time - users_availability.start_time >= make_interval(hours => $3)
+		LIMIT 1
+		FOR SHARE OF users_availability`
+		var available int
+		if err := tx.QueryRowContext(ctx, availableQuery, event.UserID, SlotsColumn(event.Slots), event.DurationHours).Scan(&available); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, ErrOrganizerUnavailable
+			}
+			return nil, fmt.Errorf("scan: %w", err)
+		}
+	}
 
-	query := `INSERT INTO events (id, title, duration_hours, user_id, slots, created_at) VALUES ($1, $2, $3, $4, $5, $6)`
-	if _, err := a.db.ExecContext(ctx, query, id, event.Title, event.DurationHours, event.UserID, SlotsColumn(event.Slots), now); err != nil {
+	query := `INSERT INTO events (id, title, duration_hours, user_id, slots, timezone, created_at, visibility) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	if _, err := tx.ExecContext(ctx, query, id, event.Title, event.DurationHours, event.UserID, SlotsColumn(event.Slots), event.Timezone, now, event.Visibility.orDefault()); err != nil {
+		if database.IsUniqueViolation(err) {
+			return nil, ErrAlreadyExists
+		}
 		return nil, fmt.Errorf("exec context: %w", err)
 	}
 
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+
 	return &Event{
 		ID:            id,
 		Title:         event.Title,
 		DurationHours: event.DurationHours,
 		UserID:        event.UserID,
 		Slots:         event.Slots,
+		Timezone:      event.Timezone,
+		Visibility:    event.Visibility.orDefault(),
 		CreatedAt:     now,
 	}, nil
 }
 
-func (a *Accessor) UpdateEvent(ctx context.Context, event Event, now time.Time) (*Event, error) {
+// organizerRecurringAvailable reports whether a weekly rule of the organizer makes them available for
+// one of the event's slots, the rule expanded over the slot must contain it and be at least
+// duration_hours long.
+func (a *Accessor) organizerRecurringAvailable(ctx context.Context, event Event) (bool, error) {
+	recurrences, err := a.userAccessor.GetUsersRecurrences(ctx, []uuid.UUID{event.UserID})
+	if err != nil {
+		return false, fmt.Errorf("get users recurrences: %w", err)
+	}
+	duration := time.Duration(event.DurationHours) * time.Hour
+	for _, slot := range event.Slots {
+		for _, r := range recurrences[event.UserID] {
+			for _, w := range r.Expand(slot.StartTime, slot.EndTime) {
+				if !w.StartTime.After(slot.StartTime) && !w.EndTime.Before(slot.EndTime) && w.EndTime.Sub(w.StartTime) >= duration {
+					return true, nil
+				}
+			}
+		}
+	}
+	return false, nil
+}
+
+func (a *Accessor) UpdateEvent(ctx context.Context, event Event, now time.Time) (_ *Event, err error) {
+	defer database.ObserveQuery("event.update_event")()
+	defer database.WrapError(&err, "event.update_event", event.ID)
 	if err := event.Validate(); err != nil {
 		return nil, fmt.Errorf("validate: %w", err)
 	}
 
-	// Only update title, duration_hours, and slots. user_id and created_at should not be changed.
-	query := `UPDATE events SET title = $1, duration_hours = $2, slots = $3 WHERE id = $4`
-	if _, err := a.db.ExecContext(ctx, query, event.Title, event.DurationHours, SlotsColumn(event.Slots), event.ID); err != nil {
+	// Only update title, duration_hours, slots and timezone. user_id, visibility and created_at should not be changed.
+	query := `UPDATE events SET title = $1, duration_hours = $2, slots = $3, timezone = $4 WHERE id = $5`
+	if _, err := a.db.ExecContext(ctx, query, event.Title, event.DurationHours, SlotsColumn(event.Slots), event.Timezone, event.ID); err != nil {
 		return nil, fmt.Errorf("exec context: %w", err)
 	}
 
@@ -73,72 +237,378 @@ func (a *Accessor) UpdateEvent(ctx context.Context, event Event, now time.Time)
 	if err != nil {
 		return nil, fmt.Errorf("get event: %w", err)
 	}
-	if updatedEvent == nil {
-		return nil, fmt.Errorf("event not found after update")
-	}
 
 	return updatedEvent, nil
 }
 
-func (a *Accessor) GetEvent(ctx context.Context, id uuid.UUID) (*Event, error) {
+func (a *Accessor) GetEvent(ctx context.Context, id uuid.UUID) (_ *Event, err error) {
+	defer database.ObserveQuery("event.get_event")()
+	defer database.WrapError(&err, "event.get_event", id)
+	var event Event
+	var slotsCol SlotsColumn
+
+	query := `SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1 AND deleted_at IS NULL`
+	err = a.retry.Do(ctx, func() error {
+		row := a.queryRowContext(ctx, query, id)
+		return row.Scan(&event.ID, &event.Title, &event.DurationHours, &event.UserID, &slotsCol, &event.Timezone, &event.CreatedAt, &event.Visibility)
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("scan: %w", err)
+	}
+	event.Slots = []Slot(slotsCol)
+
+	return &event, nil
+}
+
+// GetEventIncludingDeleted is GetEvent that also returns soft-deleted events, with DeletedAt set.
+func (a *Accessor) GetEventIncludingDeleted(ctx context.Context, id uuid.UUID) (_ *Event, err error) {
+	defer database.ObserveQuery("event.get_event_including_deleted")()
+	defer database.WrapError(&err, "event.get_event_including_deleted", id)
 	var event Event
 	var slotsCol SlotsColumn
+	var deletedAt sql.NullTime
 
-	query := `SELECT id, title, duration_hours, user_id, slots, created_at FROM events WHERE id = $1`
+	query := `SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility, deleted_at FROM events WHERE id = $1`
 	row := a.db.QueryRowContext(ctx, query, id)
-	if err := row.Scan(&event.ID, &event.Title, &event.DurationHours, &event.UserID, &slotsCol, &event.CreatedAt); err != nil {
+	if err := row.Scan(&event.ID, &event.Title, &event.DurationHours, &event.UserID, &slotsCol, &event.Timezone, &event.CreatedAt, &event.Visibility, &deletedAt); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, nil
+			return nil, ErrNotFound
 		}
 		return nil, fmt.Errorf("scan: %w", err)
 	}
 	event.Slots = []Slot(slotsCol)
+	if deletedAt.Valid {
+		event.DeletedAt = &deletedAt.Time
+	}
 
 	return &event, nil
 }
 
-func (a *Accessor) DeleteEvent(ctx context.Context, id uuid.UUID) error {
-	query := `DELETE FROM events WHERE id = $1`
-	if _, err := a.db.ExecContext(ctx, query, id); err != nil {
+// DeleteEvent soft-deletes the event so it is hidden from reads but kept for history.
+func (a *Accessor) DeleteEvent(ctx context.Context, id uuid.UUID, now time.Time) (err error) {
+	defer database.ObserveQuery("event.delete_event")()
+	defer database.WrapError(&err, "event.delete_event", id)
+	query := `UPDATE events SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL`
+	if _, err := a.db.ExecContext(ctx, query, now, id); err != nil {
 		return fmt.Errorf("exec context: %w", err)
 	}
 	return nil
 }
 
+// ReassignEvents moves every event organized by fromID to toID and returns the number of events moved.
+func (a *Accessor) ReassignEvents(ctx context.Context, fromID, toID uuid.UUID) (_ int64, err error) {
+	defer database.ObserveQuery("event.reassign_events")()
+	defer database.WrapError(&err, "event.reassign_events", fromID, toID)
+	tx, err := a.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() {
+		err := tx.Rollback()
+		if err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Printf("rollback tx: %v", err)
+		}
+	}()
+
+	query := `UPDATE events SET user_id = $1 WHERE user_id = $2`
+	res, err := tx.ExecContext(ctx, query, toID, fromID)
+	if err != nil {
+		return 0, fmt.Errorf("exec context: %w", err)
+	}
+	moved, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("rows affected: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit: %w", err)
+	}
+	return moved, nil
+}
+
+// SetEventSlots replaces the candidate slots of the event, leaving its other fields alone, and returns
+// the updated event, or ErrNotFound if the event does not exist. Nil slots clear them.
+func (a *Accessor) SetEventSlots(ctx context.Context, id uuid.UUID, slots []Slot) (_ *Event, err error) {
+	defer database.ObserveQuery("event.set_event_slots")()
+	defer database.WrapError(&err, "event.set_event_slots", id)
+	for _, slot := range slots {
+		if err := slot.Validate(); err != nil {
+			return nil, fmt.Errorf("validate: invalid slot - %v: %w", slot, err)
+		}
+	}
+
+	query := `UPDATE events SET slots = $1 WHERE id = $2 AND deleted_at IS NULL`
+	res, err := a.db.ExecContext(ctx, query, SlotsColumn(slots), id)
+	if err != nil {
+		return nil, fmt.Errorf("exec context: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("rows affected: %w", err)
+	}
+	if n == 0 {
+		return nil, ErrNotFound
+	}
+
+	return a.GetEvent(ctx, id)
+}
+
+// TransferEvent makes newOrganizerID the organizer of the event and returns the updated event,
+// or ErrNotFound if the event does not exist. The caller checks that the new organizer exists.
+func (a *Accessor) TransferEvent(ctx context.Context, id, newOrganizerID uuid.UUID) (_ *Event, err error) {
+	defer database.ObserveQuery("event.transfer_event")()
+	defer database.WrapError(&err, "event.transfer_event", id, newOrganizerID)
+	query := `UPDATE events SET user_id = $1 WHERE id = $2 AND deleted_at IS NULL`
+	res, err := a.db.ExecContext(ctx, query, newOrganizerID, id)
+	if err != nil {
+		return nil, fmt.Errorf("exec context: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("rows affected: %w", err)
+	}
+	if n == 0 {
+		return nil, ErrNotFound
+	}
+
+	return a.GetEvent(ctx, id)
+}
+
 // GetPossibleEventSlot returns the possible event slot for the event with maximum user attendance.
 // If there is no such time slot found, then it returns the time slots that work for the most number of people (also provides a list for whom it does not work).
+// It returns ErrNotFound if the event does not exist, and nil if no slot suits anyone.
 func (a *Accessor) GetPossibleEventSlot(ctx context.Context, id uuid.UUID) (*PossibleEventSlot, error) {
+	return a.GetPossibleEventSlotForUsers(ctx, id, nil)
+}
+
+// GetPossibleEventSlotForUsers is GetPossibleEventSlot restricted to the given candidate users (e.g. the invitees).
+// Only candidates are counted as available or not working, and the search stops early once a slot suits all of them.
+// A nil candidates list means every user is a candidate.
+func (a *Accessor) GetPossibleEventSlotForUsers(ctx context.Context, id uuid.UUID, candidates []user.User) (*PossibleEventSlot, error) {
+	_, possible, err := a.GetEventWithPossibleSlot(ctx, id, candidates)
+	return possible, err
+}
+
+// GetEventWithPossibleSlot is GetPossibleEventSlotForUsers also returning the event the search ran
+// over, for callers that need both without reading the event twice.
+func (a *Accessor) GetEventWithPossibleSlot(ctx context.Context, id uuid.UUID, candidates []user.User) (_ *Event, _ *PossibleEventSlot, err error) {
+	defer database.ObserveQuery("event.get_possible_event_slot_for_users")()
+	defer database.WrapError(&err, "event.get_possible_event_slot_for_users", id)
+	unlock, err := a.lockEvents(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer unlock()
 	event, err := a.GetEvent(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("get event: %w", err)
+		return nil, nil, fmt.Errorf("get event: %w", err)
+	}
+	possible, err := a.FindPossibleSlot(ctx, event, candidates)
+	if err != nil {
+		return nil, nil, err
 	}
-	if event == nil || len(event.Slots) == 0 {
+	return event, possible, nil
+}
+
+// FindPossibleSlot runs the possible-slot search of GetPossibleEventSlotForUsers over the slots and
+// duration of event, which need not be stored. Slots clashing with other events of the organizer
+// are tried last; an event without an organizer has no such clashes. It returns nil if no slot
+// suits anyone.
+func (a *Accessor) FindPossibleSlot(ctx context.Context, event *Event, candidates []user.User) (*PossibleEventSlot, error) {
+	if len(event.Slots) == 0 {
 		return nil, nil
 	}
+	search, err := a.newPossibleSlotSearch(ctx, candidates)
+	if err != nil {
+		return nil, err
+	}
+	return a.findPossibleSlot(ctx, event, search)
+}
+
+// GetPossibleEventSlots is GetPossibleEventSlot for several events at once, in the order of ids,
+// with nil for an event no slot suits anyone for. Users are fetched once for all the events, and
+// a slot proposed by several of them is looked up once per duration. It returns ErrNotFound if
+// any of the events does not exist.
+func (a *Accessor) GetPossibleEventSlots(ctx context.Context, ids []uuid.UUID) (_ []*PossibleEventSlot, err error) {
+	defer database.ObserveQuery("event.get_possible_event_slots")()
+	defer database.WrapError(&err, "event.get_possible_event_slots")
+	unlock, err := a.lockEvents(ctx, ids...)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	var search *possibleSlotSearch
+	possible := make([]*PossibleEventSlot, len(ids))
+	for i, id := range ids {
+		event, err := a.GetEvent(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("get event: %w", err)
+		}
+		if len(event.Slots) == 0 {
+			continue
+		}
+		if search == nil {
+			if search, err = a.newPossibleSlotSearch(ctx, nil); err != nil {
+				return nil, err
+			}
+		}
+		if possible[i], err = a.findPossibleSlot(ctx, event, search); err != nil {
+			return nil, err
+		}
+	}
+	return possible, nil
+}
+
+// lockEvents takes the transaction-scoped advisory lock of each event when the accessor was built
+// WithPossibleSlotLock, and returns the function that releases them by ending the transaction. The
+// locks are taken in ID order, so that two searches over several events cannot deadlock.
+func (a *Accessor) lockEvents(ctx context.Context, ids ...uuid.UUID) (func(), error) {
+	if !a.lockPossibleSlot || len(ids) == 0 {
+		return func() {}, nil
+	}
 
-	allUsers, err := a.userAccessor.GetUsers(ctx)
+	tx, err := a.db.BeginTx(ctx, nil)
 	if err != nil {
-		return nil, fmt.Errorf("get users: %w", err)
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	// Nothing is written under the locks, so rolling back is how they are released.
+	unlock := func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Printf("rollback tx: %v", err)
+		}
+	}
+
+	ids = slices.Clone(ids)
+	slices.SortFunc(ids, func(a, b uuid.UUID) int { return strings.Compare(a.String(), b.String()) })
+	ids = slices.Compact(ids)
+	query := `SELECT pg_advisory_xact_lock(hashtextextended('possible_slot:' || $1, 0))`
+	for _, id := range ids {
+		if _, err := tx.ExecContext(ctx, query, id.String()); err != nil {
+			unlock()
+			return nil, fmt.Errorf("advisory lock: %w", err)
+		}
+	}
+	return unlock, nil
+}
+
+// possibleSlotSearch is what possible-slot searches over several events share: the candidate
+// users, and the candidates already found available for a slot.
+type possibleSlotSearch struct {
+	candidates   []user.User
+	candidateIDs map[uuid.UUID]bool
+	// filterIDs, when set, restricts the availability query itself to those users.
+	filterIDs []uuid.UUID
+	available map[availabilityKey]slotCandidates
+}
+
+// slotCandidates are the candidates available for a slot, and with WithTrim the window they share.
+type slotCandidates struct {
+	users  []user.User
+	window *Slot
+}
+
+type availabilityKey struct {
+	start, end    int64
+	durationHours int
+}
+
+// newPossibleSlotSearch starts a search among candidates, every user when nil.
+func (a *Accessor) newPossibleSlotSearch(ctx context.Context, candidates []user.User) (*possibleSlotSearch, error) {
+	// An explicit invitee set is pushed down to the availability query.
+	filter := candidates != nil
+	if candidates == nil {
+		var err error
+		candidates, err = a.userAccessor.GetUsers(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("get users: %w", err)
+		}
+	}
+	search := &possibleSlotSearch{
+		candidates:   candidates,
+		candidateIDs: make(map[uuid.UUID]bool, len(candidates)),
+		available:    map[availabilityKey]slotCandidates{},
+	}
+	for _, u := range candidates {
+		search.candidateIDs[u.ID] = true
+		if filter {
+			search.filterIDs = append(search.filterIDs, u.ID)
+		}
+	}
+	return search, nil
+}
+
+func (a *Accessor) findPossibleSlot(ctx context.Context, event *Event, search *possibleSlotSearch) (*PossibleEventSlot, error) {
+	candidates := search.candidates
+	if len(event.Slots) == 0 || len(candidates) == 0 {
+		return nil, nil
+	}
+
+	// The organizer must attend, so slots clashing with their other events are
+	// only considered once no conflict-free slot has anyone available.
+	// A slot listed twice is looked up once.
+	freeSlots := []Slot{}
+	conflictingSlots := []Slot{}
+	for _, slot := range distinctSlots(event.Slots) {
+		if event.UserID == uuid.Nil {
+			freeSlots = append(freeSlots, slot)
+			continue
+		}
+		conflicts, err := a.GetUserEventConflicts(ctx, event.UserID, slot)
+		if err != nil {
+			return nil, fmt.Errorf("get user event conflicts: %w", err)
+		}
+		conflicting := false
+		for _, c := range conflicts {
+			if c.ID != event.ID {
+				conflicting = true
+				break
+			}
+		}
+		if conflicting {
+			conflictingSlots = append(conflictingSlots, slot)
+		} else {
+			freeSlots = append(freeSlots, slot)
+		}
 	}
 
 	possibleSlot := PossibleEventSlot{}
 
-	for _, slot := range event.Slots {
-		users, err := a.userAccessor.GetUsersForSlot(ctx, user.Slot{StartTime: slot.StartTime, EndTime: slot.EndTime}, event.DurationHours)
+	for i, slot := range append(freeSlots, conflictingSlots...) {
+		if i == len(freeSlots) && len(possibleSlot.Users) > 0 {
+			break
+		}
+		// The user accessor is an interface and may not check ctx itself.
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		available, err := a.availableCandidates(ctx, slot, event.DurationHours, search)
 		if err != nil {
-			return nil, fmt.Errorf("get users for slot: %w", err)
+			return nil, err
 		}
+		users := available.users
+		availableIDs := make(map[uuid.UUID]bool, len(users))
+		for _, u := range users {
+			availableIDs[u.ID] = true
+		}
+
 		if len(users) >= len(possibleSlot.Users) {
 			possibleSlot.Users = users
 			possibleSlot.Slot = slot
+			possibleSlot.Window = available.window
 			possibleSlot.NotWorkingUsers = []user.User{}
-			for _, user := range allUsers {
-				if !slices.Contains(users, user) {
-					possibleSlot.NotWorkingUsers = append(possibleSlot.NotWorkingUsers, user)
+			for _, u := range candidates {
+				if !availableIDs[u.ID] {
+					possibleSlot.NotWorkingUsers = append(possibleSlot.NotWorkingUsers, u)
 				}
 			}
 
-			if len(possibleSlot.Users) == len(allUsers) {
+			// Every candidate can attend, no other slot can do better.
+			if len(possibleSlot.Users) == len(candidates) {
 				return &possibleSlot, nil
 			}
 		}
@@ -150,3 +620,459 @@ func (a *Accessor) GetPossibleEventSlot(ctx context.Context, id uuid.UUID) (*Pos
 
 	return &possibleSlot, nil
 }
+
+// availableCandidates returns the candidates of the search available for the slot, in
+// GetUsersForSlot order, looking them up only the first time. With WithTrim they are narrowed down
+// to those sharing the trimmed window.
+func (a *Accessor) availableCandidates(ctx context.Context, slot Slot, durationHours int, search *possibleSlotSearch) (slotCandidates, error) {
+	key := availabilityKey{start: slot.StartTime.Unix(), end: slot.EndTime.Unix(), durationHours: durationHours}
+	if available, ok := search.available[key]; ok {
+		return available, nil
+	}
+
+	getUsersForSlot := a.userAccessor.GetUsersForSlot
+	if a.overlap {
+		getUsersForSlot = a.userAccessor.GetUsersForSlotOverlap
+	}
+	slotUsers, err := getUsersForSlot(ctx, user.Slot{StartTime: slot.StartTime, EndTime: slot.EndTime}, durationHours, search.filterIDs...)
+	if err != nil {
+		return slotCandidates{}, fmt.Errorf("get users for slot: %w", err)
+	}
+
+	available := slotCandidates{users: []user.User{}}
+	for _, u := range slotUsers {
+		if search.candidateIDs[u.ID] {
+			available.users = append(available.users, u)
+		}
+	}
+	if a.trim && len(available.users) > 0 {
+		ids := make([]uuid.UUID, len(available.users))
+		for i, u := range available.users {
+			ids[i] = u.ID
+		}
+		slotsByUser, err := a.userAccessor.GetUsersSlots(ctx, ids)
+		if err != nil {
+			return slotCandidates{}, fmt.Errorf("get users slots: %w", err)
+		}
+		recurrencesByUser, err := a.userAccessor.GetUsersRecurrences(ctx, ids)
+		if err != nil {
+			return slotCandidates{}, fmt.Errorf("get users recurrences: %w", err)
+		}
+		// Recurring rules count as the one-off slots they expand to within the slot.
+		for userID, recurrences := range recurrencesByUser {
+			slots := slices.Clone(slotsByUser[userID])
+			for _, r := range recurrences {
+				slots = append(slots, r.Expand(slot.StartTime, slot.EndTime)...)
+			}
+			slotsByUser[userID] = slots
+		}
+		available.window, available.users = trimSlot(slot, time.Duration(durationHours)*time.Hour, available.users, slotsByUser)
+	}
+	search.available[key] = available
+	return available, nil
+}
+
+// trimSlot finds the window of at least duration within slot that the most of users are free for,
+// the earliest one on a tie, given their availability. It returns the window, stretched for as long
+// as all of them stay free, and those users in their original order. slotsByUser holds both one-off
+// slots and recurring rules expanded over the slot.
+func trimSlot(slot Slot, duration time.Duration, users []user.User, slotsByUser map[uuid.UUID][]user.Slot) (*Slot, []user.User) {
+	free := make([][]user.Slot, len(users))
+	for i, u := range users {
+		free[i] = user.CommonAvailability([][]user.Slot{slotsByUser[u.ID]}, slot.StartTime, slot.EndTime, duration)
+	}
+
+	// The best window starts where one of the users becomes free.
+	var best *Slot
+	bestUsers := []user.User{}
+	for _, windows := range free {
+		for _, w := range windows {
+			start, end := w.StartTime, w.StartTime.Add(duration)
+			window := Slot{StartTime: slot.StartTime, EndTime: slot.EndTime}
+			var sharing []user.User
+			for i, u := range users {
+				for _, f := range free[i] {
+					if f.StartTime.After(start) || f.EndTime.Before(end) {
+						continue
+					}
+					if f.StartTime.After(window.StartTime) {
+						window.StartTime = f.StartTime
+					}
+					if f.EndTime.Before(window.EndTime) {
+						window.EndTime = f.EndTime
+					}
+					sharing = append(sharing, u)
+					break
+				}
+			}
+			if len(sharing) > len(bestUsers) || (len(sharing) == len(bestUsers) && best != nil && window.StartTime.Before(best.StartTime)) {
+				best, bestUsers = &window, sharing
+			}
+		}
+	}
+	return best, bestUsers
+}
+
+// GetSlotAvailability counts, for every slot of the event, how many users can and cannot attend.
+// It returns ErrNotFound if the event does not exist.
+func (a *Accessor) GetSlotAvailability(ctx context.Context, id uuid.UUID) (_ []SlotAvailability, err error) {
+	defer database.ObserveQuery("event.get_slot_availability")()
+	defer database.WrapError(&err, "event.get_slot_availability", id)
+	event, err := a.GetEvent(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get event: %w", err)
+	}
+
+	availability := make([]SlotAvailability, 0, len(event.Slots))
+	if len(event.Slots) == 0 {
+		return availability, nil
+	}
+
+	search, err := a.newPossibleSlotSearch(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, slot := range event.Slots {
+		available, err := a.availableCandidates(ctx, slot, event.DurationHours, search)
+		if err != nil {
+			return nil, err
+		}
+		availability = append(availability, SlotAvailability{
+			Slot:            slot,
+			AvailableCount:  len(available.users),
+			NotWorkingCount: len(search.candidates) - len(available.users),
+		})
+	}
+	return availability, nil
+}
+
+// GetUserEventConflicts returns the events organized by the user with a slot overlapping the given one.
+// Events do not record which of their slots was picked, so every proposed slot counts as a commitment.
+func (a *Accessor) GetUserEventConflicts(ctx context.Context, userID uuid.UUID, slot Slot) (_ []Event, err error) {
+	defer database.ObserveQuery("event.get_user_event_conflicts")()
+	defer database.WrapError(&err, "event.get_user_event_conflicts", userID)
+	query := `SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE user_id = $1 AND deleted_at IS NULL
+	AND EXISTS (
+		SELECT 1 FROM jsonb_array_elements(events.slots) AS slot(value)
+		WHERE (slot.value->>'start_time')::timestamptz < $3
+			AND (slot.value->>'end_time')::timestamptz > $2
+	)
+	ORDER BY created_at`
+	rows, err := a.db.QueryContext(ctx, query, userID, slot.StartTime, slot.EndTime)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+	defer rows.Close()
+
+	events := []Event{}
+	for rows.Next() {
+		var event Event
+		var slotsCol SlotsColumn
+		if err := rows.Scan(&event.ID, &event.Title, &event.DurationHours, &event.UserID, &slotsCol, &event.Timezone, &event.CreatedAt, &event.Visibility); err != nil {
+			return nil, fmt.Errorf("scan: %w", err)
+		}
+		event.Slots = []Slot(slotsCol)
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows: %w", err)
+	}
+
+	return events, nil
+}
+
+// GetEligibleEvents returns the events with a slot starting at or after now that the user's availability
+// contains, as GetUsersForSlot matches them, ordered by their earliest such slot. Only one-off availability
+// is consulted, not recurring rules. Private events are left out unless the user organizes them, as
+// they are from the listings.
+func (a *Accessor) GetEligibleEvents(ctx context.Context, userID uuid.UUID, now time.Time) (_ []EligibleEvent, err error) {
+	defer database.ObserveQuery("event.get_eligible_events")()
+	defer database.WrapError(&err, "event.get_eligible_events", userID)
+	query := `SELECT events.id, events.title, events.duration_hours, events.user_id, events.slots, events.timezone, events.created_at,
+		users.id, users.name, users.email,
+		jsonb_agg(slot.value ORDER BY (slot.value->>'start_time')::timestamptz)
+	FROM events
+	JOIN users ON users.id = events.user_id
+	CROSS JOIN LATERAL jsonb_array_elements(events.slots) AS slot(value)
+	WHERE events.deleted_at IS NULL
+		AND (events.visibility = 'public' OR events.user_id = $1)
+		AND (slot.value->>'start_time')::timestamptz >= $2
+		AND EXISTS (
+			SELECT 1 FROM users_availability
+			WHERE users_availability.user_id = $1
+				AND users_availability.start_time <= (slot.value->>'start_time')::timestamptz
+				AND users_availability.end_time >= (slot.value->>'end_time')::timestamptz
+				AND users_availability.end_time - users_availability.start_time >= make_interval(hours => events.duration_hours)
+		)
+	GROUP BY events.id, users.id
+	ORDER BY MIN((slot.value->>'start_time')::timestamptz), events.id`
+	rows, err := a.db.QueryContext(ctx, query, userID, now)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+	defer rows.Close()
+
+	events := []EligibleEvent{}
+	for rows.Next() {
+		var event EligibleEvent
+		var slotsCol, eligibleCol SlotsColumn
+		if err := rows.Scan(&event.ID, &event.Title, &event.DurationHours, &event.UserID, &slotsCol, &event.Timezone, &event.CreatedAt,
+			&event.Organizer.ID, &event.Organizer.Name, &event.Organizer.Email, &eligibleCol); err != nil {
+			return nil, fmt.Errorf("scan: %w", err)
+		}
+		event.Slots = []Slot(slotsCol)
+		event.EligibleSlots = []Slot(eligibleCol)
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows: %w", err)
+	}
+
+	return events, nil
+}
+
+// GetEventsByOrganizer returns the events organized by the user, oldest first.
+func (a *Accessor) GetEventsByOrganizer(ctx context.Context, organizerID uuid.UUID, limit, offset int) (_ []Event, err error) {
+	defer database.ObserveQuery("event.get_events_by_organizer")()
+	defer database.WrapError(&err, "event.get_events_by_organizer", organizerID)
+	query := `SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events
+	WHERE user_id = $1 AND deleted_at IS NULL
+	ORDER BY created_at, id
+	LIMIT $2 OFFSET $3`
+	rows, err := a.db.QueryContext(ctx, query, organizerID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+	defer rows.Close()
+
+	events := []Event{}
+	for rows.Next() {
+		var event Event
+		var slotsCol SlotsColumn
+		if err := rows.Scan(&event.ID, &event.Title, &event.DurationHours, &event.UserID, &slotsCol, &event.Timezone, &event.CreatedAt, &event.Visibility); err != nil {
+			return nil, fmt.Errorf("scan: %w", err)
+		}
+		event.Slots = []Slot(slotsCol)
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows: %w", err)
+	}
+
+	return events, nil
+}
+
+// likeEscaper escapes the ILIKE wildcards so that search text is matched literally.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// SearchEvents returns the listed events, as for GetEventsWithOrganizers, whose title contains q,
+// case-insensitively, in the same order.
+func (a *Accessor) SearchEvents(ctx context.Context, q string, limit, offset int) (_ []Event, err error) {
+	defer database.ObserveQuery("event.search_events")()
+	defer database.WrapError(&err, "event.search_events")
+	visible, args := a.visibleOnly([]any{likeEscaper.Replace(q), limit, offset})
+	query := `SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events
+	WHERE deleted_at IS NULL AND title ILIKE '%' || $1 || '%' ESCAPE '\'` + visible + `
+	ORDER BY created_at DESC, id
+	LIMIT $2 OFFSET $3`
+	rows, err := a.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+	defer rows.Close()
+
+	events := []Event{}
+	for rows.Next() {
+		var event Event
+		var slotsCol SlotsColumn
+		if err := rows.Scan(&event.ID, &event.Title, &event.DurationHours, &event.UserID, &slotsCol, &event.Timezone, &event.CreatedAt, &event.Visibility); err != nil {
+			return nil, fmt.Errorf("scan: %w", err)
+		}
+		event.Slots = []Slot(slotsCol)
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows: %w", err)
+	}
+
+	return events, nil
+}
+
+// CountSearchEvents returns the number of events SearchEvents can match for q.
+func (a *Accessor) CountSearchEvents(ctx context.Context, q string) (_ int, err error) {
+	defer database.ObserveQuery("event.count_search_events")()
+	defer database.WrapError(&err, "event.count_search_events")
+	var count int
+	visible, args := a.visibleOnly([]any{likeEscaper.Replace(q)})
+	query := `SELECT COUNT(*) FROM events WHERE deleted_at IS NULL AND title ILIKE '%' || $1 || '%' ESCAPE '\'` + visible
+	if err := a.db.QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("scan: %w", err)
+	}
+	return count, nil
+}
+
+// CountEvents returns the number of listed events, as for GetEventsWithOrganizers.
+func (a *Accessor) CountEvents(ctx context.Context) (_ int, err error) {
+	defer database.ObserveQuery("event.count_events")()
+	defer database.WrapError(&err, "event.count_events")
+	var count int
+	visible, args := a.visibleOnly(nil)
+	query := `SELECT COUNT(*) FROM events WHERE deleted_at IS NULL` + visible
+	if err := a.db.QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("scan: %w", err)
+	}
+	return count, nil
+}
+
+// CountEventsByOrganizer returns the number of events organized by the given user.
+func (a *Accessor) CountEventsByOrganizer(ctx context.Context, organizerID uuid.UUID) (_ int, err error) {
+	defer database.ObserveQuery("event.count_events_by_organizer")()
+	defer database.WrapError(&err, "event.count_events_by_organizer", organizerID)
+	var count int
+	query := `SELECT COUNT(*) FROM events WHERE deleted_at IS NULL AND user_id = $1`
+	if err := a.db.QueryRowContext(ctx, query, organizerID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("scan: %w", err)
+	}
+	return count, nil
+}
+
+// GetBestSlotStats aggregates the best slot of every event in a single query.
+// An event is viable when at least one user is available for one of its slots, and its best slot is the one with the most available users.
+// Deactivated users are not counted, as they are not when possible-slot picks a slot.
+func (a *Accessor) GetBestSlotStats(ctx context.Context) (_ *BestSlotStats, err error) {
+	defer database.ObserveQuery("event.get_best_slot_stats")()
+	defer database.WrapError(&err, "event.get_best_slot_stats")
+	query := `WITH slot_attendees AS (
+		SELECT events.id AS event_id, COUNT(DISTINCT users_availability.user_id) AS attendees
+		FROM events
+		CROSS JOIN LATERAL jsonb_array_elements(events.slots) AS slot(value)
+		JOIN users_availability
+			ON users_availability.start_time <= (slot.value->>'start_time')::timestamptz
+			AND users_availability.end_time >= (slot.value->>'end_time')::timestamptz
+			AND users_availability.end_time - users_availability.start_time >= make_interval(hours => events.duration_hours)
+		JOIN users ON users.id = users_availability.user_id AND users.active
+		WHERE events.deleted_at IS NULL
+		GROUP BY events.id, slot.value
+	), best_slots AS (
+		SELECT event_id, MAX(attendees) AS attendees FROM slot_attendees GROUP BY event_id
+	)
+	SELECT COUNT(*), COALESCE(AVG(attendees), 0) FROM best_slots`
+
+	var stats BestSlotStats
+	if err := a.db.QueryRowContext(ctx, query).Scan(&stats.ViableEvents, &stats.AvgAttendees); err != nil {
+		return nil, fmt.Errorf("scan: %w", err)
+	}
+	return &stats, nil
+}
+
+// SetRSVP records the user's RSVP for the event, overwriting any previous answer.
+func (a *Accessor) SetRSVP(ctx context.Context, eventID, userID uuid.UUID, status RSVPStatus) (err error) {
+	defer database.ObserveQuery("event.set_rsvp")()
+	defer database.WrapError(&err, "event.set_rsvp", eventID, userID)
+	if err := status.Validate(); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+
+	query := `INSERT INTO event_attendees (event_id, user_id, status) VALUES ($1, $2, $3)
+	ON CONFLICT (event_id, user_id) DO UPDATE SET status = EXCLUDED.status`
+	if _, err := a.db.ExecContext(ctx, query, eventID, userID, status); err != nil {
+		return fmt.Errorf("exec context: %w", err)
+	}
+	return nil
+}
+
+// GetRSVPConflicts returns the other events userID said yes to whose chosen slot overlaps the chosen
+// slot of evt, in event ID order. Events have no slot of their own, so the chosen slot is the one
+// possible-slot picks; an event that has none overlaps nothing. Deleted events are left out.
+func (a *Accessor) GetRSVPConflicts(ctx context.Context, evt *Event, userID uuid.UUID) (_ []RSVPConflict, err error) {
+	defer database.ObserveQuery("event.get_rsvp_conflicts")()
+	defer database.WrapError(&err, "event.get_rsvp_conflicts", evt.ID, userID)
+	query := `SELECT event_attendees.event_id
+	FROM event_attendees
+	JOIN events ON events.id = event_attendees.event_id
+	WHERE event_attendees.user_id = $1 AND event_attendees.status = $2 AND event_attendees.event_id <> $3
+		AND events.deleted_at IS NULL
+	ORDER BY event_attendees.event_id`
+	rows, err := a.db.QueryContext(ctx, query, userID, RSVPYes, evt.ID)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+	defer rows.Close()
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows: %w", err)
+	}
+
+	conflicts := []RSVPConflict{}
+	if len(ids) == 0 || len(evt.Slots) == 0 {
+		return conflicts, nil
+	}
+	// Every search shares the candidates and their availability, as in GetPossibleEventSlots.
+	search, err := a.newPossibleSlotSearch(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	chosen, err := a.findPossibleSlot(ctx, evt, search)
+	if err != nil {
+		return nil, err
+	}
+	if chosen == nil {
+		return conflicts, nil
+	}
+	for _, id := range ids {
+		other, err := a.GetEvent(ctx, id)
+		if errors.Is(err, ErrNotFound) {
+			// Deleted since the RSVPs were read.
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("get event: %w", err)
+		}
+		possible, err := a.findPossibleSlot(ctx, other, search)
+		if err != nil {
+			return nil, err
+		}
+		if possible != nil && possible.Slot.overlaps(chosen.Slot) {
+			conflicts = append(conflicts, RSVPConflict{Event: *other, Slot: possible.Slot})
+		}
+	}
+	return conflicts, nil
+}
+
+// GetAttendees returns the users that answered the event's invitation along with their RSVP.
+func (a *Accessor) GetAttendees(ctx context.Context, eventID uuid.UUID) (_ []Attendee, err error) {
+	defer database.ObserveQuery("event.get_attendees")()
+	defer database.WrapError(&err, "event.get_attendees", eventID)
+	query := `SELECT users.id, users.name, users.email, event_attendees.status
+	FROM event_attendees
+	JOIN users ON event_attendees.user_id = users.id
+	WHERE event_attendees.event_id = $1
+	ORDER BY users.name`
+	rows, err := a.db.QueryContext(ctx, query, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+	defer rows.Close()
+
+	attendees := []Attendee{}
+	for rows.Next() {
+		var attendee Attendee
+		if err := rows.Scan(&attendee.User.ID, &attendee.User.Name, &attendee.User.Email, &attendee.Status); err != nil {
+			return nil, fmt.Errorf("scan: %w", err)
+		}
+		attendees = append(attendees, attendee)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows: %w", err)
+	}
+
+	return attendees, nil
+}
@@ -3,14 +3,21 @@ package event_test
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"events-system/database"
 	"events-system/event"
 	"events-system/user"
+	"events-system/validation"
+	"fmt"
+	"os"
 	"regexp"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	testifymock "github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -26,11 +33,36 @@ func (m *MockUserAccessor) GetUsers(ctx context.Context) ([]user.User, error) {
 	return args.Get(0).([]user.User), args.Error(1)
 }
 
-func (m *MockUserAccessor) GetUsersForSlot(ctx context.Context, slot user.Slot, durationHours int) ([]user.User, error) {
-	args := m.Called(ctx, slot, durationHours)
+func (m *MockUserAccessor) GetUsersForSlot(ctx context.Context, slot user.Slot, durationHours int, userIDs ...uuid.UUID) ([]user.User, error) {
+	args := m.Called(ctx, slot, durationHours, userIDs)
 	return args.Get(0).([]user.User), args.Error(1)
 }
 
+func (m *MockUserAccessor) GetUsersForSlotOverlap(ctx context.Context, slot user.Slot, durationHours int, userIDs ...uuid.UUID) ([]user.User, error) {
+	args := m.Called(ctx, slot, durationHours, userIDs)
+	return args.Get(0).([]user.User), args.Error(1)
+}
+
+func (m *MockUserAccessor) GetUsersSlots(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID][]user.Slot, error) {
+	args := m.Called(ctx, userIDs)
+	return args.Get(0).(map[uuid.UUID][]user.Slot), args.Error(1)
+}
+
+func (m *MockUserAccessor) GetUsersRecurrences(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID][]user.Recurrence, error) {
+	args := m.Called(ctx, userIDs)
+	return args.Get(0).(map[uuid.UUID][]user.Recurrence), args.Error(1)
+}
+
+const conflictsQuery = `SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE user_id = $1 AND deleted_at IS NULL`
+
+func expectNoConflicts(dbMock sqlmock.Sqlmock, organizerID uuid.UUID, slots int) {
+	for range slots {
+		dbMock.ExpectQuery(regexp.QuoteMeta(conflictsQuery)).
+			WithArgs(organizerID, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}))
+	}
+}
+
 func TestEvent(t *testing.T) {
 	db, dbMock, err := sqlmock.New()
 	require.NoError(t, err)
@@ -55,9 +87,9 @@ func TestEvent(t *testing.T) {
 	}
 
 	t.Run("create event", func(t *testing.T) {
-		insertQuery := `INSERT INTO events (id, title, duration_hours, user_id, slots, created_at) VALUES ($1, $2, $3, $4, $5, $6)`
+		insertQuery := `INSERT INTO events (id, title, duration_hours, user_id, slots, timezone, created_at, visibility) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
 		dbMock.ExpectExec(regexp.QuoteMeta(insertQuery)).
-			WithArgs(sqlmock.AnyArg(), eventData.Title, eventData.DurationHours, eventData.UserID, event.SlotsColumn(eventData.Slots), sqlmock.AnyArg()).
+			WithArgs(sqlmock.AnyArg(), eventData.Title, eventData.DurationHours, eventData.UserID, event.SlotsColumn(eventData.Slots), eventData.Timezone, sqlmock.AnyArg(), "public").
 			WillReturnResult(sqlmock.NewResult(1, 1))
 
 		createdEvent, err := a.CreateEvent(t.Context(), eventData, now)
@@ -72,11 +104,117 @@ func TestEvent(t *testing.T) {
 		require.NoError(t, dbMock.ExpectationsWereMet())
 	})
 
+	t.Run("create event with a taken id", func(t *testing.T) {
+		taken := eventData
+		taken.ID = uuid.New()
+		dbMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO events (id, title, duration_hours, user_id, slots, timezone, created_at, visibility)`)).
+			WithArgs(taken.ID, taken.Title, taken.DurationHours, taken.UserID, event.SlotsColumn(taken.Slots), taken.Timezone, now, "public").
+			WillReturnError(&pq.Error{Code: "23505"})
+
+		createdEvent, err := a.CreateEvent(t.Context(), taken, now)
+		require.ErrorIs(t, err, event.ErrAlreadyExists)
+		assert.Nil(t, createdEvent)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("create event if organizer available", func(t *testing.T) {
+		availableQuery := `SELECT 1 FROM users_availability`
+		insertQuery := `INSERT INTO events (id, title, duration_hours, user_id, slots, timezone, created_at, visibility) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+		userAccessor.On("GetUsersRecurrences", testifymock.Anything, []uuid.UUID{organizerID}).
+			Return(map[uuid.UUID][]user.Recurrence{}, nil).Once()
+		dbMock.ExpectBegin()
+		dbMock.ExpectQuery(regexp.QuoteMeta(availableQuery)).
+			WithArgs(organizerID, event.SlotsColumn(eventData.Slots), eventData.DurationHours).
+			WillReturnRows(sqlmock.NewRows([]string{"?column?"}).AddRow(1))
+		dbMock.ExpectExec(regexp.QuoteMeta(insertQuery)).
+			WithArgs(sqlmock.AnyArg(), eventData.Title, eventData.DurationHours, eventData.UserID, event.SlotsColumn(eventData.Slots), eventData.Timezone, now, "public").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		dbMock.ExpectCommit()
+
+		createdEvent, err := a.CreateEventIfOrganizerAvailable(t.Context(), eventData, now)
+		require.NoError(t, err)
+		assert.NotEqual(t, uuid.Nil, createdEvent.ID)
+		assert.Equal(t, eventData.Slots, createdEvent.Slots)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		userAccessor.AssertExpectations(t)
+	})
+
+	t.Run("create event if organizer available - organizer unavailable", func(t *testing.T) {
+		availableQuery := `SELECT 1 FROM users_availability`
+		dbMock.ExpectBegin()
+		dbMock.ExpectQuery(regexp.QuoteMeta(availableQuery)).
+			WithArgs(organizerID, event.SlotsColumn(eventData.Slots), eventData.DurationHours).
+			WillReturnRows(sqlmock.NewRows([]string{"?column?"}))
+		dbMock.ExpectRollback()
+		userAccessor.On("GetUsersRecurrences", testifymock.Anything, []uuid.UUID{organizerID}).
+			Return(map[uuid.UUID][]user.Recurrence{}, nil).Once()
+
+		createdEvent, err := a.CreateEventIfOrganizerAvailable(t.Context(), eventData, now)
+		require.ErrorIs(t, err, event.ErrOrganizerUnavailable)
+		assert.Nil(t, createdEvent)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		userAccessor.AssertExpectations(t)
+	})
+
+	t.Run("create event if organizer available - recurring availability only", func(t *testing.T) {
+		// The organizer has no one-off availability, only Mondays 09:00-13:00.
+		monday := time.Date(2030, 1, 14, 0, 0, 0, 0, time.UTC)
+		recurrences := map[uuid.UUID][]user.Recurrence{
+			organizerID: {{Weekday: time.Monday, StartMinute: 9 * 60, EndMinute: 13 * 60, ValidFrom: monday.AddDate(0, 0, -7)}},
+		}
+		for _, tc := range []struct {
+			name          string
+			durationHours int
+			available     bool
+		}{
+			{name: "window long enough", durationHours: 2, available: true},
+			{name: "window shorter than the duration", durationHours: 5, available: false},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				evt := event.Event{
+					Title:         "Test Event",
+					DurationHours: tc.durationHours,
+					UserID:        organizerID,
+					Slots:         []event.Slot{{StartTime: monday.Add(10 * time.Hour), EndTime: monday.Add(12 * time.Hour)}},
+				}
+				userAccessor.On("GetUsersRecurrences", testifymock.Anything, []uuid.UUID{organizerID}).
+					Return(recurrences, nil).Once()
+				dbMock.ExpectBegin()
+				// A matching rule makes the one-off availability irrelevant.
+				if tc.available {
+					dbMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO events`)).
+						WithArgs(sqlmock.AnyArg(), evt.Title, tc.durationHours, organizerID, event.SlotsColumn(evt.Slots), evt.Timezone, now, "public").
+						WillReturnResult(sqlmock.NewResult(1, 1))
+					dbMock.ExpectCommit()
+				} else {
+					dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT 1 FROM users_availability`)).
+						WithArgs(organizerID, event.SlotsColumn(evt.Slots), tc.durationHours).
+						WillReturnRows(sqlmock.NewRows([]string{"?column?"}))
+					dbMock.ExpectRollback()
+				}
+
+				createdEvent, err := a.CreateEventIfOrganizerAvailable(t.Context(), evt, now)
+				if tc.available {
+					require.NoError(t, err)
+					assert.Equal(t, evt.Slots, createdEvent.Slots)
+				} else {
+					require.ErrorIs(t, err, event.ErrOrganizerUnavailable)
+				}
+
+				require.NoError(t, dbMock.ExpectationsWereMet())
+				userAccessor.AssertExpectations(t)
+			})
+		}
+	})
+
 	t.Run("get event", func(t *testing.T) {
 		slotsJSON, _ := event.SlotsColumn(eventData.Slots).Value()
-		selectQuery := `SELECT id, title, duration_hours, user_id, slots, created_at FROM events WHERE id = $1`
-		rows := sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "created_at"}).
-			AddRow(eventID, eventData.Title, eventData.DurationHours, eventData.UserID, slotsJSON, now)
+		selectQuery := `SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1`
+		rows := sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}).
+			AddRow(eventID, eventData.Title, eventData.DurationHours, eventData.UserID, slotsJSON, "UTC", now, "public")
 
 		dbMock.ExpectQuery(regexp.QuoteMeta(selectQuery)).
 			WithArgs(eventID).
@@ -92,15 +230,28 @@ func TestEvent(t *testing.T) {
 		require.NoError(t, dbMock.ExpectationsWereMet())
 	})
 
+	t.Run("get private event", func(t *testing.T) {
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1`)).
+			WithArgs(eventID).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}).
+				AddRow(eventID, eventData.Title, eventData.DurationHours, eventData.UserID, []byte("[]"), "UTC", now, "private"))
+
+		evt, err := a.GetEvent(t.Context(), eventID)
+		require.NoError(t, err)
+		assert.Equal(t, event.VisibilityPrivate, evt.Visibility)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
 	t.Run("get event - no rows", func(t *testing.T) {
 		noRowsID := uuid.New()
-		selectQuery := `SELECT id, title, duration_hours, user_id, slots, created_at FROM events WHERE id = $1`
+		selectQuery := `SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1`
 		dbMock.ExpectQuery(regexp.QuoteMeta(selectQuery)).
 			WithArgs(noRowsID).
 			WillReturnError(sql.ErrNoRows)
 
 		evt, err := a.GetEvent(t.Context(), noRowsID)
-		require.NoError(t, err)
+		require.ErrorIs(t, err, event.ErrNotFound)
 		require.Nil(t, evt)
 
 		require.NoError(t, dbMock.ExpectationsWereMet())
@@ -117,16 +268,16 @@ func TestEvent(t *testing.T) {
 			},
 		}
 
-		updateQuery := `UPDATE events SET title = $1, duration_hours = $2, slots = $3 WHERE id = $4`
+		updateQuery := `UPDATE events SET title = $1, duration_hours = $2, slots = $3, timezone = $4 WHERE id = $5`
 		updatedSlotsJSON, _ := event.SlotsColumn(updatedEvent.Slots).Value()
 		dbMock.ExpectExec(regexp.QuoteMeta(updateQuery)).
-			WithArgs(updatedEvent.Title, updatedEvent.DurationHours, updatedSlotsJSON, updatedEvent.ID).
+			WithArgs(updatedEvent.Title, updatedEvent.DurationHours, updatedSlotsJSON, updatedEvent.Timezone, updatedEvent.ID).
 			WillReturnResult(sqlmock.NewResult(1, 1))
 
 		// After update, GetEvent is called to return the updated event with original created_at
-		selectQuery := `SELECT id, title, duration_hours, user_id, slots, created_at FROM events WHERE id = $1`
-		rows := sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "created_at"}).
-			AddRow(updatedEvent.ID, updatedEvent.Title, updatedEvent.DurationHours, updatedEvent.UserID, updatedSlotsJSON, now)
+		selectQuery := `SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1`
+		rows := sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}).
+			AddRow(updatedEvent.ID, updatedEvent.Title, updatedEvent.DurationHours, updatedEvent.UserID, updatedSlotsJSON, "UTC", now, "public")
 		dbMock.ExpectQuery(regexp.QuoteMeta(selectQuery)).
 			WithArgs(updatedEvent.ID).
 			WillReturnRows(rows)
@@ -142,15 +293,460 @@ func TestEvent(t *testing.T) {
 	})
 
 	t.Run("delete event", func(t *testing.T) {
-		deleteQuery := `DELETE FROM events WHERE id = $1`
+		deleteQuery := `UPDATE events SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL`
 		dbMock.ExpectExec(regexp.QuoteMeta(deleteQuery)).
+			WithArgs(now, eventID).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		err := a.DeleteEvent(t.Context(), eventID, now)
+		require.NoError(t, err)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("get event - soft deleted is hidden", func(t *testing.T) {
+		selectQuery := `SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1 AND deleted_at IS NULL`
+		dbMock.ExpectQuery(regexp.QuoteMeta(selectQuery)).
+			WithArgs(eventID).
+			WillReturnError(sql.ErrNoRows)
+
+		evt, err := a.GetEvent(t.Context(), eventID)
+		require.ErrorIs(t, err, event.ErrNotFound)
+		require.Nil(t, evt)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("get event including deleted", func(t *testing.T) {
+		selectQuery := `SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility, deleted_at FROM events WHERE id = $1`
+		dbMock.ExpectQuery(regexp.QuoteMeta(selectQuery)).
+			WithArgs(eventID).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility", "deleted_at"}).
+				AddRow(eventID, eventData.Title, eventData.DurationHours, eventData.UserID, []byte("[]"), "UTC", now, "private", now))
+
+		evt, err := a.GetEventIncludingDeleted(t.Context(), eventID)
+		require.NoError(t, err)
+		require.NotNil(t, evt)
+		require.NotNil(t, evt.DeletedAt)
+		assert.Equal(t, now, *evt.DeletedAt)
+		assert.Equal(t, event.VisibilityPrivate, evt.Visibility)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+	})
+}
+
+func TestGetEventPrepared(t *testing.T) {
+	db, dbMock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	stmts := database.NewStatements(db)
+	a := event.NewAccessor(db, new(MockUserAccessor)).WithStatements(stmts)
+	eventID, organizerID := uuid.New(), uuid.New()
+	createdAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	slot := event.Slot{StartTime: time.Date(2030, 1, 2, 9, 0, 0, 0, time.UTC), EndTime: time.Date(2030, 1, 2, 11, 0, 0, 0, time.UTC)}
+	slotsJSON := []byte(`[{"start_time":"2030-01-02T09:00:00Z","end_time":"2030-01-02T11:00:00Z"}]`)
+
+	// The statement is prepared by the first read and reused by the second.
+	prepared := dbMock.ExpectPrepare(regexp.QuoteMeta(`SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1 AND deleted_at IS NULL`))
+	for range 2 {
+		prepared.ExpectQuery().
+			WithArgs(eventID).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}).
+				AddRow(eventID, "Planning", 2, organizerID, slotsJSON, "UTC", createdAt, "public"))
+	}
+	prepared.ExpectQuery().WithArgs(sqlmock.AnyArg()).WillReturnError(sql.ErrNoRows)
+	prepared.WillBeClosed()
+
+	want := &event.Event{ID: eventID, Title: "Planning", DurationHours: 2, UserID: organizerID, Slots: []event.Slot{slot}, Timezone: "UTC", Visibility: event.VisibilityPublic, CreatedAt: createdAt}
+	for range 2 {
+		got, err := a.GetEvent(t.Context(), eventID)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+	_, err = a.GetEvent(t.Context(), uuid.New())
+	assert.ErrorIs(t, err, event.ErrNotFound)
+
+	require.NoError(t, stmts.Close())
+	require.NoError(t, dbMock.ExpectationsWereMet())
+}
+
+// BenchmarkGetEvent compares GetEvent with and without prepared statements against the Postgres at
+// POSTGRES_DSN, since the parsing and planning they save only happen on a real server.
+func BenchmarkGetEvent(b *testing.B) {
+	dsn := os.Getenv("POSTGRES_DSN")
+	if dsn == "" {
+		b.Skip("POSTGRES_DSN is not set")
+	}
+	db, err := database.Connect(database.Config{DSN: dsn})
+	require.NoError(b, err)
+	b.Cleanup(func() { _ = db.Close() })
+	ctx := context.Background()
+	_, err = database.Migrate(ctx, db)
+	require.NoError(b, err)
+
+	users := user.NewAccessor(db)
+	organizer, err := users.CreateUser(ctx, user.User{Name: "Benchmark", Email: uuid.NewString() + "@example.com"}, time.Now())
+	require.NoError(b, err)
+	start := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+	evt, err := event.NewAccessor(db, users).CreateEvent(ctx, event.Event{
+		Title:         "Benchmark",
+		DurationHours: 1,
+		UserID:        organizer.ID,
+		Slots:         []event.Slot{{StartTime: start, EndTime: start.Add(time.Hour)}},
+		Timezone:      "UTC",
+	}, time.Now())
+	require.NoError(b, err)
+
+	stmts := database.NewStatements(db)
+	b.Cleanup(func() { _ = stmts.Close() })
+	for name, a := range map[string]*event.Accessor{
+		"unprepared": event.NewAccessor(db, users),
+		"prepared":   event.NewAccessor(db, users).WithStatements(stmts),
+	} {
+		b.Run(name, func(b *testing.B) {
+			for b.Loop() {
+				if _, err := a.GetEvent(ctx, evt.ID); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestReassignEvents(t *testing.T) {
+	db, dbMock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	a := event.NewAccessor(db, new(MockUserAccessor))
+	fromID := uuid.New()
+	toID := uuid.New()
+
+	t.Run("reassign events successfully", func(t *testing.T) {
+		dbMock.ExpectBegin()
+		updateQuery := `UPDATE events SET user_id = $1 WHERE user_id = $2`
+		dbMock.ExpectExec(regexp.QuoteMeta(updateQuery)).
+			WithArgs(toID, fromID).
+			WillReturnResult(sqlmock.NewResult(0, 3))
+		dbMock.ExpectCommit()
+
+		moved, err := a.ReassignEvents(t.Context(), fromID, toID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), moved)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("reassign events - rollback on error", func(t *testing.T) {
+		dbMock.ExpectBegin()
+		updateQuery := `UPDATE events SET user_id = $1 WHERE user_id = $2`
+		dbMock.ExpectExec(regexp.QuoteMeta(updateQuery)).
+			WithArgs(toID, fromID).
+			WillReturnError(sql.ErrConnDone)
+		dbMock.ExpectRollback()
+
+		_, err := a.ReassignEvents(t.Context(), fromID, toID)
+		require.Error(t, err)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+	})
+}
+
+func TestTransferEvent(t *testing.T) {
+	db, dbMock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	a := event.NewAccessor(db, new(MockUserAccessor))
+	eventID := uuid.New()
+	newOrganizerID := uuid.New()
+	updateQuery := `UPDATE events SET user_id = $1 WHERE id = $2 AND deleted_at IS NULL`
+
+	t.Run("transfer event successfully", func(t *testing.T) {
+		dbMock.ExpectExec(regexp.QuoteMeta(updateQuery)).
+			WithArgs(newOrganizerID, eventID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1`)).
+			WithArgs(eventID).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}).
+				AddRow(eventID, "Test Event", 2, newOrganizerID, []byte("[]"), "UTC", time.Now(), "public"))
+
+		e, err := a.TransferEvent(t.Context(), eventID, newOrganizerID)
+		require.NoError(t, err)
+		assert.Equal(t, newOrganizerID, e.UserID)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("transfer event - not found", func(t *testing.T) {
+		dbMock.ExpectExec(regexp.QuoteMeta(updateQuery)).
+			WithArgs(newOrganizerID, eventID).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		_, err := a.TransferEvent(t.Context(), eventID, newOrganizerID)
+		require.ErrorIs(t, err, event.ErrNotFound)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+	})
+}
+
+func TestSetEventSlots(t *testing.T) {
+	db, dbMock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	a := event.NewAccessor(db, new(MockUserAccessor))
+	eventID := uuid.New()
+	start := time.Date(2030, 1, 2, 9, 0, 0, 0, time.UTC)
+	slots := []event.Slot{{StartTime: start, EndTime: start.Add(time.Hour)}}
+	updateQuery := `UPDATE events SET slots = $1 WHERE id = $2 AND deleted_at IS NULL`
+
+	t.Run("replace slots", func(t *testing.T) {
+		dbMock.ExpectExec(regexp.QuoteMeta(updateQuery)).
+			WithArgs(event.SlotsColumn(slots), eventID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		stored, _ := event.SlotsColumn(slots).Value()
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1`)).
 			WithArgs(eventID).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}).
+				AddRow(eventID, "Test Event", 2, uuid.New(), stored, "UTC", time.Now(), "public"))
+
+		e, err := a.SetEventSlots(t.Context(), eventID, slots)
+		require.NoError(t, err)
+		assert.Equal(t, "Test Event", e.Title)
+		assert.Equal(t, slots, e.Slots)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("invalid slot", func(t *testing.T) {
+		_, err := a.SetEventSlots(t.Context(), eventID, []event.Slot{{StartTime: start, EndTime: start.Add(-time.Hour)}})
+		require.ErrorContains(t, err, "start time is after end time")
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		dbMock.ExpectExec(regexp.QuoteMeta(updateQuery)).
+			WithArgs(event.SlotsColumn(nil), eventID).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		_, err := a.SetEventSlots(t.Context(), eventID, nil)
+		require.ErrorIs(t, err, event.ErrNotFound)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+	})
+}
+
+func TestRSVP(t *testing.T) {
+	db, dbMock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	a := event.NewAccessor(db, new(MockUserAccessor))
+	eventID := uuid.New()
+	userID := uuid.New()
+
+	upsertQuery := `INSERT INTO event_attendees (event_id, user_id, status) VALUES ($1, $2, $3)
+	ON CONFLICT (event_id, user_id) DO UPDATE SET status = EXCLUDED.status`
+
+	t.Run("set rsvp", func(t *testing.T) {
+		dbMock.ExpectExec(regexp.QuoteMeta(upsertQuery)).
+			WithArgs(eventID, userID, event.RSVPYes).
 			WillReturnResult(sqlmock.NewResult(1, 1))
 
-		err := a.DeleteEvent(t.Context(), eventID)
+		require.NoError(t, a.SetRSVP(t.Context(), eventID, userID, event.RSVPYes))
+		require.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("overwrite rsvp", func(t *testing.T) {
+		dbMock.ExpectExec(regexp.QuoteMeta(upsertQuery)).
+			WithArgs(eventID, userID, event.RSVPMaybe).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		require.NoError(t, a.SetRSVP(t.Context(), eventID, userID, event.RSVPMaybe))
+		require.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("invalid status", func(t *testing.T) {
+		err := a.SetRSVP(t.Context(), eventID, userID, event.RSVPStatus("sure"))
+		require.Error(t, err)
+		require.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("get attendees", func(t *testing.T) {
+		otherID := uuid.New()
+		selectQuery := `SELECT users.id, users.name, users.email, event_attendees.status
+	FROM event_attendees
+	JOIN users ON event_attendees.user_id = users.id
+	WHERE event_attendees.event_id = $1
+	ORDER BY users.name`
+		dbMock.ExpectQuery(regexp.QuoteMeta(selectQuery)).
+			WithArgs(eventID).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "status"}).
+				AddRow(userID, "Alice", "alice@example.com", "maybe").
+				AddRow(otherID, "Bob", "bob@example.com", "no"))
+
+		attendees, err := a.GetAttendees(t.Context(), eventID)
+		require.NoError(t, err)
+		require.Len(t, attendees, 2)
+		assert.Equal(t, userID, attendees[0].User.ID)
+		assert.Equal(t, event.RSVPMaybe, attendees[0].Status)
+		assert.Equal(t, otherID, attendees[1].User.ID)
+		assert.Equal(t, event.RSVPNo, attendees[1].Status)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+	})
+}
+
+func TestGetPossibleEventSlotTrim(t *testing.T) {
+	at := func(hour, minute int) time.Time { return time.Date(2030, 1, 7, hour, minute, 0, 0, time.UTC) }
+	userSlot := func(startHour, startMinute, endHour, endMinute int) user.Slot {
+		return user.Slot{StartTime: at(startHour, startMinute), EndTime: at(endHour, endMinute)}
+	}
+	slot := event.Slot{StartTime: at(9, 0), EndTime: at(12, 0)}
+	alice := user.User{ID: uuid.New(), Name: "Alice"}
+	bob := user.User{ID: uuid.New(), Name: "Bob"}
+	carol := user.User{ID: uuid.New(), Name: "Carol"}
+	dave := user.User{ID: uuid.New(), Name: "Dave"}
+	everyone := []user.User{alice, bob, carol, dave}
+
+	// search finds the possible slot of a one hour event proposing 9-12, for which every user's
+	// availability overlaps the slot by an hour, each having the given one-off slots and rules.
+	search := func(t *testing.T, slotsByUser map[uuid.UUID][]user.Slot, recurrencesByUser map[uuid.UUID][]user.Recurrence) *event.PossibleEventSlot {
+		db, dbMock, err := sqlmock.New()
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = db.Close() })
+		userAccessor := new(MockUserAccessor)
+
+		// Without an organizer there are no organizer conflicts to look up.
+		eventID := uuid.New()
+		slotsJSON, _ := event.SlotsColumn([]event.Slot{slot}).Value()
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1`)).
+			WithArgs(eventID).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}).
+				AddRow(eventID, "Standup", 1, uuid.Nil, slotsJSON, "UTC", at(0, 0), "public"))
+		userAccessor.On("GetUsers", testifymock.Anything).Return(everyone, nil)
+		userAccessor.On("GetUsersForSlotOverlap", testifymock.Anything, user.Slot{StartTime: slot.StartTime, EndTime: slot.EndTime}, 1, []uuid.UUID(nil)).
+			Return(everyone, nil)
+		userAccessor.On("GetUsersSlots", testifymock.Anything, []uuid.UUID{alice.ID, bob.ID, carol.ID, dave.ID}).Return(slotsByUser, nil)
+		userAccessor.On("GetUsersRecurrences", testifymock.Anything, []uuid.UUID{alice.ID, bob.ID, carol.ID, dave.ID}).Return(recurrencesByUser, nil)
+
+		possible, err := event.NewAccessor(db, userAccessor).WithTrim().GetPossibleEventSlot(t.Context(), eventID)
+		require.NoError(t, err)
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		userAccessor.AssertExpectations(t)
+		return possible
+	}
+
+	t.Run("partial coverage trims the slot to the window most users share", func(t *testing.T) {
+		possible := search(t, map[uuid.UUID][]user.Slot{
+			alice.ID: {userSlot(8, 0, 11, 0)},
+			bob.ID:   {userSlot(10, 0, 13, 0)},
+			carol.ID: {userSlot(10, 0, 11, 30)},
+			dave.ID:  {userSlot(11, 0, 12, 0)},
+		}, map[uuid.UUID][]user.Recurrence{})
+		require.NotNil(t, possible)
+		assert.Equal(t, slot, possible.Slot)
+		assert.Equal(t, &event.Slot{StartTime: at(10, 0), EndTime: at(11, 0)}, possible.Window)
+		assert.Equal(t, []user.User{alice, bob, carol}, possible.Users)
+		assert.Equal(t, []user.User{dave}, possible.NotWorkingUsers)
+	})
+
+	t.Run("the window stretches as long as everyone stays free", func(t *testing.T) {
+		possible := search(t, map[uuid.UUID][]user.Slot{
+			// Touching slots count as one stretch of availability.
+			alice.ID: {userSlot(9, 0, 10, 0), userSlot(10, 0, 12, 0)},
+			bob.ID:   {userSlot(9, 30, 13, 0)},
+			carol.ID: {userSlot(8, 0, 11, 45)},
+			dave.ID:  {userSlot(9, 0, 12, 0)},
+		}, map[uuid.UUID][]user.Recurrence{})
+		require.NotNil(t, possible)
+		assert.Equal(t, &event.Slot{StartTime: at(9, 30), EndTime: at(11, 45)}, possible.Window)
+		assert.Equal(t, everyone, possible.Users)
+		assert.Empty(t, possible.NotWorkingUsers)
+	})
+
+	t.Run("recurring availability is expanded over the slot", func(t *testing.T) {
+		// 2030-01-07 is a Monday. Dave is only available through a Monday 10-11 rule, and Alice
+		// through a rule along with a one-off slot it touches.
+		possible := search(t, map[uuid.UUID][]user.Slot{
+			alice.ID: {userSlot(9, 0, 10, 0)},
+			bob.ID:   {userSlot(9, 30, 13, 0)},
+			carol.ID: {userSlot(8, 0, 11, 45)},
+		}, map[uuid.UUID][]user.Recurrence{
+			alice.ID: {{Weekday: time.Monday, StartMinute: 10 * 60, EndMinute: 12 * 60, ValidFrom: at(0, 0)}},
+			dave.ID:  {{Weekday: time.Monday, StartMinute: 10 * 60, EndMinute: 11 * 60, ValidFrom: at(0, 0)}},
+		})
+		require.NotNil(t, possible)
+		assert.Equal(t, &event.Slot{StartTime: at(10, 0), EndTime: at(11, 0)}, possible.Window)
+		assert.Equal(t, everyone, possible.Users)
+		assert.Empty(t, possible.NotWorkingUsers)
+	})
+}
+
+func TestGetRSVPConflicts(t *testing.T) {
+	at := func(hour int) time.Time { return time.Date(2030, 1, 7, hour, 0, 0, 0, time.UTC) }
+	userID := uuid.New()
+	attendee := user.User{ID: userID, Name: "Alice", Email: "alice@example.com"}
+	yesQuery := regexp.QuoteMeta(`SELECT event_attendees.event_id
+	FROM event_attendees`)
+	getEventQuery := regexp.QuoteMeta(`SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1 AND deleted_at IS NULL`)
+	eventRow := func(id uuid.UUID, title string, start, end int) *sqlmock.Rows {
+		slotsJSON, _ := event.SlotsColumn([]event.Slot{{StartTime: at(start), EndTime: at(end)}}).Value()
+		// Without an organizer there are no organizer conflicts to look up.
+		return sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}).
+			AddRow(id, title, 1, uuid.Nil, slotsJSON, "UTC", at(0), "public")
+	}
+	evt := &event.Event{ID: uuid.New(), Title: "Planning", DurationHours: 1, Slots: []event.Slot{{StartTime: at(9), EndTime: at(11)}}}
+
+	t.Run("lists the yes events whose chosen slot overlaps", func(t *testing.T) {
+		db, dbMock, err := sqlmock.New()
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = db.Close() })
+		userAccessor := new(MockUserAccessor)
+		a := event.NewAccessor(db, userAccessor)
+
+		overlappingID, touchingID := uuid.New(), uuid.New()
+		dbMock.ExpectQuery(yesQuery).
+			WithArgs(userID, event.RSVPYes, evt.ID).
+			WillReturnRows(sqlmock.NewRows([]string{"event_id"}).AddRow(overlappingID).AddRow(touchingID))
+		dbMock.ExpectQuery(getEventQuery).WithArgs(overlappingID).WillReturnRows(eventRow(overlappingID, "Retro", 10, 12))
+		// Ending as the event starts is no overlap.
+		dbMock.ExpectQuery(getEventQuery).WithArgs(touchingID).WillReturnRows(eventRow(touchingID, "Lunch", 11, 12))
+
+		userAccessor.On("GetUsers", testifymock.Anything).Return([]user.User{attendee}, nil)
+		for _, slot := range []user.Slot{{StartTime: at(9), EndTime: at(11)}, {StartTime: at(10), EndTime: at(12)}, {StartTime: at(11), EndTime: at(12)}} {
+			userAccessor.On("GetUsersForSlot", testifymock.Anything, slot, 1, []uuid.UUID(nil)).Return([]user.User{attendee}, nil)
+		}
+
+		conflicts, err := a.GetRSVPConflicts(t.Context(), evt, userID)
+		require.NoError(t, err)
+		require.Len(t, conflicts, 1)
+		assert.Equal(t, overlappingID, conflicts[0].Event.ID)
+		assert.Equal(t, event.Slot{StartTime: at(10), EndTime: at(12)}, conflicts[0].Slot)
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		userAccessor.AssertExpectations(t)
+	})
+
+	t.Run("no other yes events", func(t *testing.T) {
+		db, dbMock, err := sqlmock.New()
 		require.NoError(t, err)
+		t.Cleanup(func() { _ = db.Close() })
+		userAccessor := new(MockUserAccessor)
+		a := event.NewAccessor(db, userAccessor)
+
+		dbMock.ExpectQuery(yesQuery).
+			WithArgs(userID, event.RSVPYes, evt.ID).
+			WillReturnRows(sqlmock.NewRows([]string{"event_id"}))
 
+		conflicts, err := a.GetRSVPConflicts(t.Context(), evt, userID)
+		require.NoError(t, err)
+		assert.Empty(t, conflicts)
 		require.NoError(t, dbMock.ExpectationsWereMet())
+		// Nothing to compare with, so no slot is searched.
+		userAccessor.AssertNotCalled(t, "GetUsers", testifymock.Anything)
 	})
 }
 
@@ -175,13 +771,13 @@ func TestGetPossibleEventSlot(t *testing.T) {
 	user3 := user.User{ID: uuid.New(), Name: "User 3", Email: "user3@example.com"}
 
 	t.Run("event not found", func(t *testing.T) {
-		selectQuery := `SELECT id, title, duration_hours, user_id, slots, created_at FROM events WHERE id = $1`
+		selectQuery := `SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1`
 		dbMock.ExpectQuery(regexp.QuoteMeta(selectQuery)).
 			WithArgs(eventID).
 			WillReturnError(sql.ErrNoRows)
 
 		result, err := a.GetPossibleEventSlot(t.Context(), eventID)
-		require.NoError(t, err)
+		require.ErrorIs(t, err, event.ErrNotFound)
 		require.Nil(t, result)
 
 		require.NoError(t, dbMock.ExpectationsWereMet())
@@ -198,9 +794,9 @@ func TestGetPossibleEventSlot(t *testing.T) {
 			Slots:         []event.Slot{},
 		}
 
-		selectQuery := `SELECT id, title, duration_hours, user_id, slots, created_at FROM events WHERE id = $1`
-		rows := sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "created_at"}).
-			AddRow(eventData.ID, eventData.Title, eventData.DurationHours, eventData.UserID, []byte("[]"), now)
+		selectQuery := `SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1`
+		rows := sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}).
+			AddRow(eventData.ID, eventData.Title, eventData.DurationHours, eventData.UserID, []byte("[]"), "UTC", now, "public")
 
 		dbMock.ExpectQuery(regexp.QuoteMeta(selectQuery)).
 			WithArgs(eventID).
@@ -233,18 +829,19 @@ func TestGetPossibleEventSlot(t *testing.T) {
 		availableUsers := []user.User{user1, user2, user3}
 		slotsJSON, _ := event.SlotsColumn(eventData.Slots).Value()
 
-		selectQuery := `SELECT id, title, duration_hours, user_id, slots, created_at FROM events WHERE id = $1`
-		rows := sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "created_at"}).
-			AddRow(eventData.ID, eventData.Title, eventData.DurationHours, eventData.UserID, slotsJSON, now)
+		selectQuery := `SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1`
+		rows := sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}).
+			AddRow(eventData.ID, eventData.Title, eventData.DurationHours, eventData.UserID, slotsJSON, "UTC", now, "public")
 
 		dbMock.ExpectQuery(regexp.QuoteMeta(selectQuery)).
 			WithArgs(eventID).
 			WillReturnRows(rows)
+		expectNoConflicts(dbMock, organizerID, 1)
 
 		userAccessor.On("GetUsers", testifymock.Anything).Return(allUsers, nil)
 		userAccessor.On("GetUsersForSlot", testifymock.Anything, testifymock.MatchedBy(func(s user.Slot) bool {
 			return s.StartTime.Unix() == startTime1.Unix() && s.EndTime.Unix() == endTime1.Unix()
-		}), 2).Return(availableUsers, nil)
+		}), 2, []uuid.UUID(nil)).Return(availableUsers, nil)
 
 		result, err := a.GetPossibleEventSlot(t.Context(), eventID)
 		require.NoError(t, err)
@@ -277,21 +874,22 @@ func TestGetPossibleEventSlot(t *testing.T) {
 		slot2Users := []user.User{user1, user2, user3} // 3 users - should be selected
 		slotsJSON, _ := event.SlotsColumn(eventData.Slots).Value()
 
-		selectQuery := `SELECT id, title, duration_hours, user_id, slots, created_at FROM events WHERE id = $1`
-		rows := sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "created_at"}).
-			AddRow(eventData.ID, eventData.Title, eventData.DurationHours, eventData.UserID, slotsJSON, now)
+		selectQuery := `SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1`
+		rows := sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}).
+			AddRow(eventData.ID, eventData.Title, eventData.DurationHours, eventData.UserID, slotsJSON, "UTC", now, "public")
 
 		dbMock.ExpectQuery(regexp.QuoteMeta(selectQuery)).
 			WithArgs(eventID).
 			WillReturnRows(rows)
+		expectNoConflicts(dbMock, organizerID, 2)
 
 		userAccessor.On("GetUsers", testifymock.Anything).Return(allUsers, nil)
 		userAccessor.On("GetUsersForSlot", testifymock.Anything, testifymock.MatchedBy(func(s user.Slot) bool {
 			return s.StartTime.Unix() == startTime1.Unix() && s.EndTime.Unix() == endTime1.Unix()
-		}), 2).Return(slot1Users, nil)
+		}), 2, []uuid.UUID(nil)).Return(slot1Users, nil)
 		userAccessor.On("GetUsersForSlot", testifymock.Anything, testifymock.MatchedBy(func(s user.Slot) bool {
 			return s.StartTime.Unix() == startTime2.Unix() && s.EndTime.Unix() == endTime2.Unix()
-		}), 2).Return(slot2Users, nil)
+		}), 2, []uuid.UUID(nil)).Return(slot2Users, nil)
 
 		result, err := a.GetPossibleEventSlot(t.Context(), eventID)
 		require.NoError(t, err)
@@ -304,6 +902,50 @@ func TestGetPossibleEventSlot(t *testing.T) {
 		userAccessor.AssertExpectations(t)
 	})
 
+	t.Run("duplicate slots are queried once", func(t *testing.T) {
+		userAccessor.ExpectedCalls = nil
+		userAccessor.Calls = nil
+
+		eventData := event.Event{
+			ID:            eventID,
+			Title:         "Test Event",
+			DurationHours: 2,
+			UserID:        organizerID,
+			Slots: []event.Slot{
+				{StartTime: startTime1, EndTime: endTime1},
+				{StartTime: startTime2, EndTime: endTime2},
+				{StartTime: startTime1, EndTime: endTime1},
+				{StartTime: startTime2, EndTime: endTime2},
+			},
+		}
+		slotsJSON, _ := event.SlotsColumn(eventData.Slots).Value()
+
+		selectQuery := `SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1`
+		dbMock.ExpectQuery(regexp.QuoteMeta(selectQuery)).
+			WithArgs(eventID).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}).
+				AddRow(eventData.ID, eventData.Title, eventData.DurationHours, eventData.UserID, slotsJSON, "UTC", now, "public"))
+		expectNoConflicts(dbMock, organizerID, 2)
+
+		userAccessor.On("GetUsers", testifymock.Anything).Return([]user.User{user1, user2, user3}, nil)
+		userAccessor.On("GetUsersForSlot", testifymock.Anything, testifymock.MatchedBy(func(s user.Slot) bool {
+			return s.StartTime.Unix() == startTime1.Unix()
+		}), 2, []uuid.UUID(nil)).Return([]user.User{user1}, nil).Once()
+		userAccessor.On("GetUsersForSlot", testifymock.Anything, testifymock.MatchedBy(func(s user.Slot) bool {
+			return s.StartTime.Unix() == startTime2.Unix()
+		}), 2, []uuid.UUID(nil)).Return([]user.User{user1, user2}, nil).Once()
+
+		result, err := a.GetPossibleEventSlot(t.Context(), eventID)
+		require.NoError(t, err)
+		require.NotNil(t, result)
+		assert.Equal(t, startTime2.Unix(), result.Slot.StartTime.Unix())
+		assert.Equal(t, []user.User{user1, user2}, result.Users)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		userAccessor.AssertExpectations(t)
+		userAccessor.AssertNumberOfCalls(t, "GetUsersForSlot", 2)
+	})
+
 	t.Run("no users available for any slot", func(t *testing.T) {
 		userAccessor.ExpectedCalls = nil
 		userAccessor.Calls = nil
@@ -322,25 +964,23 @@ func TestGetPossibleEventSlot(t *testing.T) {
 		availableUsers := []user.User{} // No users available
 		slotsJSON, _ := event.SlotsColumn(eventData.Slots).Value()
 
-		selectQuery := `SELECT id, title, duration_hours, user_id, slots, created_at FROM events WHERE id = $1`
-		rows := sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "created_at"}).
-			AddRow(eventData.ID, eventData.Title, eventData.DurationHours, eventData.UserID, slotsJSON, now)
+		selectQuery := `SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1`
+		rows := sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}).
+			AddRow(eventData.ID, eventData.Title, eventData.DurationHours, eventData.UserID, slotsJSON, "UTC", now, "public")
 
 		dbMock.ExpectQuery(regexp.QuoteMeta(selectQuery)).
 			WithArgs(eventID).
 			WillReturnRows(rows)
+		expectNoConflicts(dbMock, organizerID, 1)
 
 		userAccessor.On("GetUsers", testifymock.Anything).Return(allUsers, nil)
 		userAccessor.On("GetUsersForSlot", testifymock.Anything, testifymock.MatchedBy(func(s user.Slot) bool {
 			return s.StartTime.Unix() == startTime1.Unix() && s.EndTime.Unix() == endTime1.Unix()
-		}), 2).Return(availableUsers, nil)
+		}), 2, []uuid.UUID(nil)).Return(availableUsers, nil)
 
 		result, err := a.GetPossibleEventSlot(t.Context(), eventID)
 		require.NoError(t, err)
-		require.NotNil(t, result)
-		assert.True(t, result.Slot.StartTime.Unix() == eventData.Slots[0].StartTime.Unix() && result.Slot.EndTime.Unix() == eventData.Slots[0].EndTime.Unix())
-		assert.Equal(t, 0, len(result.Users))
-		assert.Equal(t, 3, len(result.NotWorkingUsers)) // All users not working
+		require.Nil(t, result) // No slot has anyone available
 
 		require.NoError(t, dbMock.ExpectationsWereMet())
 		userAccessor.AssertExpectations(t)
@@ -361,9 +1001,9 @@ func TestGetPossibleEventSlot(t *testing.T) {
 		}
 		slotsJSON, _ := event.SlotsColumn(eventData.Slots).Value()
 
-		selectQuery := `SELECT id, title, duration_hours, user_id, slots, created_at FROM events WHERE id = $1`
-		rows := sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "created_at"}).
-			AddRow(eventData.ID, eventData.Title, eventData.DurationHours, eventData.UserID, slotsJSON, now)
+		selectQuery := `SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1`
+		rows := sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}).
+			AddRow(eventData.ID, eventData.Title, eventData.DurationHours, eventData.UserID, slotsJSON, "UTC", now, "public")
 
 		dbMock.ExpectQuery(regexp.QuoteMeta(selectQuery)).
 			WithArgs(eventID).
@@ -396,18 +1036,19 @@ func TestGetPossibleEventSlot(t *testing.T) {
 		slotsJSON, _ := event.SlotsColumn(eventData.Slots).Value()
 		allUsers := []user.User{user1, user2}
 
-		selectQuery := `SELECT id, title, duration_hours, user_id, slots, created_at FROM events WHERE id = $1`
-		rows := sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "created_at"}).
-			AddRow(eventData.ID, eventData.Title, eventData.DurationHours, eventData.UserID, slotsJSON, now)
+		selectQuery := `SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1`
+		rows := sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}).
+			AddRow(eventData.ID, eventData.Title, eventData.DurationHours, eventData.UserID, slotsJSON, "UTC", now, "public")
 
 		dbMock.ExpectQuery(regexp.QuoteMeta(selectQuery)).
 			WithArgs(eventID).
 			WillReturnRows(rows)
+		expectNoConflicts(dbMock, organizerID, 1)
 
 		userAccessor.On("GetUsers", testifymock.Anything).Return(allUsers, nil)
 		userAccessor.On("GetUsersForSlot", testifymock.Anything, testifymock.MatchedBy(func(s user.Slot) bool {
 			return s.StartTime.Unix() == startTime1.Unix() && s.EndTime.Unix() == endTime1.Unix()
-		}), 2).Return([]user.User{}, sql.ErrConnDone)
+		}), 2, []uuid.UUID(nil)).Return([]user.User{}, sql.ErrConnDone)
 
 		result, err := a.GetPossibleEventSlot(t.Context(), eventID)
 		require.Error(t, err)
@@ -417,4 +1058,1096 @@ func TestGetPossibleEventSlot(t *testing.T) {
 		require.NoError(t, dbMock.ExpectationsWereMet())
 		userAccessor.AssertExpectations(t)
 	})
+
+	t.Run("short-circuits against the invitee set", func(t *testing.T) {
+		userAccessor.ExpectedCalls = nil
+		userAccessor.Calls = nil
+
+		eventData := event.Event{
+			ID:            eventID,
+			Title:         "Test Event",
+			DurationHours: 2,
+			UserID:        organizerID,
+			Slots: []event.Slot{
+				{StartTime: startTime1, EndTime: endTime1},
+				{StartTime: startTime2, EndTime: endTime2},
+			},
+		}
+		invitees := []user.User{user1, user2}
+		slotsJSON, _ := event.SlotsColumn(eventData.Slots).Value()
+
+		selectQuery := `SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1`
+		rows := sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}).
+			AddRow(eventData.ID, eventData.Title, eventData.DurationHours, eventData.UserID, slotsJSON, "UTC", now, "public")
+
+		dbMock.ExpectQuery(regexp.QuoteMeta(selectQuery)).
+			WithArgs(eventID).
+			WillReturnRows(rows)
+		expectNoConflicts(dbMock, organizerID, 2)
+
+		// Not every user is free for the first slot, but every invitee is, so the second
+		// slot must never be queried.
+		userAccessor.On("GetUsersForSlot", testifymock.Anything, testifymock.MatchedBy(func(s user.Slot) bool {
+			return s.StartTime.Unix() == startTime1.Unix() && s.EndTime.Unix() == endTime1.Unix()
+		}), 2, []uuid.UUID{user1.ID, user2.ID}).Return([]user.User{user1, user2}, nil)
+
+		result, err := a.GetPossibleEventSlotForUsers(t.Context(), eventID, invitees)
+		require.NoError(t, err)
+		require.NotNil(t, result)
+		assert.Equal(t, startTime1.Unix(), result.Slot.StartTime.Unix())
+		assert.Equal(t, invitees, result.Users)
+		assert.Empty(t, result.NotWorkingUsers)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		userAccessor.AssertExpectations(t)
+		userAccessor.AssertNotCalled(t, "GetUsers")
+		userAccessor.AssertNumberOfCalls(t, "GetUsersForSlot", 1)
+	})
+
+	t.Run("ignores available users outside the invitee set", func(t *testing.T) {
+		userAccessor.ExpectedCalls = nil
+		userAccessor.Calls = nil
+
+		eventData := event.Event{
+			ID:            eventID,
+			Title:         "Test Event",
+			DurationHours: 2,
+			UserID:        organizerID,
+			Slots: []event.Slot{
+				{StartTime: startTime1, EndTime: endTime1},
+			},
+		}
+		slotsJSON, _ := event.SlotsColumn(eventData.Slots).Value()
+
+		selectQuery := `SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1`
+		rows := sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}).
+			AddRow(eventData.ID, eventData.Title, eventData.DurationHours, eventData.UserID, slotsJSON, "UTC", now, "public")
+
+		dbMock.ExpectQuery(regexp.QuoteMeta(selectQuery)).
+			WithArgs(eventID).
+			WillReturnRows(rows)
+		expectNoConflicts(dbMock, organizerID, 1)
+
+		userAccessor.On("GetUsersForSlot", testifymock.Anything, testifymock.Anything, 2, []uuid.UUID{user1.ID, user2.ID}).
+			Return([]user.User{user1, user3}, nil)
+
+		result, err := a.GetPossibleEventSlotForUsers(t.Context(), eventID, []user.User{user1, user2})
+		require.NoError(t, err)
+		require.NotNil(t, result)
+		assert.Equal(t, []user.User{user1}, result.Users)
+		assert.Equal(t, []user.User{user2}, result.NotWorkingUsers)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		userAccessor.AssertExpectations(t)
+	})
+
+	t.Run("overlap mode queries overlapping availability", func(t *testing.T) {
+		userAccessor.ExpectedCalls = nil
+		userAccessor.Calls = nil
+
+		slotsJSON, _ := event.SlotsColumn([]event.Slot{{StartTime: startTime1, EndTime: endTime1}}).Value()
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1`)).
+			WithArgs(eventID).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}).
+				AddRow(eventID, "Test Event", 2, organizerID, slotsJSON, "UTC", now, "public"))
+		expectNoConflicts(dbMock, organizerID, 1)
+
+		userAccessor.On("GetUsers", testifymock.Anything).Return([]user.User{user1, user2}, nil)
+		userAccessor.On("GetUsersForSlotOverlap", testifymock.Anything, testifymock.Anything, 2, []uuid.UUID(nil)).Return([]user.User{user1}, nil)
+
+		result, err := a.WithOverlap().GetPossibleEventSlot(t.Context(), eventID)
+		require.NoError(t, err)
+		require.NotNil(t, result)
+		assert.Equal(t, []user.User{user1}, result.Users)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		userAccessor.AssertExpectations(t)
+		userAccessor.AssertNotCalled(t, "GetUsersForSlot")
+	})
+
+	t.Run("demotes slots that clash with the organizer's other events", func(t *testing.T) {
+		userAccessor.ExpectedCalls = nil
+		userAccessor.Calls = nil
+
+		eventData := event.Event{
+			ID:            eventID,
+			Title:         "Test Event",
+			DurationHours: 2,
+			UserID:        organizerID,
+			Slots: []event.Slot{
+				{StartTime: startTime1, EndTime: endTime1},
+				{StartTime: startTime2, EndTime: endTime2},
+			},
+		}
+		slotsJSON, _ := event.SlotsColumn(eventData.Slots).Value()
+
+		selectQuery := `SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1`
+		rows := sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}).
+			AddRow(eventData.ID, eventData.Title, eventData.DurationHours, eventData.UserID, slotsJSON, "UTC", now, "public")
+
+		dbMock.ExpectQuery(regexp.QuoteMeta(selectQuery)).
+			WithArgs(eventID).
+			WillReturnRows(rows)
+
+		// The organizer already has another event during the first slot; the event
+		// itself is also returned and must not count as a conflict.
+		otherSlotsJSON, _ := event.SlotsColumn([]event.Slot{{StartTime: startTime1, EndTime: endTime1}}).Value()
+		dbMock.ExpectQuery(regexp.QuoteMeta(conflictsQuery)).
+			WithArgs(organizerID, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}).
+				AddRow(eventID, eventData.Title, 2, organizerID, slotsJSON, "UTC", now, "public").
+				AddRow(uuid.New(), "Other Event", 2, organizerID, otherSlotsJSON, "UTC", now, "public"))
+		dbMock.ExpectQuery(regexp.QuoteMeta(conflictsQuery)).
+			WithArgs(organizerID, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}).
+				AddRow(eventID, eventData.Title, 2, organizerID, slotsJSON, "UTC", now, "public"))
+
+		// The second slot wins even though more users are free for the first one.
+		userAccessor.On("GetUsers", testifymock.Anything).Return([]user.User{user1, user2, user3}, nil)
+		userAccessor.On("GetUsersForSlot", testifymock.Anything, testifymock.MatchedBy(func(s user.Slot) bool {
+			return s.StartTime.Unix() == startTime2.Unix()
+		}), 2, []uuid.UUID(nil)).Return([]user.User{user1}, nil)
+
+		result, err := a.GetPossibleEventSlot(t.Context(), eventID)
+		require.NoError(t, err)
+		require.NotNil(t, result)
+		assert.Equal(t, startTime2.Unix(), result.Slot.StartTime.Unix())
+		assert.Equal(t, []user.User{user1}, result.Users)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		userAccessor.AssertExpectations(t)
+		userAccessor.AssertNumberOfCalls(t, "GetUsersForSlot", 1)
+	})
+}
+
+func TestErrorContext(t *testing.T) {
+	db, dbMock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	a := event.NewAccessor(db, new(MockUserAccessor))
+	eventID, userID := uuid.New(), uuid.New()
+
+	t.Run("names the operation and identifiers", func(t *testing.T) {
+		dbMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO event_attendees`)).
+			WillReturnError(sql.ErrConnDone)
+
+		err := a.SetRSVP(t.Context(), eventID, userID, event.RSVPYes)
+		require.ErrorIs(t, err, sql.ErrConnDone)
+		assert.ErrorContains(t, err, "event.set_rsvp "+eventID.String()+" "+userID.String()+": ")
+		require.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("keeps not found detectable", func(t *testing.T) {
+		dbMock.ExpectQuery(regexp.QuoteMeta(`FROM events WHERE id = $1`)).
+			WithArgs(eventID).
+			WillReturnError(sql.ErrNoRows)
+
+		_, err := a.GetEvent(t.Context(), eventID)
+		require.ErrorIs(t, err, event.ErrNotFound)
+		assert.ErrorContains(t, err, "event.get_event "+eventID.String())
+		require.NoError(t, dbMock.ExpectationsWereMet())
+	})
+}
+
+func TestCancelledContext(t *testing.T) {
+	db, dbMock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	userAccessor := new(MockUserAccessor)
+	a := event.NewAccessor(db, userAccessor)
+	eventID := uuid.New()
+
+	t.Run("get event", func(t *testing.T) {
+		_, err := a.GetEvent(database.WithCancelledContext(t.Context()), eventID)
+		require.ErrorIs(t, err, context.Canceled)
+		require.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("possible slot stops between slots", func(t *testing.T) {
+		organizerID := uuid.New()
+		start := time.Now().Add(24 * time.Hour)
+		slots := []event.Slot{
+			{StartTime: start, EndTime: start.Add(time.Hour)},
+			{StartTime: start.Add(24 * time.Hour), EndTime: start.Add(25 * time.Hour)},
+		}
+		slotsJSON, _ := event.SlotsColumn(slots).Value()
+		dbMock.ExpectQuery(regexp.QuoteMeta(`FROM events WHERE id = $1`)).
+			WithArgs(eventID).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}).
+				AddRow(eventID, "Test Event", 1, organizerID, slotsJSON, "UTC", time.Now(), "public"))
+		expectNoConflicts(dbMock, organizerID, 2)
+
+		// The mock ignores ctx, so only the accessor's own check stops the second lookup.
+		ctx, cancel := context.WithCancel(t.Context())
+		userAccessor.On("GetUsers", testifymock.Anything).Return([]user.User{{ID: uuid.New(), Name: "User 1"}}, nil)
+		userAccessor.On("GetUsersForSlot", testifymock.Anything, testifymock.Anything, 1, []uuid.UUID(nil)).
+			Run(func(testifymock.Arguments) { cancel() }).
+			Return([]user.User{}, nil)
+
+		_, err := a.GetPossibleEventSlot(ctx, eventID)
+		require.ErrorIs(t, err, context.Canceled)
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		userAccessor.AssertNumberOfCalls(t, "GetUsersForSlot", 1)
+	})
+}
+
+func TestTransientErrors(t *testing.T) {
+	db, dbMock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	a := event.NewAccessor(db, new(MockUserAccessor)).WithRetry(database.Retry{Attempts: 2})
+	eventID := uuid.New()
+
+	t.Run("get event retried once", func(t *testing.T) {
+		query := regexp.QuoteMeta(`FROM events WHERE id = $1 AND deleted_at IS NULL`)
+		dbMock.ExpectQuery(query).WithArgs(eventID).WillReturnError(&pq.Error{Code: "40P01"})
+		dbMock.ExpectQuery(query).WithArgs(eventID).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}).
+				AddRow(eventID, "Test Event", 1, uuid.New(), []byte(`[]`), "UTC", time.Now(), "public"))
+
+		evt, err := a.GetEvent(t.Context(), eventID)
+		require.NoError(t, err)
+		assert.Equal(t, "Test Event", evt.Title)
+		require.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("not found is not retried", func(t *testing.T) {
+		dbMock.ExpectQuery(regexp.QuoteMeta(`FROM events WHERE id = $1 AND deleted_at IS NULL`)).
+			WithArgs(eventID).
+			WillReturnError(sql.ErrNoRows)
+
+		_, err := a.GetEvent(t.Context(), eventID)
+		require.ErrorIs(t, err, event.ErrNotFound)
+		require.NoError(t, dbMock.ExpectationsWereMet())
+	})
+}
+
+func TestGetUserEventConflicts(t *testing.T) {
+	db, dbMock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	a := event.NewAccessor(db, new(MockUserAccessor))
+
+	userID := uuid.New()
+	start := time.Date(2030, 1, 1, 10, 0, 0, 0, time.UTC)
+	slot := event.Slot{StartTime: start, EndTime: start.Add(2 * time.Hour)}
+
+	t.Run("overlapping event", func(t *testing.T) {
+		otherID := uuid.New()
+		slotsJSON, _ := event.SlotsColumn([]event.Slot{{StartTime: start.Add(time.Hour), EndTime: start.Add(3 * time.Hour)}}).Value()
+		dbMock.ExpectQuery(regexp.QuoteMeta(conflictsQuery)).
+			WithArgs(userID, slot.StartTime, slot.EndTime).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}).
+				AddRow(otherID, "Standup", 2, userID, slotsJSON, "UTC", start, "public"))
+
+		conflicts, err := a.GetUserEventConflicts(t.Context(), userID, slot)
+		require.NoError(t, err)
+		require.Len(t, conflicts, 1)
+		assert.Equal(t, otherID, conflicts[0].ID)
+		assert.Len(t, conflicts[0].Slots, 1)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("no overlapping event", func(t *testing.T) {
+		dbMock.ExpectQuery(regexp.QuoteMeta(conflictsQuery)).
+			WithArgs(userID, slot.StartTime, slot.EndTime).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}))
+
+		conflicts, err := a.GetUserEventConflicts(t.Context(), userID, slot)
+		require.NoError(t, err)
+		assert.Empty(t, conflicts)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+	})
+}
+
+func TestGetEligibleEvents(t *testing.T) {
+	db, dbMock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	a := event.NewAccessor(db, new(MockUserAccessor))
+
+	userID := uuid.New()
+	now := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	columns := []string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "id", "name", "email", "eligible_slots"}
+	eligibleQuery := regexp.QuoteMeta(`FROM events
+	JOIN users ON users.id = events.user_id
+	CROSS JOIN LATERAL jsonb_array_elements(events.slots) AS slot(value)
+	WHERE events.deleted_at IS NULL
+		AND (events.visibility = 'public' OR events.user_id = $1)`)
+
+	t.Run("user fits some events", func(t *testing.T) {
+		eventID, organizerID := uuid.New(), uuid.New()
+		fits := event.Slot{StartTime: now.Add(24 * time.Hour), EndTime: now.Add(26 * time.Hour)}
+		misses := event.Slot{StartTime: now.Add(48 * time.Hour), EndTime: now.Add(50 * time.Hour)}
+		slotsJSON, _ := event.SlotsColumn([]event.Slot{misses, fits}).Value()
+		eligibleJSON, _ := event.SlotsColumn([]event.Slot{fits}).Value()
+		dbMock.ExpectQuery(eligibleQuery).
+			WithArgs(userID, now).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(eventID, "Standup", 2, organizerID, slotsJSON, "UTC", now, organizerID, "Bob", "bob@example.com", eligibleJSON))
+
+		events, err := a.GetEligibleEvents(t.Context(), userID, now)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, eventID, events[0].ID)
+		assert.Equal(t, "Bob", events[0].Organizer.Name)
+		assert.Len(t, events[0].Slots, 2)
+		assert.Equal(t, []event.Slot{fits}, events[0].EligibleSlots)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("user fits no events", func(t *testing.T) {
+		dbMock.ExpectQuery(eligibleQuery).
+			WithArgs(userID, now).
+			WillReturnRows(sqlmock.NewRows(columns))
+
+		events, err := a.GetEligibleEvents(t.Context(), userID, now)
+		require.NoError(t, err)
+		assert.Empty(t, events)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+	})
+}
+
+func TestSearchEvents(t *testing.T) {
+	db, dbMock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	a := event.NewAccessor(db, new(MockUserAccessor))
+
+	const searchQuery = `SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events
+	WHERE deleted_at IS NULL AND title ILIKE '%' || $1 || '%' ESCAPE '\' AND events.visibility = 'public'
+	ORDER BY created_at DESC, id
+	LIMIT $2 OFFSET $3`
+	columns := []string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}
+	slotsJSON, _ := event.SlotsColumn([]event.Slot{}).Value()
+
+	t.Run("matching query", func(t *testing.T) {
+		eventID := uuid.New()
+		dbMock.ExpectQuery(regexp.QuoteMeta(searchQuery)).
+			WithArgs("stand", 20, 0).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(eventID, "Daily Standup", 1, uuid.New(), slotsJSON, "UTC", time.Now(), "public"))
+
+		events, err := a.SearchEvents(t.Context(), "stand", 20, 0)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, eventID, events[0].ID)
+		assert.Equal(t, "Daily Standup", events[0].Title)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("non-matching query", func(t *testing.T) {
+		dbMock.ExpectQuery(regexp.QuoteMeta(searchQuery)).
+			WithArgs("retro", 20, 0).
+			WillReturnRows(sqlmock.NewRows(columns))
+
+		events, err := a.SearchEvents(t.Context(), "retro", 20, 0)
+		require.NoError(t, err)
+		assert.Empty(t, events)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("case-insensitive query is passed through unchanged", func(t *testing.T) {
+		dbMock.ExpectQuery(regexp.QuoteMeta(searchQuery)).
+			WithArgs("STANDUP", 5, 10).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(uuid.New(), "daily standup", 1, uuid.New(), slotsJSON, "UTC", time.Now(), "public"))
+
+		events, err := a.SearchEvents(t.Context(), "STANDUP", 5, 10)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, "daily standup", events[0].Title)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("wildcards are matched literally", func(t *testing.T) {
+		dbMock.ExpectQuery(regexp.QuoteMeta(searchQuery)).
+			WithArgs(`100\%\_done\\`, 20, 0).
+			WillReturnRows(sqlmock.NewRows(columns))
+
+		_, err := a.SearchEvents(t.Context(), `100%_done\`, 20, 0)
+		require.NoError(t, err)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+	})
+}
+
+func TestGetEventsVisibility(t *testing.T) {
+	organizerID := uuid.New()
+	columns := []string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "visibility", "created_at", "id", "name", "email"}
+	slotsJSON, _ := event.SlotsColumn([]event.Slot{}).Value()
+	createdAt := time.Date(2030, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	t.Run("private events are left out by default", func(t *testing.T) {
+		db, dbMock, err := sqlmock.New()
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = db.Close() })
+		a := event.NewAccessor(db, new(MockUserAccessor))
+
+		publicID := uuid.New()
+		dbMock.ExpectQuery(regexp.QuoteMeta(`WHERE events.deleted_at IS NULL AND events.visibility = 'public'
+	ORDER BY`)).
+			WithArgs(20, 0).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(publicID, "Town hall", 1, organizerID, slotsJSON, "UTC", "public", createdAt, organizerID, "Alice", "alice@example.com"))
+
+		events, err := a.GetEventsWithOrganizers(t.Context(), 20, 0)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, publicID, events[0].ID)
+		assert.Equal(t, event.VisibilityPublic, events[0].Visibility)
+		require.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("an organizer's listing includes their private events", func(t *testing.T) {
+		db, dbMock, err := sqlmock.New()
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = db.Close() })
+		a := event.NewAccessor(db, new(MockUserAccessor)).WithOrganizer(organizerID)
+
+		publicID, privateID := uuid.New(), uuid.New()
+		dbMock.ExpectQuery(regexp.QuoteMeta(`WHERE events.deleted_at IS NULL AND events.user_id = $3
+	ORDER BY`)).
+			WithArgs(20, 0, organizerID).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(privateID, "1:1", 1, organizerID, slotsJSON, "UTC", "private", createdAt.Add(time.Hour), organizerID, "Alice", "alice@example.com").
+				AddRow(publicID, "Town hall", 1, organizerID, slotsJSON, "UTC", "public", createdAt, organizerID, "Alice", "alice@example.com"))
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM events WHERE deleted_at IS NULL AND events.user_id = $1`)).
+			WithArgs(organizerID).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+
+		events, err := a.GetEventsWithOrganizers(t.Context(), 20, 0)
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+		assert.Equal(t, privateID, events[0].ID)
+		assert.Equal(t, event.VisibilityPrivate, events[0].Visibility)
+		count, err := a.CountEvents(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+		require.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("with private events every event is counted", func(t *testing.T) {
+		db, dbMock, err := sqlmock.New()
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = db.Close() })
+		a := event.NewAccessor(db, new(MockUserAccessor)).WithPrivate()
+
+		dbMock.ExpectQuery(`^SELECT COUNT\(\*\) FROM events WHERE deleted_at IS NULL$`).
+			WithArgs().
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+		count, err := a.CountEvents(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, 3, count)
+		require.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("created events default to public", func(t *testing.T) {
+		db, dbMock, err := sqlmock.New()
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = db.Close() })
+		a := event.NewAccessor(db, new(MockUserAccessor))
+
+		slots := []event.Slot{{StartTime: createdAt.Add(24 * time.Hour), EndTime: createdAt.Add(26 * time.Hour)}}
+		for _, tc := range []struct {
+			visibility event.Visibility
+			stored     string
+		}{
+			{visibility: "", stored: "public"},
+			{visibility: event.VisibilityPrivate, stored: "private"},
+		} {
+			dbMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO events`)).
+				WithArgs(sqlmock.AnyArg(), "Planning", 2, organizerID, sqlmock.AnyArg(), "UTC", createdAt, tc.stored).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+
+			created, err := a.CreateEvent(t.Context(), event.Event{Title: "Planning", DurationHours: 2, UserID: organizerID, Slots: slots, Timezone: "UTC", Visibility: tc.visibility}, createdAt)
+			require.NoError(t, err)
+			assert.Equal(t, event.Visibility(tc.stored), created.Visibility)
+		}
+		require.NoError(t, dbMock.ExpectationsWereMet())
+	})
+}
+
+func TestGetEventsWithOrganizers(t *testing.T) {
+	db, dbMock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	a := event.NewAccessor(db, new(MockUserAccessor))
+
+	organizerID := uuid.New()
+	older := time.Date(2030, 1, 1, 9, 0, 0, 0, time.UTC)
+	newer := older.Add(time.Hour)
+	// Two events created at the same instant are told apart by ID.
+	first, second := uuid.MustParse("00000000-0000-0000-0000-000000000001"), uuid.MustParse("00000000-0000-0000-0000-000000000002")
+	oldest := uuid.New()
+	slotsJSON, _ := event.SlotsColumn([]event.Slot{}).Value()
+	// Newest first with an ID tie-break, the order SearchEvents uses.
+	dbMock.ExpectQuery(`FROM events\s+JOIN users ON users\.id = events\.user_id\s+WHERE events\.deleted_at IS NULL AND events\.visibility = 'public'\s+` +
+		`ORDER BY events\.created_at DESC, events\.id\s+LIMIT \$1 OFFSET \$2$`).
+		WithArgs(20, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "visibility", "created_at", "id", "name", "email"}).
+			AddRow(first, "First", 1, organizerID, slotsJSON, "UTC", "public", newer, organizerID, "Alice", "alice@example.com").
+			AddRow(second, "Second", 1, organizerID, slotsJSON, "UTC", "public", newer, organizerID, "Alice", "alice@example.com").
+			AddRow(oldest, "Oldest", 1, organizerID, slotsJSON, "UTC", "public", older, organizerID, "Alice", "alice@example.com"))
+
+	events, err := a.GetEventsWithOrganizers(t.Context(), 20, 0)
+	require.NoError(t, err)
+	ids := make([]uuid.UUID, len(events))
+	for i, e := range events {
+		ids[i] = e.ID
+	}
+	assert.Equal(t, []uuid.UUID{first, second, oldest}, ids)
+	assert.Equal(t, []event.Slot{}, events[0].Slots)
+	assert.Equal(t, user.User{ID: organizerID, Name: "Alice", Email: "alice@example.com"}, events[0].Organizer)
+
+	require.NoError(t, dbMock.ExpectationsWereMet())
+}
+
+func TestSlotJSON(t *testing.T) {
+	slot := event.Slot{
+		StartTime: time.Date(2030, 1, 2, 9, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2030, 1, 2, 11, 0, 0, 0, time.UTC),
+	}
+
+	t.Run("api form is epoch seconds", func(t *testing.T) {
+		b, err := json.Marshal(slot)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"start_time":1893574800,"end_time":1893582000}`, string(b))
+
+		var decoded event.Slot
+		require.NoError(t, json.Unmarshal(b, &decoded))
+		assert.Equal(t, slot, decoded)
+	})
+
+	t.Run("rejects RFC 3339 strings", func(t *testing.T) {
+		var decoded event.Slot
+		require.Error(t, json.Unmarshal([]byte(`{"start_time":"2030-01-02T09:00:00Z","end_time":"2030-01-02T11:00:00Z"}`), &decoded))
+	})
+
+	t.Run("column form stays RFC 3339", func(t *testing.T) {
+		v, err := event.SlotsColumn{slot}.Value()
+		require.NoError(t, err)
+		assert.JSONEq(t, `[{"start_time":"2030-01-02T09:00:00Z","end_time":"2030-01-02T11:00:00Z"}]`, string(v.([]byte)))
+
+		var scanned event.SlotsColumn
+		require.NoError(t, scanned.Scan(v))
+		assert.Equal(t, event.SlotsColumn{slot}, scanned)
+	})
+
+	t.Run("label round-trips in both forms", func(t *testing.T) {
+		labeled := slot
+		labeled.Label = "Morning option"
+
+		b, err := json.Marshal(labeled)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"start_time":1893574800,"end_time":1893582000,"label":"Morning option"}`, string(b))
+		var decoded event.Slot
+		require.NoError(t, json.Unmarshal(b, &decoded))
+		assert.Equal(t, labeled, decoded)
+
+		v, err := event.SlotsColumn{labeled, slot}.Value()
+		require.NoError(t, err)
+		assert.JSONEq(t, `[{"start_time":"2030-01-02T09:00:00Z","end_time":"2030-01-02T11:00:00Z","label":"Morning option"},`+
+			`{"start_time":"2030-01-02T09:00:00Z","end_time":"2030-01-02T11:00:00Z"}]`, string(v.([]byte)))
+		var scanned event.SlotsColumn
+		require.NoError(t, scanned.Scan(v))
+		assert.Equal(t, event.SlotsColumn{labeled, slot}, scanned)
+	})
+
+	t.Run("label is validated", func(t *testing.T) {
+		long := slot
+		long.Label = strings.Repeat("é", 101)
+		require.ErrorContains(t, long.Validate(), "label")
+		long.Label = strings.Repeat("é", 100)
+		require.NoError(t, long.Validate())
+	})
+}
+
+func TestAllDaySlot(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2030, 1, d, 0, 0, 0, 0, time.UTC) }
+
+	t.Run("decoding widens to whole days", func(t *testing.T) {
+		var decoded event.Slot
+		body := fmt.Sprintf(`{"start_time":%d,"end_time":%d,"all_day":true}`, day(2).Add(15*time.Hour).Unix(), day(3).Add(10*time.Hour).Unix())
+		require.NoError(t, json.Unmarshal([]byte(body), &decoded))
+		assert.Equal(t, event.Slot{StartTime: day(2), EndTime: day(4), AllDay: true}, decoded)
+		require.NoError(t, decoded.Validate())
+
+		b, err := json.Marshal(decoded)
+		require.NoError(t, err)
+		assert.JSONEq(t, fmt.Sprintf(`{"start_time":%d,"end_time":%d,"all_day":true}`, day(2).Unix(), day(4).Unix()), string(b))
+	})
+
+	t.Run("whole days are kept and a single instant is one day", func(t *testing.T) {
+		assert.Equal(t, event.Slot{StartTime: day(2), EndTime: day(3), AllDay: true},
+			event.Slot{StartTime: day(2), EndTime: day(3), AllDay: true}.WholeDays())
+		assert.Equal(t, event.Slot{StartTime: day(2), EndTime: day(3), AllDay: true},
+			event.Slot{StartTime: day(2), EndTime: day(2), AllDay: true}.WholeDays())
+		timed := event.Slot{StartTime: day(2).Add(9 * time.Hour), EndTime: day(2).Add(10 * time.Hour)}
+		assert.Equal(t, timed, timed.WholeDays())
+	})
+
+	t.Run("column keeps the flag", func(t *testing.T) {
+		slots := event.SlotsColumn{{StartTime: day(2), EndTime: day(3), AllDay: true}}
+		v, err := slots.Value()
+		require.NoError(t, err)
+		assert.JSONEq(t, `[{"start_time":"2030-01-02T00:00:00Z","end_time":"2030-01-03T00:00:00Z","all_day":true}]`, string(v.([]byte)))
+		var scanned event.SlotsColumn
+		require.NoError(t, scanned.Scan(v))
+		assert.Equal(t, slots, scanned)
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		misaligned := event.Slot{StartTime: day(2).Add(time.Hour), EndTime: day(3), AllDay: true}
+		var errs validation.Errors
+		require.ErrorAs(t, misaligned.Validate(), &errs)
+		assert.Equal(t, []validation.FieldError{{Field: "all_day", Message: "all-day slot must start and end at midnight UTC"}}, errs.FieldErrors())
+
+		// Whole days need no duration, but a timed slot still does.
+		e := event.Event{Title: "Offsite", UserID: uuid.New(), Slots: []event.Slot{{StartTime: day(2), EndTime: day(4), AllDay: true}}}
+		require.NoError(t, e.Validate())
+		assert.Empty(t, e.Warnings(day(1)))
+		e.Slots = append(e.Slots, event.Slot{StartTime: day(5).Add(9 * time.Hour), EndTime: day(5).Add(10 * time.Hour)})
+		require.ErrorContains(t, e.Validate(), "duration hours must be greater than 0")
+	})
+
+	t.Run("rounding leaves it alone", func(t *testing.T) {
+		slot := event.Slot{StartTime: day(2), EndTime: day(3), AllDay: true}
+		assert.Equal(t, slot, slot.Round(7*time.Hour))
+	})
+}
+
+func TestEventWindow(t *testing.T) {
+	start := time.Date(2030, 1, 2, 9, 0, 0, 0, time.UTC)
+	e := event.Event{Slots: []event.Slot{
+		{StartTime: start.Add(24 * time.Hour), EndTime: start.Add(25 * time.Hour)},
+		{StartTime: start, EndTime: start.Add(2 * time.Hour)},
+		{StartTime: start.Add(time.Hour), EndTime: start.Add(30 * time.Hour)},
+	}}
+
+	from, to := e.Window()
+	assert.Equal(t, start, from)
+	assert.Equal(t, start.Add(30*time.Hour), to)
+
+	from, to = (&event.Event{}).Window()
+	assert.True(t, from.IsZero())
+	assert.True(t, to.IsZero())
+}
+
+func TestEventWarnings(t *testing.T) {
+	now := time.Date(2030, 1, 2, 12, 0, 0, 0, time.UTC)
+	e := event.Event{DurationHours: 2, Slots: []event.Slot{
+		{StartTime: now.Add(-3 * time.Hour), EndTime: now.Add(-time.Hour)},
+		{StartTime: now.Add(time.Hour), EndTime: now.Add(3 * time.Hour)},
+		{StartTime: now.Add(4 * time.Hour), EndTime: now.Add(5 * time.Hour)},
+	}}
+
+	assert.Equal(t, []string{"slot 0 is in the past", "slot 2 is shorter than the event duration"}, e.Warnings(now))
+	assert.Empty(t, (&event.Event{DurationHours: 2, Slots: e.Slots[1:2]}).Warnings(now))
+}
+
+func TestSlotRound(t *testing.T) {
+	at := func(hour, minute int) time.Time { return time.Date(2030, 1, 2, hour, minute, 0, 0, time.UTC) }
+	slot := event.Slot{StartTime: at(9, 7), EndTime: at(10, 53), Label: "Morning"}
+
+	assert.Equal(t, event.Slot{StartTime: at(9, 15), EndTime: at(10, 45), Label: "Morning"}, slot.Round(15*time.Minute))
+	assert.Equal(t, event.Slot{StartTime: at(9, 30), EndTime: at(10, 30), Label: "Morning"}, slot.Round(30*time.Minute))
+	assert.Equal(t, event.Slot{StartTime: at(10, 0), EndTime: at(10, 0), Label: "Morning"}, slot.Round(time.Hour))
+	assert.Equal(t, slot, slot.Round(0))
+
+	aligned := event.Slot{StartTime: at(9, 0), EndTime: at(10, 0)}
+	assert.Equal(t, aligned, aligned.Round(15*time.Minute))
+}
+
+func TestFindPossibleSlot(t *testing.T) {
+	db, dbMock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	userAccessor := new(MockUserAccessor)
+	a := event.NewAccessor(db, userAccessor)
+
+	organizerID := uuid.New()
+	now := time.Now()
+	startTime1 := now.Add(24 * time.Hour)
+	endTime1 := startTime1.Add(2 * time.Hour)
+	startTime2 := now.Add(48 * time.Hour)
+	endTime2 := startTime2.Add(2 * time.Hour)
+
+	user1 := user.User{ID: uuid.New(), Name: "User 1", Email: "user1@example.com"}
+	user2 := user.User{ID: uuid.New(), Name: "User 2", Email: "user2@example.com"}
+	user3 := user.User{ID: uuid.New(), Name: "User 3", Email: "user3@example.com"}
+
+	slot1 := testifymock.MatchedBy(func(s user.Slot) bool { return s.StartTime.Unix() == startTime1.Unix() })
+	slot2 := testifymock.MatchedBy(func(s user.Slot) bool { return s.StartTime.Unix() == startTime2.Unix() })
+
+	t.Run("no slots", func(t *testing.T) {
+		userAccessor.ExpectedCalls = nil
+		userAccessor.Calls = nil
+
+		result, err := a.FindPossibleSlot(t.Context(), &event.Event{DurationHours: 2, UserID: organizerID}, nil)
+		require.NoError(t, err)
+		require.Nil(t, result)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		userAccessor.AssertNotCalled(t, "GetUsers")
+	})
+
+	t.Run("returns slot with most users without an organizer", func(t *testing.T) {
+		userAccessor.ExpectedCalls = nil
+		userAccessor.Calls = nil
+
+		evt := &event.Event{DurationHours: 2, Slots: []event.Slot{
+			{StartTime: startTime1, EndTime: endTime1},
+			{StartTime: startTime2, EndTime: endTime2},
+		}}
+
+		// Without an organizer there are no conflicts to look up.
+		userAccessor.On("GetUsers", testifymock.Anything).Return([]user.User{user1, user2, user3}, nil)
+		userAccessor.On("GetUsersForSlot", testifymock.Anything, slot1, 2, []uuid.UUID(nil)).Return([]user.User{user1}, nil)
+		userAccessor.On("GetUsersForSlot", testifymock.Anything, slot2, 2, []uuid.UUID(nil)).Return([]user.User{user1, user2}, nil)
+
+		result, err := a.FindPossibleSlot(t.Context(), evt, nil)
+		require.NoError(t, err)
+		require.NotNil(t, result)
+		assert.Equal(t, startTime2.Unix(), result.Slot.StartTime.Unix())
+		assert.Equal(t, []user.User{user1, user2}, result.Users)
+		assert.Equal(t, []user.User{user3}, result.NotWorkingUsers)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		userAccessor.AssertExpectations(t)
+	})
+
+	t.Run("demotes slots that clash with the organizer's events", func(t *testing.T) {
+		userAccessor.ExpectedCalls = nil
+		userAccessor.Calls = nil
+
+		evt := &event.Event{DurationHours: 2, UserID: organizerID, Slots: []event.Slot{
+			{StartTime: startTime1, EndTime: endTime1},
+			{StartTime: startTime2, EndTime: endTime2},
+		}}
+
+		// The event is not stored, so every event of the organizer found is a conflict.
+		otherSlotsJSON, _ := event.SlotsColumn(evt.Slots[:1]).Value()
+		dbMock.ExpectQuery(regexp.QuoteMeta(conflictsQuery)).
+			WithArgs(organizerID, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}).
+				AddRow(uuid.New(), "Other Event", 2, organizerID, otherSlotsJSON, "UTC", now, "public"))
+		expectNoConflicts(dbMock, organizerID, 1)
+
+		userAccessor.On("GetUsers", testifymock.Anything).Return([]user.User{user1, user2, user3}, nil)
+		userAccessor.On("GetUsersForSlot", testifymock.Anything, slot2, 2, []uuid.UUID(nil)).Return([]user.User{user1}, nil)
+
+		result, err := a.FindPossibleSlot(t.Context(), evt, nil)
+		require.NoError(t, err)
+		require.NotNil(t, result)
+		assert.Equal(t, startTime2.Unix(), result.Slot.StartTime.Unix())
+		assert.Equal(t, []user.User{user1}, result.Users)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		userAccessor.AssertExpectations(t)
+		userAccessor.AssertNumberOfCalls(t, "GetUsersForSlot", 1)
+	})
+
+	t.Run("short-circuits against the invitee set", func(t *testing.T) {
+		userAccessor.ExpectedCalls = nil
+		userAccessor.Calls = nil
+
+		evt := &event.Event{DurationHours: 2, UserID: organizerID, Slots: []event.Slot{
+			{StartTime: startTime1, EndTime: endTime1},
+			{StartTime: startTime2, EndTime: endTime2},
+		}}
+		invitees := []user.User{user1, user2}
+		expectNoConflicts(dbMock, organizerID, 2)
+
+		userAccessor.On("GetUsersForSlot", testifymock.Anything, slot1, 2, []uuid.UUID{user1.ID, user2.ID}).Return(invitees, nil)
+
+		result, err := a.FindPossibleSlot(t.Context(), evt, invitees)
+		require.NoError(t, err)
+		require.NotNil(t, result)
+		assert.Equal(t, startTime1.Unix(), result.Slot.StartTime.Unix())
+		assert.Equal(t, invitees, result.Users)
+		assert.Empty(t, result.NotWorkingUsers)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		userAccessor.AssertNotCalled(t, "GetUsers")
+		userAccessor.AssertNumberOfCalls(t, "GetUsersForSlot", 1)
+	})
+
+	t.Run("no users available for any slot", func(t *testing.T) {
+		userAccessor.ExpectedCalls = nil
+		userAccessor.Calls = nil
+
+		evt := &event.Event{DurationHours: 2, Slots: []event.Slot{{StartTime: startTime1, EndTime: endTime1}}}
+
+		userAccessor.On("GetUsers", testifymock.Anything).Return([]user.User{user1, user2}, nil)
+		userAccessor.On("GetUsersForSlot", testifymock.Anything, slot1, 2, []uuid.UUID(nil)).Return([]user.User{}, nil)
+
+		result, err := a.FindPossibleSlot(t.Context(), evt, nil)
+		require.NoError(t, err)
+		require.Nil(t, result)
+
+		userAccessor.AssertExpectations(t)
+	})
+}
+
+func TestGetPossibleEventSlots(t *testing.T) {
+	db, dbMock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	userAccessor := new(MockUserAccessor)
+	a := event.NewAccessor(db, userAccessor)
+
+	organizerID := uuid.New()
+	now := time.Now()
+	shared := event.Slot{StartTime: now.Add(24 * time.Hour), EndTime: now.Add(26 * time.Hour)}
+	other := event.Slot{StartTime: now.Add(48 * time.Hour), EndTime: now.Add(50 * time.Hour)}
+
+	user1 := user.User{ID: uuid.New(), Name: "User 1", Email: "user1@example.com"}
+	user2 := user.User{ID: uuid.New(), Name: "User 2", Email: "user2@example.com"}
+	user3 := user.User{ID: uuid.New(), Name: "User 3", Email: "user3@example.com"}
+
+	selectQuery := regexp.QuoteMeta(`SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1`)
+	expectEvent := func(id uuid.UUID, slots ...event.Slot) {
+		slotsJSON, _ := event.SlotsColumn(slots).Value()
+		dbMock.ExpectQuery(selectQuery).
+			WithArgs(id).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}).
+				AddRow(id, "Test Event", 2, organizerID, slotsJSON, "UTC", now, "public"))
+	}
+
+	t.Run("events share the user pool", func(t *testing.T) {
+		userAccessor.ExpectedCalls = nil
+		userAccessor.Calls = nil
+
+		planning, review, empty := uuid.New(), uuid.New(), uuid.New()
+		expectEvent(planning, shared, other)
+		expectNoConflicts(dbMock, organizerID, 2)
+		expectEvent(review, shared)
+		expectNoConflicts(dbMock, organizerID, 1)
+		expectEvent(empty)
+
+		// Users are fetched once, and the slot both events propose is looked up once.
+		userAccessor.On("GetUsers", testifymock.Anything).Return([]user.User{user1, user2, user3}, nil).Once()
+		userAccessor.On("GetUsersForSlot", testifymock.Anything, testifymock.MatchedBy(func(s user.Slot) bool {
+			return s.StartTime.Unix() == shared.StartTime.Unix()
+		}), 2, []uuid.UUID(nil)).Return([]user.User{user1, user2}, nil).Once()
+		userAccessor.On("GetUsersForSlot", testifymock.Anything, testifymock.MatchedBy(func(s user.Slot) bool {
+			return s.StartTime.Unix() == other.StartTime.Unix()
+		}), 2, []uuid.UUID(nil)).Return([]user.User{user1, user2, user3}, nil).Once()
+
+		results, err := a.GetPossibleEventSlots(t.Context(), []uuid.UUID{planning, review, empty})
+		require.NoError(t, err)
+		require.Len(t, results, 3)
+		require.NotNil(t, results[0])
+		assert.Equal(t, other.StartTime.Unix(), results[0].Slot.StartTime.Unix())
+		assert.Equal(t, []user.User{user1, user2, user3}, results[0].Users)
+		require.NotNil(t, results[1])
+		assert.Equal(t, shared.StartTime.Unix(), results[1].Slot.StartTime.Unix())
+		assert.Equal(t, []user.User{user1, user2}, results[1].Users)
+		assert.Equal(t, []user.User{user3}, results[1].NotWorkingUsers)
+		assert.Nil(t, results[2])
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		userAccessor.AssertExpectations(t)
+	})
+
+	t.Run("event not found", func(t *testing.T) {
+		userAccessor.ExpectedCalls = nil
+		userAccessor.Calls = nil
+
+		missing := uuid.New()
+		dbMock.ExpectQuery(selectQuery).WithArgs(missing).WillReturnError(sql.ErrNoRows)
+
+		results, err := a.GetPossibleEventSlots(t.Context(), []uuid.UUID{missing})
+		require.ErrorIs(t, err, event.ErrNotFound)
+		assert.Nil(t, results)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		userAccessor.AssertNotCalled(t, "GetUsers")
+	})
+}
+
+func TestPossibleSlotLock(t *testing.T) {
+	db, dbMock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	userAccessor := new(MockUserAccessor)
+	a := event.NewAccessor(db, userAccessor).WithPossibleSlotLock()
+
+	organizerID := uuid.New()
+	now := time.Now()
+	slot := event.Slot{StartTime: now.Add(24 * time.Hour), EndTime: now.Add(26 * time.Hour)}
+	alice := user.User{ID: uuid.New(), Name: "Alice", Email: "alice@example.com"}
+
+	lockQuery := regexp.QuoteMeta(`SELECT pg_advisory_xact_lock(hashtextextended('possible_slot:' || $1, 0))`)
+	selectQuery := regexp.QuoteMeta(`SELECT id, title, duration_hours, user_id, slots, timezone, created_at, visibility FROM events WHERE id = $1`)
+	expectEvent := func(id uuid.UUID, slots ...event.Slot) {
+		slotsJSON, _ := event.SlotsColumn(slots).Value()
+		dbMock.ExpectQuery(selectQuery).
+			WithArgs(id).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "duration_hours", "user_id", "slots", "timezone", "created_at", "visibility"}).
+				AddRow(id, "Test Event", 2, organizerID, slotsJSON, "UTC", now, "public"))
+	}
+
+	t.Run("held for the whole search", func(t *testing.T) {
+		userAccessor.ExpectedCalls = nil
+		userAccessor.Calls = nil
+
+		eventID := uuid.New()
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec(lockQuery).WithArgs(eventID.String()).WillReturnResult(sqlmock.NewResult(0, 1))
+		expectEvent(eventID, slot)
+		expectNoConflicts(dbMock, organizerID, 1)
+		userAccessor.On("GetUsers", testifymock.Anything).Return([]user.User{alice}, nil).Once()
+		userAccessor.On("GetUsersForSlot", testifymock.Anything, testifymock.Anything, 2, []uuid.UUID(nil)).Return([]user.User{alice}, nil).Once()
+		dbMock.ExpectRollback()
+
+		possible, err := a.GetPossibleEventSlot(t.Context(), eventID)
+		require.NoError(t, err)
+		require.NotNil(t, possible)
+		assert.Equal(t, []user.User{alice}, possible.Users)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+		userAccessor.AssertExpectations(t)
+	})
+
+	t.Run("batch locks every event in ID order", func(t *testing.T) {
+		low := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+		high := uuid.MustParse("ffffffff-ffff-ffff-ffff-ffffffffffff")
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec(lockQuery).WithArgs(low.String()).WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectExec(lockQuery).WithArgs(high.String()).WillReturnResult(sqlmock.NewResult(0, 1))
+		expectEvent(high)
+		expectEvent(low)
+		expectEvent(high)
+		dbMock.ExpectRollback()
+
+		results, err := a.GetPossibleEventSlots(t.Context(), []uuid.UUID{high, low, high})
+		require.NoError(t, err)
+		assert.Len(t, results, 3)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("failing to lock skips the search", func(t *testing.T) {
+		eventID := uuid.New()
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec(lockQuery).WithArgs(eventID.String()).WillReturnError(sql.ErrConnDone)
+		dbMock.ExpectRollback()
+
+		possible, err := a.GetPossibleEventSlot(t.Context(), eventID)
+		require.ErrorIs(t, err, sql.ErrConnDone)
+		assert.Nil(t, possible)
+
+		require.NoError(t, dbMock.ExpectationsWereMet())
+	})
+}
+
+func TestEventValidate(t *testing.T) {
+	now := time.Date(2030, 1, 2, 12, 0, 0, 0, time.UTC)
+	e := event.Event{Timezone: "Mars/Olympus_Mons", Visibility: "secret", Slots: []event.Slot{
+		{StartTime: now, EndTime: now.Add(time.Hour)},
+		{StartTime: now.Add(time.Hour), EndTime: now, Label: strings.Repeat("x", 101)},
+	}}
+
+	var errs validation.Errors
+	require.ErrorAs(t, e.Validate(), &errs)
+	assert.Equal(t, []validation.FieldError{
+		{Field: "title", Message: "title is required"},
+		{Field: "duration_hours", Message: "duration hours must be greater than 0"},
+		{Field: "organizer_id", Message: "organizer ID is required"},
+		{Field: "timezone", Message: `unknown timezone "Mars/Olympus_Mons"`},
+		{Field: "visibility", Message: `invalid visibility "secret", must be one of public, private`},
+		{Field: "slots[1].end_time", Message: "slot 1: start time is after end time"},
+		{Field: "slots[1].label", Message: "slot 1: label must be at most 100 characters"},
+	}, errs.FieldErrors())
+
+	// Only the duration and slots matter to a schedule.
+	require.ErrorAs(t, e.ValidateSchedule(), &errs)
+	assert.Equal(t, []validation.FieldError{
+		{Field: "duration_hours", Message: "duration hours must be greater than 0"},
+		{Field: "slots[1].end_time", Message: "slot 1: start time is after end time"},
+		{Field: "slots[1].label", Message: "slot 1: label must be at most 100 characters"},
+	}, errs.FieldErrors())
+
+	e = event.Event{Title: "Standup", DurationHours: 1, UserID: uuid.New(), Slots: e.Slots[:1]}
+	require.NoError(t, e.Validate())
+	require.NoError(t, (&event.Event{DurationHours: 1, Slots: e.Slots}).ValidateSchedule())
+}
+
+func TestSlotsColumnScan(t *testing.T) {
+	raw := `[{"start_time":"2030-01-02T09:00:00Z","end_time":"2030-01-02T11:00:00Z"}]`
+	want := event.SlotsColumn{{
+		StartTime: time.Date(2030, 1, 2, 9, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2030, 1, 2, 11, 0, 0, 0, time.UTC),
+	}}
+
+	for _, tc := range []struct {
+		name  string
+		value any
+		want  event.SlotsColumn
+	}{
+		{name: "bytes", value: []byte(raw), want: want},
+		{name: "string", value: raw, want: want},
+		{name: "nil", value: nil, want: nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			scanned := event.SlotsColumn{{}}
+			require.NoError(t, scanned.Scan(tc.value))
+			assert.Equal(t, tc.want, scanned)
+		})
+	}
+
+	t.Run("round trip is canonical", func(t *testing.T) {
+		berlin, err := time.LoadLocation("Europe/Berlin")
+		require.NoError(t, err)
+		written := event.SlotsColumn{{
+			StartTime: time.Date(2030, 7, 1, 9, 15, 37, 250_000_000, berlin),
+			EndTime:   time.Date(2030, 7, 1, 10, 15, 37, 0, berlin),
+			Label:     "Early",
+		}}
+		value, err := written.Value()
+		require.NoError(t, err)
+		assert.JSONEq(t, `[{"start_time":"2030-07-01T07:15:37Z","end_time":"2030-07-01T08:15:37Z","label":"Early"}]`, string(value.([]byte)))
+
+		// Rows written before slots were canonical may carry offsets and fractions.
+		for _, stored := range []any{value, `[{"start_time":"2030-07-01T09:15:37.25+02:00","end_time":"2030-07-01T10:15:37+02:00","label":"Early"}]`} {
+			var scanned event.SlotsColumn
+			require.NoError(t, scanned.Scan(stored))
+			assert.Equal(t, event.SlotsColumn{{
+				StartTime: time.Unix(written[0].StartTime.Unix(), 0).UTC(),
+				EndTime:   time.Unix(written[0].EndTime.Unix(), 0).UTC(),
+				Label:     "Early",
+			}}, scanned)
+
+			again, err := scanned.Value()
+			require.NoError(t, err)
+			assert.Equal(t, value, again)
+		}
+	})
+
+	t.Run("unsupported type", func(t *testing.T) {
+		var scanned event.SlotsColumn
+		assert.ErrorContains(t, scanned.Scan(42), "int")
+	})
+
+	t.Run("invalid json names the type", func(t *testing.T) {
+		var scanned event.SlotsColumn
+		assert.ErrorContains(t, scanned.Scan("not json"), "string")
+	})
 }
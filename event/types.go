@@ -5,82 +5,415 @@ import (
 	"encoding/json"
 	"errors"
 	"events-system/user"
+	"events-system/validation"
 	"fmt"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 )
 
+// ErrNotFound is returned by the accessor when the requested event does not exist.
+var ErrNotFound = errors.New("event not found")
+
+// ErrAlreadyExists is returned when an event is created with the ID of an existing one, including a
+// deleted event.
+var ErrAlreadyExists = errors.New("event already exists")
+
+// ErrOrganizerUnavailable is returned when an event must only be created if its organizer is available
+// for one of its slots, and they are available for none.
+var ErrOrganizerUnavailable = errors.New("organizer is not available for any slot")
+
 type SlotsColumn []Slot
 
+// storedSlot is how a slot is kept in the JSONB column: RFC 3339 strings, which the
+// queries cast with ::timestamptz. It deliberately differs from the API's epoch seconds.
+// Slots are stored and read back canonical, so a slot compares equal after a round trip.
+type storedSlot struct {
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+	Label     string    `json:"label,omitempty"`
+	AllDay    bool      `json:"all_day,omitempty"`
+}
+
 // Value implements driver.Valuer for INSERT/UPDATE.
 func (s SlotsColumn) Value() (driver.Value, error) {
-	if s == nil {
-		return []byte("[]"), nil
+	stored := make([]storedSlot, len(s))
+	for i, slot := range s {
+		stored[i] = storedSlot(slot.canonical())
 	}
-	return json.Marshal(s)
+	return json.Marshal(stored)
 }
 
-// Scan implements sql.Scanner for SELECT.
+// Scan implements sql.Scanner for SELECT. Some drivers hand JSONB back as a string rather than []byte.
 func (s *SlotsColumn) Scan(value any) error {
-	if value == nil {
+	var b []byte
+	switch v := value.(type) {
+	case nil:
 		*s = nil
 		return nil
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return fmt.Errorf("scan slots: unsupported type %T", value)
 	}
-	b, ok := value.([]byte)
-	if !ok {
-		return fmt.Errorf("not a []byte: %T", value)
+	var stored []storedSlot
+	if err := json.Unmarshal(b, &stored); err != nil {
+		return fmt.Errorf("scan slots from %T: %w", value, err)
 	}
-	return json.Unmarshal(b, s)
+	*s = make(SlotsColumn, len(stored))
+	for i, slot := range stored {
+		(*s)[i] = Slot(slot).canonical()
+	}
+	return nil
 }
 
 type Event struct {
-	ID            uuid.UUID `json:"id"`
-	Title         string    `json:"title"`
-	DurationHours int       `json:"duration_hours"`
-	UserID        uuid.UUID `json:"user_id"`
-	Slots         []Slot    `json:"slots"`
-	CreatedAt     time.Time `json:"created_at"`
+	ID            uuid.UUID  `json:"id"`
+	Title         string     `json:"title"`
+	DurationHours int        `json:"duration_hours"`
+	UserID        uuid.UUID  `json:"user_id"`
+	Slots         []Slot     `json:"slots"`
+	Timezone      string     `json:"timezone"`   // IANA name, slots are still stored in UTC
+	Visibility    Visibility `json:"visibility"` // empty means public
+	CreatedAt     time.Time  `json:"created_at"`
+	DeletedAt     *time.Time `json:"deleted_at,omitempty"`
+}
+
+// EventWithOrganizer is an event together with the user who organizes it.
+type EventWithOrganizer struct {
+	Event
+	Organizer user.User `json:"organizer"`
+}
+
+// EligibleEvent is an event that a user can attend, with the slots of it that fit their availability.
+type EligibleEvent struct {
+	EventWithOrganizer
+	EligibleSlots []Slot `json:"eligible_slots"`
 }
 
+// Cursor is the key of an event in the listing order, the position a keyset page continues from.
+type Cursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// Location returns the event's timezone, UTC when it is unset or unknown.
+func (e *Event) Location() *time.Location {
+	if e.Timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(e.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// Window returns the span from the earliest slot start to the latest slot end, zero times when
+// the event has no slots.
+func (e *Event) Window() (from, to time.Time) {
+	for i, slot := range e.Slots {
+		if i == 0 || slot.StartTime.Before(from) {
+			from = slot.StartTime
+		}
+		if i == 0 || slot.EndTime.After(to) {
+			to = slot.EndTime
+		}
+	}
+	return from, to
+}
+
+// Validate reports the problems that make the event unusable, every invalid field as validation.Errors,
+// named as in the API body. Problems that only deserve a mention are reported by Warnings instead.
 func (e *Event) Validate() error {
+	var errs validation.Errors
 	if e.Title == "" {
-		return errors.New("title is required")
-	}
-	if e.DurationHours <= 0 {
-		return errors.New("duration hours must be greater than 0")
+		errs.Add("title", "title is required")
 	}
+	e.validateDuration(&errs)
 	if e.UserID == uuid.Nil {
-		return errors.New("user ID is required")
+		errs.Add("organizer_id", "organizer ID is required")
+	}
+	if e.Timezone != "" {
+		if _, err := time.LoadLocation(e.Timezone); err != nil {
+			errs.Add("timezone", fmt.Sprintf("unknown timezone %q", e.Timezone))
+		}
+	}
+	if err := e.Visibility.Validate(); err != nil {
+		errs.Add("visibility", err.Error())
+	}
+	e.validateSlots(&errs)
+	return errs.Err()
+}
+
+// ValidateSchedule checks only the duration and slots of the event, which is all a possible-slot
+// search over an event that is never stored needs.
+func (e *Event) ValidateSchedule() error {
+	var errs validation.Errors
+	e.validateDuration(&errs)
+	e.validateSlots(&errs)
+	return errs.Err()
+}
+
+func (e *Event) validateDuration(errs *validation.Errors) {
+	// An event held on whole days needs no duration, the days are the event.
+	if e.DurationHours < 0 || e.DurationHours == 0 && !e.allDay() {
+		errs.Add("duration_hours", "duration hours must be greater than 0")
 	}
+}
+
+func (e *Event) validateSlots(errs *validation.Errors) {
+	for i, slot := range e.Slots {
+		if err := slot.validate(); len(err) > 0 {
+			errs.Nest(fmt.Sprintf("slots[%d]", i), fmt.Sprintf("slot %d", i), err)
+		}
+	}
+}
+
+// allDay reports whether the event has slots and all of them are all-day.
+func (e *Event) allDay() bool {
 	for _, slot := range e.Slots {
-		if err := slot.Validate(); err != nil {
-			return fmt.Errorf("invalid slot - %v: %w", slot, err)
+		if !slot.AllDay {
+			return false
 		}
 	}
-	return nil
+	return len(e.Slots) > 0
 }
 
+// Warnings lists the non-fatal problems of a valid event at now: slots that have already ended,
+// and slots too short to hold the event's duration.
+func (e *Event) Warnings(now time.Time) []string {
+	var warnings []string
+	duration := time.Duration(e.DurationHours) * time.Hour
+	for i, slot := range e.Slots {
+		if !slot.EndTime.After(now) {
+			warnings = append(warnings, fmt.Sprintf("slot %d is in the past", i))
+		}
+		if !slot.AllDay && slot.EndTime.Sub(slot.StartTime) < duration {
+			warnings = append(warnings, fmt.Sprintf("slot %d is shorter than the event duration", i))
+		}
+	}
+	return warnings
+}
+
+// maxSlotLabelLength bounds a slot label, in characters.
+const maxSlotLabelLength = 100
+
+// Slot is a time range. In JSON its bounds are unix seconds, see MarshalJSON.
+//
+// An all-day slot spans whole UTC days: it starts at midnight and ends at the midnight after its
+// last day.
 type Slot struct {
 	StartTime time.Time `json:"start_time"`
 	EndTime   time.Time `json:"end_time"`
+	Label     string    `json:"label,omitempty"` // optional, e.g. "Morning option"
+	AllDay    bool      `json:"all_day,omitempty"`
+}
+
+// epochSlot is the JSON form of a Slot.
+type epochSlot struct {
+	StartTime int64  `json:"start_time"`
+	EndTime   int64  `json:"end_time"`
+	Label     string `json:"label,omitempty"`
+	AllDay    bool   `json:"all_day,omitempty"`
+}
+
+// MarshalJSON writes the slot as {"start_time": <unix>, "end_time": <unix>}, plus "label" when it
+// has one and "all_day" when it is one.
+func (s Slot) MarshalJSON() ([]byte, error) {
+	return json.Marshal(epochSlot{StartTime: s.StartTime.Unix(), EndTime: s.EndTime.Unix(), Label: s.Label, AllDay: s.AllDay})
+}
+
+// UnmarshalJSON reads unix seconds into UTC times. The label is optional. An all-day slot is widened
+// to the whole days its bounds fall on, see WholeDays, so clients may send any time of those days.
+func (s *Slot) UnmarshalJSON(b []byte) error {
+	var e epochSlot
+	if err := json.Unmarshal(b, &e); err != nil {
+		return err
+	}
+	s.StartTime = time.Unix(e.StartTime, 0).UTC()
+	s.EndTime = time.Unix(e.EndTime, 0).UTC()
+	s.Label = e.Label
+	s.AllDay = e.AllDay
+	*s = s.WholeDays()
+	return nil
+}
+
+// canonical returns the slot in UTC and truncated to whole seconds, the precision of the API, so
+// that equal instants are also equal times whatever location or fraction they were given in.
+func (s Slot) canonical() Slot {
+	s.StartTime = s.StartTime.UTC().Truncate(time.Second)
+	s.EndTime = s.EndTime.UTC().Truncate(time.Second)
+	return s
+}
+
+// overlaps reports whether s and other share some time, slots that merely touch do not overlap.
+func (s Slot) overlaps(other Slot) bool {
+	return s.StartTime.Before(other.EndTime) && other.StartTime.Before(s.EndTime)
+}
+
+// WholeDays widens an all-day slot to the UTC days it touches, at least one: the start moves back
+// to midnight and the end forward to the next midnight, unless it already is one. Other slots are
+// returned unchanged.
+func (s Slot) WholeDays() Slot {
+	if !s.AllDay || s.StartTime.After(s.EndTime) {
+		return s
+	}
+	const day = 24 * time.Hour
+	s.StartTime = s.StartTime.UTC().Truncate(day)
+	end := s.EndTime.UTC().Truncate(day)
+	if end.Before(s.EndTime) || !end.After(s.StartTime) {
+		end = end.Add(day)
+	}
+	s.EndTime = end
+	return s
+}
+
+// Round shrinks the slot to whole multiples of granularity, rounding the start up and the end down
+// so that it never covers time it did not before, e.g. 09:07-10:53 becomes 09:15-10:45 at 15
+// minutes. Multiples are counted in UTC. A non-positive granularity leaves the slot unchanged, as
+// it does an all-day one.
+func (s Slot) Round(granularity time.Duration) Slot {
+	if granularity <= 0 || s.AllDay {
+		return s
+	}
+	start := s.StartTime.Truncate(granularity)
+	if start.Before(s.StartTime) {
+		start = start.Add(granularity)
+	}
+	s.StartTime = start
+	s.EndTime = s.EndTime.Truncate(granularity)
+	return s
 }
 
+// Validate reports every invalid field of the slot as validation.Errors.
 func (s *Slot) Validate() error {
+	return s.validate().Err()
+}
+
+func (s *Slot) validate() validation.Errors {
+	var errs validation.Errors
 	if s.StartTime.IsZero() {
-		return errors.New("start time is required")
+		errs.Add("start_time", "start time is required")
 	}
 	if s.EndTime.IsZero() {
-		return errors.New("end time is required")
+		errs.Add("end_time", "end time is required")
+	} else if s.StartTime.After(s.EndTime) {
+		errs.Add("end_time", "start time is after end time")
 	}
-	if s.StartTime.After(s.EndTime) {
-		return errors.New("start time is after end time")
+	if utf8.RuneCountInString(s.Label) > maxSlotLabelLength {
+		errs.Add("label", fmt.Sprintf("label must be at most %d characters", maxSlotLabelLength))
 	}
-	return nil
+	if s.AllDay && len(errs) == 0 {
+		if days := s.WholeDays(); !days.StartTime.Equal(s.StartTime) || !days.EndTime.Equal(s.EndTime) {
+			errs.Add("all_day", "all-day slot must start and end at midnight UTC")
+		}
+	}
+	return errs
+}
+
+// FutureSlots returns the slots that have not ended yet at now.
+func FutureSlots(slots []Slot, now time.Time) []Slot {
+	future := []Slot{}
+	for _, slot := range slots {
+		if slot.EndTime.After(now) {
+			future = append(future, slot)
+		}
+	}
+	return future
+}
+
+// distinctSlots drops repeated slots, keeping the first occurrence of each start/end pair in order.
+func distinctSlots(slots []Slot) []Slot {
+	type bounds struct{ start, end int64 }
+	seen := make(map[bounds]bool, len(slots))
+	distinct := make([]Slot, 0, len(slots))
+	for _, slot := range slots {
+		key := bounds{start: slot.StartTime.UnixNano(), end: slot.EndTime.UnixNano()}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		distinct = append(distinct, slot)
+	}
+	return distinct
 }
 
 type PossibleEventSlot struct {
 	Slot            Slot        `json:"slot"`
 	Users           []user.User `json:"users,omitempty"`
 	NotWorkingUsers []user.User `json:"not_working_users,omitempty"`
+	// Window is the part of Slot all of Users are free for, only set by searches WithTrim.
+	Window *Slot `json:"window,omitempty"`
+}
+
+// SlotAvailability is how many users can and cannot attend one of an event's slots.
+type SlotAvailability struct {
+	Slot            Slot
+	AvailableCount  int
+	NotWorkingCount int
+}
+
+// BestSlotStats summarizes the best possible slot across all events.
+type BestSlotStats struct {
+	ViableEvents int
+	AvgAttendees float64
+}
+
+// Visibility decides who sees an event in listings. Private events are only listed for their
+// organizer, but anyone with the ID can still fetch them.
+type Visibility string
+
+const (
+	VisibilityPublic  Visibility = "public"
+	VisibilityPrivate Visibility = "private"
+)
+
+// Validate accepts the empty visibility, which is public.
+func (v Visibility) Validate() error {
+	switch v {
+	case "", VisibilityPublic, VisibilityPrivate:
+		return nil
+	}
+	return fmt.Errorf("invalid visibility %q, must be one of public, private", v)
+}
+
+// orDefault returns the visibility, public when it is unset.
+func (v Visibility) orDefault() Visibility {
+	if v == "" {
+		return VisibilityPublic
+	}
+	return v
+}
+
+// RSVPStatus is a user's answer to an event invitation.
+type RSVPStatus string
+
+const (
+	RSVPYes   RSVPStatus = "yes"
+	RSVPNo    RSVPStatus = "no"
+	RSVPMaybe RSVPStatus = "maybe"
+)
+
+func (s RSVPStatus) Validate() error {
+	switch s {
+	case RSVPYes, RSVPNo, RSVPMaybe:
+		return nil
+	}
+	return fmt.Errorf("invalid RSVP status %q, must be one of yes, no, maybe", s)
+}
+
+type Attendee struct {
+	User   user.User  `json:"user"`
+	Status RSVPStatus `json:"status"`
+}
+
+// RSVPConflict is an event a user said yes to whose chosen slot overlaps the one of another event
+// they answer, see GetRSVPConflicts.
+type RSVPConflict struct {
+	Event Event
+	Slot  Slot
 }
@@ -1,13 +1,86 @@
 package user
 
-import "database/sql"
+import (
+	"context"
+	"database/sql"
+	"events-system/database"
+)
 
 // Accessor is the DB layer entrypoint for user-related queries.
 type Accessor struct {
 	db *sql.DB
+	// mergeSlots makes CreateUserSlots merge new slots into the overlapping existing ones instead
+	// of rejecting them.
+	mergeSlots bool
+	// skipDuplicateSlots makes slot inserts leave out the slots the user already has instead of
+	// failing with a DuplicateSlotError.
+	skipDuplicateSlots bool
+	// retry applies to the reads that are safe to repeat, GetUser and GetUsers.
+	retry database.Retry
+	// includeInactive makes listings and availability lookups also return deactivated users.
+	includeInactive bool
+	// stmts, when set, serves the hot queries from prepared statements, currently GetUsersForSlot
+	// and GetUsersForSlotOverlap.
+	stmts *database.Statements
 }
 
 func NewAccessor(db *sql.DB) *Accessor {
-	return &Accessor{db: db}
+	return &Accessor{db: db, retry: database.DefaultRetry}
 }
 
+// WithRetry returns a copy of the accessor that retries reads with the given policy.
+func (a *Accessor) WithRetry(retry database.Retry) *Accessor {
+	c := *a
+	c.retry = retry
+	return &c
+}
+
+// WithStatements returns a copy of the accessor that runs its hot queries through stmts. A nil
+// stmts runs every query unprepared.
+func (a *Accessor) WithStatements(stmts *database.Statements) *Accessor {
+	c := *a
+	c.stmts = stmts
+	return &c
+}
+
+// queryContext runs query through the prepared statement cache when the accessor has one.
+func (a *Accessor) queryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	if a.stmts != nil {
+		return a.stmts.QueryContext(ctx, query, args...)
+	}
+	return a.db.QueryContext(ctx, query, args...)
+}
+
+// WithMergeSlots returns a copy of the accessor whose CreateUserSlots merges new slots with the
+// existing availability they overlap, rather than failing with a SlotConflictError.
+func (a *Accessor) WithMergeSlots() *Accessor {
+	c := *a
+	c.mergeSlots = true
+	return &c
+}
+
+// WithSkipDuplicateSlots returns a copy of the accessor whose CreateUserSlots and ReplaceUserSlots
+// silently leave out the slots the user already has, returning only those they inserted, rather than
+// failing with a DuplicateSlotError.
+func (a *Accessor) WithSkipDuplicateSlots() *Accessor {
+	c := *a
+	c.skipDuplicateSlots = true
+	return &c
+}
+
+// WithInactive returns a copy of the accessor whose listings and availability lookups also return
+// deactivated users, which are otherwise only reachable by ID.
+func (a *Accessor) WithInactive() *Accessor {
+	c := *a
+	c.includeInactive = true
+	return &c
+}
+
+// activeOnly returns the condition, prefixed with prefix, restricting a query on users to active
+// ones, or nothing when the accessor includes inactive users.
+func (a *Accessor) activeOnly(prefix string) string {
+	if a.includeInactive {
+		return ""
+	}
+	return prefix + "users.active"
+}
@@ -0,0 +1,55 @@
+package user
+
+import "time"
+
+// CalendarDay is one day of a WeekCalendar.
+type CalendarDay struct {
+	Date string `json:"date"`
+	// Hours holds one cell per UTC hour, true when any slot overlaps it.
+	Hours []bool `json:"hours"`
+}
+
+// WeekCalendar is availability as a fixed grid of seven days of hourly cells, for scheduling UIs.
+type WeekCalendar struct {
+	WeekStart string        `json:"week_start"`
+	Days      []CalendarDay `json:"days"`
+}
+
+// WeekStart returns the UTC midnight of the Monday starting the week that contains t.
+func WeekStart(t time.Time) time.Time {
+	day := truncateDay(t)
+	// Weekday counts from Sunday, shift it so Monday is 0.
+	return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+}
+
+// Calendar lays the slots out on the week starting at weekStart, a UTC midnight. A cell is marked
+// as soon as a slot overlaps part of it, so a slot from 09:30 to 11:15 marks 09, 10 and 11. Parts
+// of slots outside the week are ignored.
+func Calendar(slots []Slot, weekStart time.Time) WeekCalendar {
+	weekEnd := weekStart.AddDate(0, 0, 7)
+	calendar := WeekCalendar{WeekStart: weekStart.Format(time.DateOnly), Days: make([]CalendarDay, 7)}
+	for i := range calendar.Days {
+		calendar.Days[i] = CalendarDay{
+			Date:  weekStart.AddDate(0, 0, i).Format(time.DateOnly),
+			Hours: make([]bool, 24),
+		}
+	}
+
+	for _, s := range slots {
+		start, end := s.StartTime.UTC(), s.EndTime.UTC()
+		if !start.Before(weekEnd) || !end.After(weekStart) {
+			continue
+		}
+		if start.Before(weekStart) {
+			start = weekStart
+		}
+		if end.After(weekEnd) {
+			end = weekEnd
+		}
+		for cell := start.Truncate(time.Hour); cell.Before(end); cell = cell.Add(time.Hour) {
+			hour := int(cell.Sub(weekStart) / time.Hour)
+			calendar.Days[hour/24].Hours[hour%24] = true
+		}
+	}
+	return calendar
+}
@@ -0,0 +1,91 @@
+package user
+
+import (
+	"slices"
+	"time"
+)
+
+// CommonAvailability returns the windows within [from, to) during which every user is available
+// for at least minDuration, in order. Each element of slotsByUser holds one user's slots, which may
+// overlap each other or extend beyond the window.
+func CommonAvailability(slotsByUser [][]Slot, from, to time.Time, minDuration time.Duration) []Slot {
+	common := []Slot{{StartTime: from, EndTime: to}}
+	for _, slots := range slotsByUser {
+		common = intersect(common, merge(slots, from, to))
+	}
+
+	windows := []Slot{}
+	for _, s := range common {
+		if s.EndTime.Sub(s.StartTime) >= minDuration {
+			windows = append(windows, s)
+		}
+	}
+	return windows
+}
+
+// merge clips the slots to [from, to) and joins the ones that overlap or touch, in order.
+func merge(slots []Slot, from, to time.Time) []Slot {
+	sorted := slices.Clone(slots)
+	slices.SortFunc(sorted, func(a, b Slot) int { return a.StartTime.Compare(b.StartTime) })
+
+	var merged []Slot
+	for _, s := range sorted {
+		if s.StartTime.Before(from) {
+			s.StartTime = from
+		}
+		if s.EndTime.After(to) {
+			s.EndTime = to
+		}
+		if !s.EndTime.After(s.StartTime) {
+			continue
+		}
+		if n := len(merged); n > 0 && !s.StartTime.After(merged[n-1].EndTime) {
+			if s.EndTime.After(merged[n-1].EndTime) {
+				merged[n-1].EndTime = s.EndTime
+			}
+			continue
+		}
+		merged = append(merged, s)
+	}
+	return merged
+}
+
+// mergeAll joins the slots that overlap or touch, in order, over their whole span.
+func mergeAll(slots []Slot) []Slot {
+	if len(slots) == 0 {
+		return []Slot{}
+	}
+	from, to := slots[0].StartTime, slots[0].EndTime
+	for _, s := range slots[1:] {
+		if s.StartTime.Before(from) {
+			from = s.StartTime
+		}
+		if s.EndTime.After(to) {
+			to = s.EndTime
+		}
+	}
+	return merge(slots, from, to)
+}
+
+// intersect returns the overlaps of two ordered lists of disjoint slots.
+func intersect(a, b []Slot) []Slot {
+	var res []Slot
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		start, end := a[i].StartTime, a[i].EndTime
+		if b[j].StartTime.After(start) {
+			start = b[j].StartTime
+		}
+		if b[j].EndTime.Before(end) {
+			end = b[j].EndTime
+		}
+		if end.After(start) {
+			res = append(res, Slot{StartTime: start, EndTime: end})
+		}
+		if a[i].EndTime.Before(b[j].EndTime) {
+			i++
+		} else {
+			j++
+		}
+	}
+	return res
+}
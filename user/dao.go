@@ -4,34 +4,181 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"events-system/database"
 	"fmt"
 	"log"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
-func (a *Accessor) CreateUser(ctx context.Context, user User) (*User, error) {
+// CreateUser inserts the user, stamping created_at and updated_at with now. An ID or email already
+// taken by another user fails with ErrAlreadyExists.
+func (a *Accessor) CreateUser(ctx context.Context, user User, now time.Time) (_ *User, err error) {
+	defer database.ObserveQuery("user.create_user")()
+	defer database.WrapError(&err, "user.create_user")
 	if err := user.Validate(); err != nil {
 		return nil, fmt.Errorf("validate: %w", err)
 	}
 
-	id := uuid.New()
+	// Honour a client-supplied ID so creates can be made conditional on it.
+	id := user.ID
+	if id == uuid.Nil {
+		id = uuid.New()
+	}
 
-	query := `INSERT INTO users (id, name, email) VALUES ($1, $2, $3)`
-	if _, err := a.db.ExecContext(ctx, query, id, user.Name, user.Email); err != nil {
+	query := `INSERT INTO users (id, name, email, created_at, updated_at) VALUES ($1, $2, $3, $4, $5)`
+	if _, err := a.db.ExecContext(ctx, query, id, user.Name, user.Email, now, now); err != nil {
+		if database.IsUniqueViolation(err) {
+			return nil, ErrAlreadyExists
+		}
 		return nil, fmt.Errorf("exec context: %w", err)
 	}
 
 	return &User{
-		ID:    id,
-		Name:  user.Name,
-		Email: user.Email,
+		ID:        id,
+		Name:      user.Name,
+		Email:     user.Email,
+		CreatedAt: now,
+		UpdatedAt: now,
 	}, nil
 }
 
+// UpsertUser creates the user or, when one already has its email, renames that user and bumps its
+// updated_at to now. created reports which of the two happened. The ID of an existing user is kept
+// even if the payload carries another one, but an ID already taken by a user with another email
+// fails the call with ErrIDTaken.
+func (a *Accessor) UpsertUser(ctx context.Context, user User, now time.Time) (_ *User, created bool, err error) {
+	defer database.ObserveQuery("user.upsert_user")()
+	defer database.WrapError(&err, "user.upsert_user")
+	if err := user.Validate(); err != nil {
+		return nil, false, fmt.Errorf("validate: %w", err)
+	}
+
+	id := user.ID
+	if id == uuid.Nil {
+		id = uuid.New()
+	}
+
+	// xmax is only set on rows the statement updated, telling a fresh insert from an update.
+	query := `INSERT INTO users (id, name, email, created_at, updated_at) VALUES ($1, $2, $3, $4, $5)
+	ON CONFLICT (email) DO UPDATE SET name = EXCLUDED.name, updated_at = EXCLUDED.updated_at
+	RETURNING id, name, email, created_at, updated_at, (xmax = 0) AS inserted`
+	var u User
+	err = a.db.QueryRowContext(ctx, query, id, user.Name, user.Email, now, now).
+		Scan(&u.ID, &u.Name, &u.Email, &u.CreatedAt, &u.UpdatedAt, &created)
+	// Conflicts on the email are updates, so a unique violation can only come from the primary key.
+	if database.IsUniqueViolation(err) {
+		return nil, false, ErrIDTaken
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("query row: %w", err)
+	}
+	return &u, created, nil
+}
+
+// CreateUsers inserts the users in a single transaction, nothing is stored if any of them fails.
+// A user reusing an id or email fails the call with a DuplicateUserError.
+func (a *Accessor) CreateUsers(ctx context.Context, users []User, now time.Time) (_ []User, err error) {
+	defer database.ObserveQuery("user.create_users")()
+	defer database.WrapError(&err, "user.create_users")
+	for i := range users {
+		if err := users[i].Validate(); err != nil {
+			return nil, fmt.Errorf("validate user %d: %w", i, err)
+		}
+	}
+
+	tx, err := a.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() {
+		err := tx.Rollback()
+		if err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Printf("rollback tx: %v", err)
+		}
+	}()
+
+	created := make([]User, 0, len(users))
+	query := `INSERT INTO users (id, name, email, created_at, updated_at) VALUES ($1, $2, $3, $4, $5)`
+	for i, u := range users {
+		id := u.ID
+		if id == uuid.Nil {
+			id = uuid.New()
+		}
+		if _, err := tx.ExecContext(ctx, query, id, u.Name, u.Email, now, now); err != nil {
+			if database.IsUniqueViolation(err) {
+				field := "id"
+				if database.ViolatedConstraint(err) == "users_email_key" {
+					field = "email"
+				}
+				return nil, &DuplicateUserError{Index: i, Field: field}
+			}
+			return nil, fmt.Errorf("exec context user %d: %w", i, err)
+		}
+		created = append(created, User{ID: id, Name: u.Name, Email: u.Email, CreatedAt: now, UpdatedAt: now})
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+	return created, nil
+}
+
 func (a *Accessor) GetUsers(ctx context.Context) ([]User, error) {
-	query := `SELECT id, name, email FROM users`
+	var users []User
+	err := a.retry.Do(ctx, func() error {
+		// Start over on a retry, the failed attempt may have read some users already.
+		users = []User{}
+		return a.EachUser(ctx, func(user User) error {
+			users = append(users, user)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// EachUser calls fn for every user as the rows are read from the database cursor, so that callers
+// can stream all users without holding them in memory. Users come in the order of GetUsersPage. It
+// stops at the first error fn returns.
+func (a *Accessor) EachUser(ctx context.Context, fn func(User) error) (err error) {
+	defer database.ObserveQuery("user.get_users")()
+	defer database.WrapError(&err, "user.get_users")
+	query := `SELECT id, name, email, created_at, updated_at FROM users` + a.activeOnly(" WHERE ") + ` ORDER BY name, id`
 	rows, err := a.db.QueryContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var user User
+		if err := rows.Scan(&user.ID, &user.Name, &user.Email, &user.CreatedAt, &user.UpdatedAt); err != nil {
+			return fmt.Errorf("scan: %w", err)
+		}
+		if err := fn(user); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("rows: %w", err)
+	}
+
+	return nil
+}
+
+// GetUsersPage returns a page of users ordered by name.
+func (a *Accessor) GetUsersPage(ctx context.Context, limit, offset int) (_ []User, err error) {
+	defer database.ObserveQuery("user.get_users_page")()
+	defer database.WrapError(&err, "user.get_users_page")
+	query := `SELECT id, name, email, created_at, updated_at FROM users` + a.activeOnly(" WHERE ") + ` ORDER BY name, id LIMIT $1 OFFSET $2`
+	rows, err := a.db.QueryContext(ctx, query, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("query: %w", err)
 	}
@@ -40,7 +187,7 @@ func (a *Accessor) GetUsers(ctx context.Context) ([]User, error) {
 	users := []User{}
 	for rows.Next() {
 		var user User
-		if err := rows.Scan(&user.ID, &user.Name, &user.Email); err != nil {
+		if err := rows.Scan(&user.ID, &user.Name, &user.Email, &user.CreatedAt, &user.UpdatedAt); err != nil {
 			return nil, fmt.Errorf("scan: %w", err)
 		}
 		users = append(users, user)
@@ -52,15 +199,133 @@ func (a *Accessor) GetUsers(ctx context.Context) ([]User, error) {
 	return users, nil
 }
 
-func (a *Accessor) GetUser(ctx context.Context, id uuid.UUID) (*User, error) {
-	query := `SELECT id, name, email FROM users WHERE id = $1`
-	row := a.db.QueryRowContext(ctx, query, id)
+func (a *Accessor) GetUser(ctx context.Context, id uuid.UUID) (_ *User, err error) {
+	defer database.ObserveQuery("user.get_user")()
+	defer database.WrapError(&err, "user.get_user", id)
+	query := `SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`
 
 	var user User
-	err := row.Scan(&user.ID, &user.Name, &user.Email)
+	err = a.retry.Do(ctx, func() error {
+		row := a.db.QueryRowContext(ctx, query, id)
+		return row.Scan(&user.ID, &user.Name, &user.Email, &user.CreatedAt, &user.UpdatedAt)
+	})
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, nil
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("scan: %w", err)
+	}
+
+	return &user, nil
+}
+
+// GetUsersByIDs returns the users with the given IDs in one query, ordered by name, then ID.
+// IDs with no user are left out, and an empty ids returns an empty slice without querying.
+func (a *Accessor) GetUsersByIDs(ctx context.Context, ids []uuid.UUID) (_ []User, err error) {
+	if len(ids) == 0 {
+		return []User{}, nil
+	}
+	defer database.ObserveQuery("user.get_users_by_ids")()
+	defer database.WrapError(&err, "user.get_users_by_ids")
+	query := `SELECT id, name, email, created_at, updated_at FROM users WHERE id = ANY($1) ORDER BY name, id`
+	rows, err := a.db.QueryContext(ctx, query, uuidArray(ids))
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+	defer rows.Close()
+
+	users := []User{}
+	for rows.Next() {
+		var user User
+		if err := rows.Scan(&user.ID, &user.Name, &user.Email, &user.CreatedAt, &user.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan: %w", err)
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows: %w", err)
+	}
+
+	return users, nil
+}
+
+// GetUserByEmail returns the user with the given email, or ErrNotFound if there is none. Emails
+// are compared case-insensitively, so "Alice@Example.com" finds a user stored as alice@example.com.
+func (a *Accessor) GetUserByEmail(ctx context.Context, email string) (_ *User, err error) {
+	defer database.ObserveQuery("user.get_user_by_email")()
+	defer database.WrapError(&err, "user.get_user_by_email")
+	query := `SELECT id, name, email, created_at, updated_at FROM users WHERE lower(email) = lower($1)`
+	row := a.db.QueryRowContext(ctx, query, email)
+
+	var user User
+	if err := row.Scan(&user.ID, &user.Name, &user.Email, &user.CreatedAt, &user.UpdatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("scan: %w", err)
+	}
+
+	return &user, nil
+}
+
+// SetUserActive activates or deactivates the user, bumping updated_at to now, or returns ErrNotFound
+// if it does not exist. Inactive users are kept, only hidden from listings and availability lookups.
+func (a *Accessor) SetUserActive(ctx context.Context, id uuid.UUID, active bool, now time.Time) (err error) {
+	defer database.ObserveQuery("user.set_user_active")()
+	defer database.WrapError(&err, "user.set_user_active", id)
+	query := `UPDATE users SET active = $1, updated_at = $2 WHERE id = $3`
+	res, err := a.db.ExecContext(ctx, query, active, now, id)
+	if err != nil {
+		return fmt.Errorf("exec context: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected: %w", err)
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// PatchUser updates only the fields set in patch, bumps updated_at to now and returns the updated user,
+// or ErrNotFound if it does not exist.
+func (a *Accessor) PatchUser(ctx context.Context, id uuid.UUID, patch UserPatch, now time.Time) (_ *User, err error) {
+	defer database.ObserveQuery("user.patch_user")()
+	defer database.WrapError(&err, "user.patch_user", id)
+	if err := patch.Validate(); err != nil {
+		return nil, fmt.Errorf("validate: %w", err)
+	}
+
+	// Column names come from this allowlist only, values are always bound as parameters.
+	fields := []struct {
+		column string
+		value  *string
+	}{
+		{column: "name", value: patch.Name},
+		{column: "email", value: patch.Email},
+	}
+
+	var sets []string
+	var args []any
+	for _, f := range fields {
+		if f.value == nil {
+			continue
+		}
+		args = append(args, *f.value)
+		sets = append(sets, fmt.Sprintf("%s = $%d", f.column, len(args)))
+	}
+	args = append(args, now)
+	sets = append(sets, fmt.Sprintf("updated_at = $%d", len(args)))
+	args = append(args, id)
+
+	query := fmt.Sprintf(`UPDATE users SET %s WHERE id = $%d RETURNING id, name, email, created_at, updated_at`, strings.Join(sets, ", "), len(args))
+	row := a.db.QueryRowContext(ctx, query, args...)
+
+	var user User
+	if err := row.Scan(&user.ID, &user.Name, &user.Email, &user.CreatedAt, &user.UpdatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
 		}
 		return nil, fmt.Errorf("scan: %w", err)
 	}
@@ -69,7 +334,9 @@ func (a *Accessor) GetUser(ctx context.Context, id uuid.UUID) (*User, error) {
 }
 
 // GetUserSlots returns the user's availability slots.
-func (a *Accessor) GetUserSlots(ctx context.Context, userID uuid.UUID) ([]Slot, error) {
+func (a *Accessor) GetUserSlots(ctx context.Context, userID uuid.UUID) (_ []Slot, err error) {
+	defer database.ObserveQuery("user.get_user_slots")()
+	defer database.WrapError(&err, "user.get_user_slots", userID)
 	query := `SELECT start_time, end_time FROM users_availability WHERE user_id = $1`
 	rows, err := a.db.QueryContext(ctx, query, userID)
 	if err != nil {
@@ -93,34 +360,225 @@ func (a *Accessor) GetUserSlots(ctx context.Context, userID uuid.UUID) ([]Slot,
 	return slots, nil
 }
 
-// CreateUserSlots creates the user's availability slots.
-func (a *Accessor) CreateUserSlots(ctx context.Context, userID uuid.UUID, slots []Slot) ([]Slot, error) {
+// GetNextAvailableSlot returns the earliest of the user's availability slots that starts no earlier
+// than after and is at least durationHours long, or nil if there is none. Only one-off availability
+// is consulted, not recurring rules.
+func (a *Accessor) GetNextAvailableSlot(ctx context.Context, userID uuid.UUID, after time.Time, durationHours int) (_ *Slot, err error) {
+	defer database.ObserveQuery("user.get_next_available_slot")()
+	defer database.WrapError(&err, "user.get_next_available_slot", userID)
+	query := `SELECT start_time, end_time FROM users_availability
+	WHERE user_id = $1 AND start_time >= $2 AND end_time - start_time >= make_interval(hours => $3)
+	ORDER BY start_time
+	LIMIT 1`
+	var slot Slot
+	err = a.db.QueryRowContext(ctx, query, userID, after, durationHours).Scan(&slot.StartTime, &slot.EndTime)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scan: %w", err)
+	}
+	return &slot, nil
+}
+
+// GetUsersSlots returns the availability slots of each of the given users, keyed by user ID.
+// Users without slots are absent from the map.
+func (a *Accessor) GetUsersSlots(ctx context.Context, userIDs []uuid.UUID) (_ map[uuid.UUID][]Slot, err error) {
+	defer database.ObserveQuery("user.get_users_slots")()
+	defer database.WrapError(&err, "user.get_users_slots")
+	query := `SELECT user_id, start_time, end_time FROM users_availability WHERE user_id = ANY($1) ORDER BY start_time`
+	rows, err := a.db.QueryContext(ctx, query, uuidArray(userIDs))
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+	defer rows.Close()
+
+	slots := map[uuid.UUID][]Slot{}
+	for rows.Next() {
+		var userID uuid.UUID
+		var slot Slot
+		if err := rows.Scan(&userID, &slot.StartTime, &slot.EndTime); err != nil {
+			return nil, fmt.Errorf("scan: %w", err)
+		}
+		slots[userID] = append(slots[userID], slot)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows: %w", err)
+	}
+
+	return slots, nil
+}
+
+// GetUsersRecurrences returns the weekly availability rules of each of the given users, keyed by
+// user ID. Users without rules are absent from the map.
+func (a *Accessor) GetUsersRecurrences(ctx context.Context, userIDs []uuid.UUID) (_ map[uuid.UUID][]Recurrence, err error) {
+	defer database.ObserveQuery("user.get_users_recurrences")()
+	defer database.WrapError(&err, "user.get_users_recurrences")
+	query := `SELECT user_id, weekday, start_minute, end_minute, valid_from, valid_until FROM users_recurring_availability WHERE user_id = ANY($1)`
+	rows, err := a.db.QueryContext(ctx, query, uuidArray(userIDs))
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+	defer rows.Close()
+
+	recurrences := map[uuid.UUID][]Recurrence{}
+	for rows.Next() {
+		var userID uuid.UUID
+		var r Recurrence
+		var weekday int
+		var validUntil sql.NullTime
+		if err := rows.Scan(&userID, &weekday, &r.StartMinute, &r.EndMinute, &r.ValidFrom, &validUntil); err != nil {
+			return nil, fmt.Errorf("scan: %w", err)
+		}
+		r.Weekday = time.Weekday(weekday)
+		if validUntil.Valid {
+			r.ValidUntil = &validUntil.Time
+		}
+		recurrences[userID] = append(recurrences[userID], r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows: %w", err)
+	}
+
+	return recurrences, nil
+}
+
+// CreateUserSlots creates the user's availability slots. New slots that overlap the user's existing
+// ones fail the whole call with a SlotConflictError, or with WithMergeSlots are joined with them.
+// It returns the rows it inserted.
+func (a *Accessor) CreateUserSlots(ctx context.Context, userID uuid.UUID, slots []Slot) (_ []Slot, err error) {
+	defer database.ObserveQuery("user.create_user_slots")()
+	defer database.WrapError(&err, "user.create_user_slots", userID)
 	tx, err := a.db.BeginTx(ctx, nil)
 	if err != nil {
 		return nil, fmt.Errorf("begin tx: %w", err)
 	}
 	defer func() {
 		err := tx.Rollback()
-		if err != nil {
+		if err != nil && !errors.Is(err, sql.ErrTxDone) {
 			log.Printf("rollback tx: %v", err)
 		}
 	}()
 
-	for _, slot := range slots {
-		query := `INSERT INTO users_availability (user_id, start_time, end_time) VALUES ($1, $2, $3)`
-		if _, err := tx.ExecContext(ctx, query, userID, slot.StartTime, slot.EndTime); err != nil {
-			return nil, fmt.Errorf("exec context: %w", err)
+	// Locking the existing slots alone would not do, a user without any has nothing to lock. The lock
+	// on the user row is what serializes concurrent calls, so that they cannot both miss each
+	// other's slots.
+	if _, err := tx.ExecContext(ctx, `SELECT 1 FROM users WHERE id = $1 FOR UPDATE`, userID); err != nil {
+		return nil, fmt.Errorf("lock user: %w", err)
+	}
+	query := `SELECT start_time, end_time FROM users_availability WHERE user_id = $1 ORDER BY start_time FOR UPDATE`
+	rows, err := tx.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+	defer rows.Close()
+	var conflicts []Slot
+	for rows.Next() {
+		var existing Slot
+		if err := rows.Scan(&existing.StartTime, &existing.EndTime); err != nil {
+			return nil, fmt.Errorf("scan: %w", err)
+		}
+		if slices.ContainsFunc(slots, existing.overlaps) {
+			conflicts = append(conflicts, existing)
 		}
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows: %w", err)
+	}
+
+	if len(conflicts) > 0 {
+		if !a.mergeSlots {
+			return nil, &SlotConflictError{Conflicts: conflicts}
+		}
+		for _, c := range conflicts {
+			query := `DELETE FROM users_availability WHERE user_id = $1 AND start_time = $2 AND end_time = $3`
+			if _, err := tx.ExecContext(ctx, query, userID, c.StartTime, c.EndTime); err != nil {
+				return nil, fmt.Errorf("exec context: %w", err)
+			}
+		}
+		slots = mergeAll(append(slices.Clone(conflicts), slots...))
+	}
+
+	inserted, err := a.insertSlots(ctx, tx, userID, slots)
+	if err != nil {
+		return nil, err
+	}
 
 	if err := tx.Commit(); err != nil {
 		return nil, fmt.Errorf("commit: %w", err)
 	}
-	return slots, nil
+	return inserted, nil
+}
+
+// ReplaceUserSlots replaces all of the user's availability slots with the given ones in a single
+// transaction, so that readers see either the old slots or the new ones. It returns the rows it inserted.
+func (a *Accessor) ReplaceUserSlots(ctx context.Context, userID uuid.UUID, slots []Slot) (_ []Slot, err error) {
+	defer database.ObserveQuery("user.replace_user_slots")()
+	defer database.WrapError(&err, "user.replace_user_slots", userID)
+	tx, err := a.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() {
+		err := tx.Rollback()
+		if err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Printf("rollback tx: %v", err)
+		}
+	}()
+
+	query := `DELETE FROM users_availability WHERE user_id = $1`
+	if _, err := tx.ExecContext(ctx, query, userID); err != nil {
+		return nil, fmt.Errorf("exec context: %w", err)
+	}
+	inserted, err := a.insertSlots(ctx, tx, userID, slots)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+	return inserted, nil
+}
+
+// insertSlots stores the user's slots in tx and returns those actually inserted. A slot the user
+// already has, e.g. inserted by a concurrent call, is left out when the accessor skips duplicates
+// and otherwise fails the call with a DuplicateSlotError.
+func (a *Accessor) insertSlots(ctx context.Context, tx *sql.Tx, userID uuid.UUID, slots []Slot) ([]Slot, error) {
+	query := `INSERT INTO users_availability (user_id, start_time, end_time) VALUES ($1, $2, $3)`
+	if a.skipDuplicateSlots {
+		query += ` ON CONFLICT DO NOTHING`
+	}
+	inserted := make([]Slot, 0, len(slots))
+	for _, slot := range slots {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		res, err := tx.ExecContext(ctx, query, userID, slot.StartTime, slot.EndTime)
+		if database.IsUniqueViolation(err) {
+			return nil, &DuplicateSlotError{Slot: slot}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("exec context: %w", err)
+		}
+		if a.skipDuplicateSlots {
+			n, err := res.RowsAffected()
+			if err != nil {
+				return nil, fmt.Errorf("rows affected: %w", err)
+			}
+			if n == 0 {
+				continue
+			}
+		}
+		inserted = append(inserted, slot)
+	}
+	return inserted, nil
 }
 
 // DeleteUserSlots deletes the user's availability slots.
-func (a *Accessor) DeleteUserSlots(ctx context.Context, userID uuid.UUID) error {
+func (a *Accessor) DeleteUserSlots(ctx context.Context, userID uuid.UUID) (err error) {
+	defer database.ObserveQuery("user.delete_user_slots")()
+	defer database.WrapError(&err, "user.delete_user_slots", userID)
 	query := `DELETE FROM users_availability WHERE user_id = $1`
 	if _, err := a.db.ExecContext(ctx, query, userID); err != nil {
 		return fmt.Errorf("exec context: %w", err)
@@ -128,29 +586,210 @@ func (a *Accessor) DeleteUserSlots(ctx context.Context, userID uuid.UUID) error
 	return nil
 }
 
+// DeleteSlotsInRange deletes every user's availability slots lying entirely within [from, to], e.g.
+// to purge a holiday, and returns how many were deleted. Slots straddling a bound are kept.
+func (a *Accessor) DeleteSlotsInRange(ctx context.Context, from, to time.Time) (_ int64, err error) {
+	defer database.ObserveQuery("user.delete_slots_in_range")()
+	defer database.WrapError(&err, "user.delete_slots_in_range")
+	query := `DELETE FROM users_availability WHERE start_time >= $1 AND end_time <= $2`
+	res, err := a.db.ExecContext(ctx, query, from, to)
+	if err != nil {
+		return 0, fmt.Errorf("exec context: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("rows affected: %w", err)
+	}
+	return n, nil
+}
+
 // GetUsersForSlot returns the users that are available for the given slot and duration hours.
-func (a *Accessor) GetUsersForSlot(ctx context.Context, slot Slot, durationHours int) ([]User, error) {
-	query := `SELECT users.id, users.name, users.email
+// When userIDs are given only those users are considered. Every bound is inclusive: an availability
+// window matching the slot exactly, or exactly durationHours long, makes the user available.
+func (a *Accessor) GetUsersForSlot(ctx context.Context, slot Slot, durationHours int, userIDs ...uuid.UUID) (_ []User, err error) {
+	defer database.ObserveQuery("user.get_users_for_slot")()
+	defer database.WrapError(&err, "user.get_users_for_slot")
+	condition := `users_availability.start_time <= $1 AND users_availability.end_time >= $2 AND users_availability.end_time - users_availability.start_time >= make_interval(hours => $3)`
+	return a.usersForSlot(ctx, condition, slot, durationHours, false, userIDs)
+}
+
+// GetUsersForSlotOverlap is GetUsersForSlot for flexible schedules: an availability window only has to
+// overlap the slot for at least durationHours instead of containing it.
+func (a *Accessor) GetUsersForSlotOverlap(ctx context.Context, slot Slot, durationHours int, userIDs ...uuid.UUID) (_ []User, err error) {
+	defer database.ObserveQuery("user.get_users_for_slot_overlap")()
+	defer database.WrapError(&err, "user.get_users_for_slot_overlap")
+	condition := `LEAST(users_availability.end_time, $2) - GREATEST(users_availability.start_time, $1) >= make_interval(hours => $3)`
+	return a.usersForSlot(ctx, condition, slot, durationHours, true, userIDs)
+}
+
+func (a *Accessor) usersForSlot(ctx context.Context, condition string, slot Slot, durationHours int, overlap bool, userIDs []uuid.UUID) ([]User, error) {
+	query := `SELECT users.id, users.name, users.email, users.created_at, users.updated_at
 	FROM users_availability
 	JOIN users ON users_availability.user_id = users.id
-	WHERE users_availability.start_time <= $1 AND users_availability.end_time >= $2 AND users_availability.end_time - users_availability.start_time >= make_interval(hours => $3)
+	WHERE ` + condition + a.activeOnly(" AND ")
+	args := []any{slot.StartTime, slot.EndTime, durationHours}
+	if len(userIDs) > 0 {
+		query += ` AND users.id = ANY($4)`
+		args = append(args, uuidArray(userIDs))
+	}
+	query += `
 	ORDER BY users.name`
-	rows, err := a.db.QueryContext(ctx, query, slot.StartTime, slot.EndTime, durationHours)
+	rows, err := a.queryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("query: %w", err)
 	}
 	defer rows.Close()
 
+	// A user with several matching windows comes back once per window.
 	var users []User
+	seen := make(map[uuid.UUID]bool)
 	for rows.Next() {
 		var user User
-		if err := rows.Scan(&user.ID, &user.Name, &user.Email); err != nil {
+		if err := rows.Scan(&user.ID, &user.Name, &user.Email, &user.CreatedAt, &user.UpdatedAt); err != nil {
 			return nil, fmt.Errorf("scan: %w", err)
 		}
-		users = append(users, user)
+		if !seen[user.ID] {
+			seen[user.ID] = true
+			users = append(users, user)
+		}
 	}
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("rows: %w", err)
 	}
+
+	recurringUsers, err := a.getRecurringUsersForSlot(ctx, slot, durationHours, overlap, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("get recurring users for slot: %w", err)
+	}
+	added := false
+	for _, u := range recurringUsers {
+		if !seen[u.ID] {
+			seen[u.ID] = true
+			users = append(users, u)
+			added = true
+		}
+	}
+	if added {
+		slices.SortStableFunc(users, func(a, b User) int { return strings.Compare(a.Name, b.Name) })
+	}
+
 	return users, nil
 }
+
+// CreateUserRecurrences stores weekly availability rules for the user.
+func (a *Accessor) CreateUserRecurrences(ctx context.Context, userID uuid.UUID, recurrences []Recurrence) (_ []Recurrence, err error) {
+	defer database.ObserveQuery("user.create_user_recurrences")()
+	defer database.WrapError(&err, "user.create_user_recurrences", userID)
+	for i := range recurrences {
+		if err := recurrences[i].Validate(); err != nil {
+			return nil, fmt.Errorf("validate: %w", err)
+		}
+	}
+
+	tx, err := a.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() {
+		err := tx.Rollback()
+		if err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Printf("rollback tx: %v", err)
+		}
+	}()
+
+	query := `INSERT INTO users_recurring_availability (user_id, weekday, start_minute, end_minute, valid_from, valid_until) VALUES ($1, $2, $3, $4, $5, $6)`
+	for _, r := range recurrences {
+		if _, err := tx.ExecContext(ctx, query, userID, int(r.Weekday), r.StartMinute, r.EndMinute, r.ValidFrom, r.ValidUntil); err != nil {
+			return nil, fmt.Errorf("exec context: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+	return recurrences, nil
+}
+
+// getRecurringUsersForSlot expands the weekly rules active around the slot and returns the users
+// whose expanded availability covers it (or overlaps it, in overlap mode), under the same rules as one-off slots.
+func (a *Accessor) getRecurringUsersForSlot(ctx context.Context, slot Slot, durationHours int, overlap bool, userIDs []uuid.UUID) ([]User, error) {
+	query := `SELECT users.id, users.name, users.email, users.created_at, users.updated_at, r.weekday, r.start_minute, r.end_minute, r.valid_from, r.valid_until
+	FROM users_recurring_availability r
+	JOIN users ON r.user_id = users.id
+	WHERE r.valid_from <= $2 AND (r.valid_until IS NULL OR r.valid_until >= $1)` + a.activeOnly(" AND ")
+	args := []any{slot.StartTime, slot.EndTime}
+	if len(userIDs) > 0 {
+		query += ` AND users.id = ANY($3)`
+		args = append(args, uuidArray(userIDs))
+	}
+	query += `
+	ORDER BY users.name`
+	rows, err := a.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+	defer rows.Close()
+
+	duration := time.Duration(durationHours) * time.Hour
+	var users []User
+	for rows.Next() {
+		var user User
+		var r Recurrence
+		var weekday int
+		var validUntil sql.NullTime
+		if err := rows.Scan(&user.ID, &user.Name, &user.Email, &user.CreatedAt, &user.UpdatedAt, &weekday, &r.StartMinute, &r.EndMinute, &r.ValidFrom, &validUntil); err != nil {
+			return nil, fmt.Errorf("scan: %w", err)
+		}
+		r.Weekday = time.Weekday(weekday)
+		if validUntil.Valid {
+			r.ValidUntil = &validUntil.Time
+		}
+
+		for _, s := range r.Expand(slot.StartTime, slot.EndTime) {
+			if s.matches(slot, duration, overlap) {
+				users = append(users, user)
+				break
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows: %w", err)
+	}
+	return users, nil
+}
+
+// uuidArray converts ids into a Postgres array parameter.
+func uuidArray(ids []uuid.UUID) any {
+	strs := make([]string, len(ids))
+	for i, id := range ids {
+		strs[i] = id.String()
+	}
+	return pq.Array(strs)
+}
+
+// CountUsers returns the total number of users, as listed by GetUsersPage.
+func (a *Accessor) CountUsers(ctx context.Context) (_ int, err error) {
+	defer database.ObserveQuery("user.count_users")()
+	defer database.WrapError(&err, "user.count_users")
+	var count int
+	query := `SELECT COUNT(*) FROM users` + a.activeOnly(" WHERE ")
+	if err := a.db.QueryRowContext(ctx, query).Scan(&count); err != nil {
+		return 0, fmt.Errorf("scan: %w", err)
+	}
+	return count, nil
+}
+
+// CountUsersWithoutAvailability returns the number of users that have no availability slots, nor
+// recurring rules. Like CountUsers it leaves deactivated users out unless made WithInactive.
+func (a *Accessor) CountUsersWithoutAvailability(ctx context.Context) (_ int, err error) {
+	defer database.ObserveQuery("user.count_users_without_availability")()
+	defer database.WrapError(&err, "user.count_users_without_availability")
+	var count int
+	query := `SELECT COUNT(*) FROM users
+	WHERE NOT EXISTS (SELECT 1 FROM users_availability WHERE users_availability.user_id = users.id)
+	AND NOT EXISTS (SELECT 1 FROM users_recurring_availability WHERE users_recurring_availability.user_id = users.id)` + a.activeOnly(" AND ")
+	if err := a.db.QueryRowContext(ctx, query).Scan(&count); err != nil {
+		return 0, fmt.Errorf("scan: %w", err)
+	}
+	return count, nil
+}
@@ -0,0 +1,35 @@
+package user
+
+import (
+	"slices"
+	"time"
+)
+
+// Gaps returns the parts of [from, to) not covered by any of the slots, in order.
+// Slots may overlap each other or extend beyond the window.
+func Gaps(slots []Slot, from, to time.Time) []Slot {
+	sorted := slices.Clone(slots)
+	slices.SortFunc(sorted, func(a, b Slot) int { return a.StartTime.Compare(b.StartTime) })
+
+	gaps := []Slot{}
+	cursor := from
+	for _, s := range sorted {
+		if !s.EndTime.After(cursor) {
+			continue
+		}
+		if !s.StartTime.Before(to) {
+			break
+		}
+		if s.StartTime.After(cursor) {
+			gaps = append(gaps, Slot{StartTime: cursor, EndTime: s.StartTime})
+		}
+		cursor = s.EndTime
+		if !cursor.Before(to) {
+			return gaps
+		}
+	}
+	if cursor.Before(to) {
+		gaps = append(gaps, Slot{StartTime: cursor, EndTime: to})
+	}
+	return gaps
+}
@@ -0,0 +1,62 @@
+package user
+
+import (
+	"errors"
+	"time"
+)
+
+// Recurrence is a weekly availability rule such as "every Monday 09:00-17:00".
+// Times of day are minutes since midnight UTC, and the rule applies between
+// ValidFrom and ValidUntil (inclusive, by date). A nil ValidUntil never expires.
+type Recurrence struct {
+	Weekday     time.Weekday `json:"weekday"`
+	StartMinute int          `json:"start_minute"`
+	EndMinute   int          `json:"end_minute"`
+	ValidFrom   time.Time    `json:"valid_from"`
+	ValidUntil  *time.Time   `json:"valid_until,omitempty"`
+}
+
+func (r *Recurrence) Validate() error {
+	if r.Weekday < time.Sunday || r.Weekday > time.Saturday {
+		return errors.New("weekday must be between 0 (Sunday) and 6 (Saturday)")
+	}
+	if r.StartMinute < 0 || r.EndMinute > 24*60 || r.StartMinute >= r.EndMinute {
+		return errors.New("start and end minute must satisfy 0 <= start < end <= 1440")
+	}
+	if r.ValidFrom.IsZero() {
+		return errors.New("valid from is required")
+	}
+	if r.ValidUntil != nil && r.ValidUntil.Before(r.ValidFrom) {
+		return errors.New("valid until is before valid from")
+	}
+	return nil
+}
+
+// Expand returns the concrete slots produced by the rule that overlap [from, to].
+func (r *Recurrence) Expand(from, to time.Time) []Slot {
+	validFrom := truncateDay(r.ValidFrom)
+	slots := []Slot{}
+	for day := truncateDay(from); !day.After(to); day = day.AddDate(0, 0, 1) {
+		if day.Weekday() != r.Weekday || day.Before(validFrom) {
+			continue
+		}
+		if r.ValidUntil != nil && day.After(truncateDay(*r.ValidUntil)) {
+			break
+		}
+
+		slot := Slot{
+			StartTime: day.Add(time.Duration(r.StartMinute) * time.Minute),
+			EndTime:   day.Add(time.Duration(r.EndMinute) * time.Minute),
+		}
+		if slot.EndTime.Before(from) || slot.StartTime.After(to) {
+			continue
+		}
+		slots = append(slots, slot)
+	}
+	return slots
+}
+
+func truncateDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
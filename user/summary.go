@@ -0,0 +1,58 @@
+package user
+
+import "time"
+
+// WeekdayHours is the availability falling on one weekday.
+type WeekdayHours struct {
+	Weekday time.Weekday `json:"weekday"`
+	Hours   float64      `json:"hours"`
+}
+
+// AvailabilitySummary totals availability by weekday, for profile pages.
+type AvailabilitySummary struct {
+	TotalHours float64 `json:"total_hours"`
+	// Weekdays always holds all seven days, from Sunday (0) to Saturday (6).
+	Weekdays []WeekdayHours `json:"weekdays"`
+}
+
+// Summarize totals the hours covered by the slots, split by UTC weekday as recurrences are.
+// Overlapping slots are counted once, and a slot crossing midnight counts towards both days.
+func Summarize(slots []Slot) AvailabilitySummary {
+	summary := AvailabilitySummary{Weekdays: make([]WeekdayHours, 7)}
+	for day := range summary.Weekdays {
+		summary.Weekdays[day].Weekday = time.Weekday(day)
+	}
+	if len(slots) == 0 {
+		return summary
+	}
+
+	from, to := slots[0].StartTime, slots[0].EndTime
+	for _, s := range slots[1:] {
+		if s.StartTime.Before(from) {
+			from = s.StartTime
+		}
+		if s.EndTime.After(to) {
+			to = s.EndTime
+		}
+	}
+
+	var total time.Duration
+	byDay := make([]time.Duration, 7)
+	for _, s := range merge(slots, from, to) {
+		total += s.EndTime.Sub(s.StartTime)
+		for start := s.StartTime.UTC(); start.Before(s.EndTime); {
+			end := truncateDay(start).AddDate(0, 0, 1)
+			if end.After(s.EndTime) {
+				end = s.EndTime.UTC()
+			}
+			byDay[start.Weekday()] += end.Sub(start)
+			start = end
+		}
+	}
+
+	summary.TotalHours = total.Hours()
+	for day, d := range byDay {
+		summary.Weekdays[day].Hours = d.Hours()
+	}
+	return summary
+}
@@ -1,29 +1,171 @@
 package user
 
 import (
+	"encoding/json"
 	"errors"
+	"events-system/validation"
+	"fmt"
+	"net/mail"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// ErrNotFound is returned by the accessor when the requested user does not exist.
+var ErrNotFound = errors.New("user not found")
+
+// ErrAlreadyExists is returned by CreateUser when another user already has the ID or the email.
+var ErrAlreadyExists = errors.New("user already exists")
+
+// ErrIDTaken is returned by UpsertUser when the payload's ID belongs to a user with another email.
+var ErrIDTaken = errors.New("id belongs to another user")
+
+// SlotConflictError is returned by CreateUserSlots when new slots overlap the user's existing
+// availability, Conflicts holds the existing slots that were hit.
+type SlotConflictError struct {
+	Conflicts []Slot
+}
+
+func (e *SlotConflictError) Error() string {
+	return fmt.Sprintf("%d existing slots overlap the new ones", len(e.Conflicts))
+}
+
+// DuplicateSlotError is returned by CreateUserSlots and ReplaceUserSlots when the user already has
+// Slot, typically because a concurrent call stored it first, unless duplicates are skipped.
+type DuplicateSlotError struct {
+	Slot Slot
+}
+
+func (e *DuplicateSlotError) Error() string {
+	return fmt.Sprintf("slot %s - %s already exists", e.Slot.StartTime.Format(time.RFC3339), e.Slot.EndTime.Format(time.RFC3339))
+}
+
+// DuplicateUserError is returned by CreateUsers when the user at Index in the batch has the id or
+// email, as named by Field, of an existing user or of an earlier one in the batch.
+type DuplicateUserError struct {
+	Index int
+	Field string
+}
+
+func (e *DuplicateUserError) Error() string {
+	return fmt.Sprintf("user %d: %s already exists", e.Index, e.Field)
+}
+
 type User struct {
 	ID    uuid.UUID `json:"id"`
 	Name  string    `json:"name"`
 	Email string    `json:"email"`
+	// CreatedAt and UpdatedAt are left zero, and omitted from JSON, where a user is embedded in
+	// another resource, such as an event organizer.
+	CreatedAt time.Time `json:"created_at,omitzero"`
+	UpdatedAt time.Time `json:"updated_at,omitzero"`
 }
 
+// Validate reports every invalid field of the user as validation.Errors.
 func (u *User) Validate() error {
+	var errs validation.Errors
 	if u.Name == "" {
-		return errors.New("name is required")
+		errs.Add("name", "name is required")
 	}
 	if u.Email == "" {
-		return errors.New("email is required")
+		errs.Add("email", "email is required")
+	} else if err := ValidateEmail(u.Email); err != nil {
+		errs.Add("email", err.Error())
+	}
+	return errs.Err()
+}
+
+// ValidateEmail checks that email is a bare address such as "alice@example.com".
+func ValidateEmail(email string) error {
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email {
+		return fmt.Errorf("invalid email %q", email)
 	}
 	return nil
 }
 
+// UserPatch is a partial user update, nil fields are left unchanged.
+type UserPatch struct {
+	Name  *string
+	Email *string
+}
+
+// Validate reports an empty patch, which concerns no field in particular, as a plain error and
+// otherwise every invalid field as validation.Errors.
+func (p *UserPatch) Validate() error {
+	if p.Name == nil && p.Email == nil {
+		return errors.New("at least one of name or email is required")
+	}
+	var errs validation.Errors
+	if p.Name != nil && *p.Name == "" {
+		errs.Add("name", "name must not be empty")
+	}
+	if p.Email != nil {
+		if err := ValidateEmail(*p.Email); err != nil {
+			errs.Add("email", err.Error())
+		}
+	}
+	return errs.Err()
+}
+
+// Slot is a time range. In JSON its bounds are unix seconds, see MarshalJSON.
 type Slot struct {
 	StartTime time.Time `json:"start_time"`
 	EndTime   time.Time `json:"end_time"`
 }
+
+// epochSlot is the JSON form of a Slot.
+type epochSlot struct {
+	StartTime int64 `json:"start_time"`
+	EndTime   int64 `json:"end_time"`
+}
+
+// MarshalJSON writes the slot as {"start_time": <unix>, "end_time": <unix>}.
+func (s Slot) MarshalJSON() ([]byte, error) {
+	return json.Marshal(epochSlot{StartTime: s.StartTime.Unix(), EndTime: s.EndTime.Unix()})
+}
+
+// UnmarshalJSON reads unix seconds into UTC times.
+func (s *Slot) UnmarshalJSON(b []byte) error {
+	var e epochSlot
+	if err := json.Unmarshal(b, &e); err != nil {
+		return err
+	}
+	s.StartTime = time.Unix(e.StartTime, 0).UTC()
+	s.EndTime = time.Unix(e.EndTime, 0).UTC()
+	return nil
+}
+
+// overlaps reports whether s and other share some time, slots that merely touch do not overlap.
+func (s Slot) overlaps(other Slot) bool {
+	return s.StartTime.Before(other.EndTime) && other.StartTime.Before(s.EndTime)
+}
+
+// matches reports whether availability window s makes a user available for slot: it must contain the
+// slot, or in overlap mode share at least duration with it, and always be at least duration long.
+func (s Slot) matches(slot Slot, duration time.Duration, overlap bool) bool {
+	if overlap {
+		start, end := s.StartTime, s.EndTime
+		if slot.StartTime.After(start) {
+			start = slot.StartTime
+		}
+		if slot.EndTime.Before(end) {
+			end = slot.EndTime
+		}
+		return end.Sub(start) >= duration
+	}
+	return !s.StartTime.After(slot.StartTime) && !s.EndTime.Before(slot.EndTime) && s.EndTime.Sub(s.StartTime) >= duration
+}
+
+// PageUsers returns at most limit of the users from offset on, or all of them from offset on when
+// limit is 0. An offset past the end gives an empty page.
+func PageUsers(users []User, limit, offset int) []User {
+	if offset >= len(users) {
+		return []User{}
+	}
+	users = users[offset:]
+	if limit > 0 && limit < len(users) {
+		users = users[:limit]
+	}
+	return users
+}
@@ -1,18 +1,31 @@
 package user_test
 
 import (
+	"context"
 	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"events-system/database"
 	"events-system/user"
+	"events-system/validation"
 	"regexp"
+	"slices"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+// userColumns are the columns the user accessor selects, userCreatedAt stamps the mocked rows.
+var (
+	userColumns   = []string{"id", "name", "email", "created_at", "updated_at"}
+	userCreatedAt = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+)
+
 func TestUser(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	require.NoError(t, err)
@@ -22,28 +35,31 @@ func TestUser(t *testing.T) {
 
 	const name = "Pulkit"
 	const email = "pulkit@example.com"
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
 
-	insertQuery := `INSERT INTO users (id, name, email) VALUES ($1, $2, $3)`
+	insertQuery := `INSERT INTO users (id, name, email, created_at, updated_at) VALUES ($1, $2, $3, $4, $5)`
 	mock.ExpectExec(regexp.QuoteMeta(insertQuery)).
-		WithArgs(sqlmock.AnyArg(), name, email).
+		WithArgs(sqlmock.AnyArg(), name, email, now, now).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	t.Run("create user", func(t *testing.T) {
 		createdUser, err := a.CreateUser(t.Context(), user.User{
 			Name:  name,
 			Email: email,
-		})
+		}, now)
 		require.NoError(t, err)
 		assert.NotEqual(t, uuid.Nil, createdUser.ID)
 		assert.Equal(t, name, createdUser.Name)
 		assert.Equal(t, email, createdUser.Email)
+		assert.Equal(t, now, createdUser.CreatedAt)
+		assert.Equal(t, now, createdUser.UpdatedAt)
 
 		require.NoError(t, mock.ExpectationsWereMet())
 
 		t.Run("get user", func(t *testing.T) {
-			selectQuery := `SELECT id, name, email FROM users WHERE id = $1`
-			rows := sqlmock.NewRows([]string{"id", "name", "email"}).
-				AddRow(createdUser.ID, name, email)
+			selectQuery := `SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`
+			rows := sqlmock.NewRows(userColumns).
+				AddRow(createdUser.ID, name, email, now, now)
 
 			mock.ExpectQuery(regexp.QuoteMeta(selectQuery)).
 				WithArgs(createdUser.ID).
@@ -54,22 +70,420 @@ func TestUser(t *testing.T) {
 			assert.Equal(t, createdUser.ID, u.ID)
 			assert.Equal(t, createdUser.Name, u.Name)
 			assert.Equal(t, createdUser.Email, u.Email)
+			assert.Equal(t, now, u.CreatedAt)
+			assert.Equal(t, now, u.UpdatedAt)
 
 			require.NoError(t, mock.ExpectationsWereMet())
 		})
 
 		t.Run("get user - no rows", func(t *testing.T) {
-			selectQuery := `SELECT id, name, email FROM users WHERE id = $1`
+			missingID := uuid.New()
+			selectQuery := `SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`
 			mock.ExpectQuery(regexp.QuoteMeta(selectQuery)).
-				WithArgs(uuid.New()).
+				WithArgs(missingID).
 				WillReturnError(sql.ErrNoRows)
 
-			_, err := a.GetUser(t.Context(), uuid.New())
-			require.Error(t, err)
+			_, err := a.GetUser(t.Context(), missingID)
+			require.ErrorIs(t, err, user.ErrNotFound)
 		})
 	})
 }
 
+func TestUpsertUser(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	upsertQuery := regexp.QuoteMeta(`INSERT INTO users (id, name, email, created_at, updated_at) VALUES ($1, $2, $3, $4, $5)
+	ON CONFLICT (email) DO UPDATE SET name = EXCLUDED.name, updated_at = EXCLUDED.updated_at
+	RETURNING id, name, email, created_at, updated_at, (xmax = 0) AS inserted`)
+	columns := append(slices.Clone(userColumns), "inserted")
+
+	t.Run("inserts a new email", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = db.Close() })
+		a := user.NewAccessor(db)
+
+		id := uuid.New()
+		mock.ExpectQuery(upsertQuery).
+			WithArgs(id, "Alice", "alice@example.com", now, now).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(id, "Alice", "alice@example.com", now, now, true))
+
+		u, created, err := a.UpsertUser(t.Context(), user.User{ID: id, Name: "Alice", Email: "alice@example.com"}, now)
+		require.NoError(t, err)
+		assert.True(t, created)
+		assert.Equal(t, &user.User{ID: id, Name: "Alice", Email: "alice@example.com", CreatedAt: now, UpdatedAt: now}, u)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("updates the user with the email", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = db.Close() })
+		a := user.NewAccessor(db)
+
+		existingID := uuid.New()
+		mock.ExpectQuery(upsertQuery).
+			WithArgs(sqlmock.AnyArg(), "Alice Smith", "alice@example.com", now, now).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(existingID, "Alice Smith", "alice@example.com", userCreatedAt, now, false))
+
+		u, created, err := a.UpsertUser(t.Context(), user.User{Name: "Alice Smith", Email: "alice@example.com"}, now)
+		require.NoError(t, err)
+		assert.False(t, created)
+		assert.Equal(t, existingID, u.ID)
+		assert.Equal(t, "Alice Smith", u.Name)
+		assert.Equal(t, userCreatedAt, u.CreatedAt)
+		assert.Equal(t, now, u.UpdatedAt)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("id of another user", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = db.Close() })
+		a := user.NewAccessor(db)
+
+		id := uuid.New()
+		mock.ExpectQuery(upsertQuery).
+			WithArgs(id, "Alice", "alice@example.com", now, now).
+			WillReturnError(&pq.Error{Code: "23505", Constraint: "users_pkey"})
+
+		u, created, err := a.UpsertUser(t.Context(), user.User{ID: id, Name: "Alice", Email: "alice@example.com"}, now)
+		require.ErrorIs(t, err, user.ErrIDTaken)
+		assert.False(t, created)
+		assert.Nil(t, u)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("invalid user", func(t *testing.T) {
+		db, _, err := sqlmock.New()
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = db.Close() })
+
+		_, _, err = user.NewAccessor(db).UpsertUser(t.Context(), user.User{Email: "alice@example.com"}, now)
+		var errs validation.Errors
+		require.ErrorAs(t, err, &errs)
+	})
+}
+
+func TestCreateUser(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	a := user.NewAccessor(db)
+	insertQuery := regexp.QuoteMeta(`INSERT INTO users (id, name, email, created_at, updated_at) VALUES ($1, $2, $3, $4, $5)`)
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("taken id or email", func(t *testing.T) {
+		mock.ExpectExec(insertQuery).
+			WithArgs(sqlmock.AnyArg(), "Alice", "alice@example.com", now, now).
+			WillReturnError(&pq.Error{Code: "23505", Constraint: "users_email_key"})
+
+		u, err := a.CreateUser(t.Context(), user.User{Name: "Alice", Email: "alice@example.com"}, now)
+		require.ErrorIs(t, err, user.ErrAlreadyExists)
+		assert.Nil(t, u)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestCreateUsers(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	a := user.NewAccessor(db)
+	insertQuery := regexp.QuoteMeta(`INSERT INTO users (id, name, email, created_at, updated_at) VALUES ($1, $2, $3, $4, $5)`)
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("rolls back on insert failure", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(insertQuery).
+			WithArgs(sqlmock.AnyArg(), "Alice", "alice@example.com", now, now).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(insertQuery).
+			WithArgs(sqlmock.AnyArg(), "Bob", "alice@example.com", now, now).
+			WillReturnError(sql.ErrConnDone)
+		mock.ExpectRollback()
+
+		users, err := a.CreateUsers(t.Context(), []user.User{
+			{Name: "Alice", Email: "alice@example.com"},
+			{Name: "Bob", Email: "alice@example.com"},
+		}, now)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "user 1")
+		assert.Nil(t, users)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("validates before touching the database", func(t *testing.T) {
+		users, err := a.CreateUsers(t.Context(), []user.User{{Name: "Alice", Email: "alice@example.com"}, {Name: ""}}, now)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "validate user 1")
+		assert.Nil(t, users)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGetUserByEmail(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	a := user.NewAccessor(db)
+	selectQuery := `SELECT id, name, email, created_at, updated_at FROM users WHERE lower(email) = lower($1)`
+
+	t.Run("found", func(t *testing.T) {
+		userID := uuid.New()
+		mock.ExpectQuery(regexp.QuoteMeta(selectQuery)).
+			WithArgs("alice@example.com").
+			WillReturnRows(sqlmock.NewRows(userColumns).AddRow(userID, "Alice", "alice@example.com", userCreatedAt, userCreatedAt))
+
+		u, err := a.GetUserByEmail(t.Context(), "alice@example.com")
+		require.NoError(t, err)
+		assert.Equal(t, userID, u.ID)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("mixed case", func(t *testing.T) {
+		userID := uuid.New()
+		mock.ExpectQuery(regexp.QuoteMeta(selectQuery)).
+			WithArgs("alice@example.com").
+			WillReturnRows(sqlmock.NewRows(userColumns).AddRow(userID, "Alice", "Alice@Example.com", userCreatedAt, userCreatedAt))
+
+		u, err := a.GetUserByEmail(t.Context(), "alice@example.com")
+		require.NoError(t, err)
+		assert.Equal(t, userID, u.ID)
+		assert.Equal(t, "Alice@Example.com", u.Email)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta(selectQuery)).
+			WithArgs("nobody@example.com").
+			WillReturnError(sql.ErrNoRows)
+
+		u, err := a.GetUserByEmail(t.Context(), "nobody@example.com")
+		require.ErrorIs(t, err, user.ErrNotFound)
+		assert.Nil(t, u)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGetUsersByIDs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	a := user.NewAccessor(db)
+
+	t.Run("fetches every ID in one query", func(t *testing.T) {
+		alice, bob, missing := uuid.New(), uuid.New(), uuid.New()
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = ANY($1) ORDER BY name, id`)).
+			WithArgs(pq.Array([]string{bob.String(), alice.String(), missing.String()})).
+			WillReturnRows(sqlmock.NewRows(userColumns).
+				AddRow(alice, "Alice", "alice@example.com", userCreatedAt, userCreatedAt).
+				AddRow(bob, "Bob", "bob@example.com", userCreatedAt, userCreatedAt))
+
+		users, err := a.GetUsersByIDs(t.Context(), []uuid.UUID{bob, alice, missing})
+		require.NoError(t, err)
+		require.Len(t, users, 2)
+		assert.Equal(t, alice, users[0].ID)
+		assert.Equal(t, bob, users[1].ID)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("empty input does not query", func(t *testing.T) {
+		users, err := a.GetUsersByIDs(t.Context(), nil)
+		require.NoError(t, err)
+		assert.Equal(t, []user.User{}, users)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPatchUser(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	a := user.NewAccessor(db)
+	userID := uuid.New()
+	now := userCreatedAt.Add(48 * time.Hour)
+
+	t.Run("patch name only", func(t *testing.T) {
+		name := "Alice Smith"
+		mock.ExpectQuery(regexp.QuoteMeta(`UPDATE users SET name = $1, updated_at = $2 WHERE id = $3 RETURNING id, name, email, created_at, updated_at`)).
+			WithArgs(name, now, userID).
+			WillReturnRows(sqlmock.NewRows(userColumns).AddRow(userID, name, "alice@example.com", userCreatedAt, now))
+
+		u, err := a.PatchUser(t.Context(), userID, user.UserPatch{Name: &name}, now)
+		require.NoError(t, err)
+		assert.Equal(t, name, u.Name)
+		assert.Equal(t, "alice@example.com", u.Email)
+		assert.Equal(t, userCreatedAt, u.CreatedAt)
+		assert.Equal(t, now, u.UpdatedAt, "update bumps updated_at")
+
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("patch email only", func(t *testing.T) {
+		email := "alice.smith@example.com"
+		mock.ExpectQuery(regexp.QuoteMeta(`UPDATE users SET email = $1, updated_at = $2 WHERE id = $3 RETURNING id, name, email, created_at, updated_at`)).
+			WithArgs(email, now, userID).
+			WillReturnRows(sqlmock.NewRows(userColumns).AddRow(userID, "Alice", email, userCreatedAt, now))
+
+		u, err := a.PatchUser(t.Context(), userID, user.UserPatch{Email: &email}, now)
+		require.NoError(t, err)
+		assert.Equal(t, email, u.Email)
+
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("patch both", func(t *testing.T) {
+		name := "Alice"
+		email := "alice@example.com"
+		mock.ExpectQuery(regexp.QuoteMeta(`UPDATE users SET name = $1, email = $2, updated_at = $3 WHERE id = $4 RETURNING id, name, email, created_at, updated_at`)).
+			WithArgs(name, email, now, userID).
+			WillReturnRows(sqlmock.NewRows(userColumns).AddRow(userID, name, email, userCreatedAt, now))
+
+		_, err := a.PatchUser(t.Context(), userID, user.UserPatch{Name: &name, Email: &email}, now)
+		require.NoError(t, err)
+
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("patch nothing", func(t *testing.T) {
+		_, err := a.PatchUser(t.Context(), userID, user.UserPatch{}, now)
+		require.Error(t, err)
+	})
+
+	t.Run("patch invalid email", func(t *testing.T) {
+		email := "not-an-email"
+		_, err := a.PatchUser(t.Context(), userID, user.UserPatch{Email: &email}, now)
+		require.Error(t, err)
+	})
+}
+
+func TestCancelledContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	a := user.NewAccessor(db)
+	ctx := database.WithCancelledContext(t.Context())
+
+	t.Run("get users", func(t *testing.T) {
+		_, err := a.GetUsers(ctx)
+		require.ErrorIs(t, err, context.Canceled)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("create user slots", func(t *testing.T) {
+		start := time.Now().Add(time.Hour)
+		_, err := a.CreateUserSlots(ctx, uuid.New(), []user.Slot{{StartTime: start, EndTime: start.Add(time.Hour)}})
+		require.ErrorIs(t, err, context.Canceled)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestTransientErrors(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	a := user.NewAccessor(db).WithRetry(database.Retry{Attempts: 2})
+	userID := uuid.New()
+	serializationFailure := &pq.Error{Code: "40001"}
+
+	t.Run("get user retried once", func(t *testing.T) {
+		query := regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)
+		mock.ExpectQuery(query).WithArgs(userID).WillReturnError(serializationFailure)
+		mock.ExpectQuery(query).WithArgs(userID).
+			WillReturnRows(sqlmock.NewRows(userColumns).AddRow(userID, "Alice", "alice@example.com", userCreatedAt, userCreatedAt))
+
+		u, err := a.GetUser(t.Context(), userID)
+		require.NoError(t, err)
+		assert.Equal(t, "Alice", u.Name)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("get users starts over", func(t *testing.T) {
+		query := regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users`)
+		mock.ExpectQuery(query).
+			WillReturnRows(sqlmock.NewRows(userColumns).
+				AddRow(userID, "Alice", "alice@example.com", userCreatedAt, userCreatedAt).
+				RowError(0, driver.ErrBadConn))
+		mock.ExpectQuery(query).
+			WillReturnRows(sqlmock.NewRows(userColumns).AddRow(userID, "Alice", "alice@example.com", userCreatedAt, userCreatedAt))
+
+		users, err := a.GetUsers(t.Context())
+		require.NoError(t, err)
+		assert.Len(t, users, 1)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("gives up after the last attempt", func(t *testing.T) {
+		query := regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)
+		mock.ExpectQuery(query).WithArgs(userID).WillReturnError(serializationFailure)
+		mock.ExpectQuery(query).WithArgs(userID).WillReturnError(serializationFailure)
+
+		_, err := a.GetUser(t.Context(), userID)
+		require.ErrorIs(t, err, serializationFailure)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("other errors are not retried", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+			WithArgs(userID).
+			WillReturnError(&pq.Error{Code: "42P01"})
+
+		_, err := a.GetUser(t.Context(), userID)
+		require.Error(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestErrorContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	a := user.NewAccessor(db)
+	userID := uuid.New()
+
+	t.Run("names the operation and user", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT start_time, end_time FROM users_availability WHERE user_id = $1`)).
+			WithArgs(userID).
+			WillReturnError(sql.ErrConnDone)
+
+		_, err := a.GetUserSlots(t.Context(), userID)
+		require.ErrorIs(t, err, sql.ErrConnDone)
+		assert.ErrorContains(t, err, "user.get_user_slots "+userID.String()+": query: ")
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("keeps not found detectable", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1`)).
+			WithArgs(userID).
+			WillReturnError(sql.ErrNoRows)
+
+		_, err := a.GetUser(t.Context(), userID)
+		require.ErrorIs(t, err, user.ErrNotFound)
+		assert.ErrorContains(t, err, "user.get_user "+userID.String())
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("never logs the email", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE lower(email) = lower($1)`)).
+			WithArgs("alice@example.com").
+			WillReturnError(sql.ErrConnDone)
+
+		_, err := a.GetUserByEmail(t.Context(), "alice@example.com")
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "user.get_user_by_email: scan: ")
+		assert.NotContains(t, err.Error(), "alice@example.com")
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
 func TestCreateUserSlots(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	require.NoError(t, err)
@@ -85,9 +499,16 @@ func TestCreateUserSlots(t *testing.T) {
 		{StartTime: startTime, EndTime: endTime},
 		{StartTime: startTime.Add(24 * time.Hour), EndTime: endTime.Add(24 * time.Hour)},
 	}
+	slotColumns := []string{"start_time", "end_time"}
 
 	t.Run("create user slots successfully", func(t *testing.T) {
 		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`SELECT 1 FROM users WHERE id = $1 FOR UPDATE`)).
+			WithArgs(userID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT start_time, end_time FROM users_availability WHERE user_id = $1 ORDER BY start_time FOR UPDATE`)).
+			WithArgs(userID).
+			WillReturnRows(sqlmock.NewRows(slotColumns))
 
 		insertQuery := `INSERT INTO users_availability (user_id, start_time, end_time) VALUES ($1, $2, $3)`
 		mock.ExpectExec(regexp.QuoteMeta(insertQuery)).
@@ -108,6 +529,12 @@ func TestCreateUserSlots(t *testing.T) {
 
 	t.Run("create user slots - transaction rollback on error", func(t *testing.T) {
 		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`SELECT 1 FROM users WHERE id = $1 FOR UPDATE`)).
+			WithArgs(userID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT start_time, end_time FROM users_availability WHERE user_id = $1 ORDER BY start_time FOR UPDATE`)).
+			WithArgs(userID).
+			WillReturnRows(sqlmock.NewRows(slotColumns))
 
 		insertQuery := `INSERT INTO users_availability (user_id, start_time, end_time) VALUES ($1, $2, $3)`
 		mock.ExpectExec(regexp.QuoteMeta(insertQuery)).
@@ -122,6 +549,191 @@ func TestCreateUserSlots(t *testing.T) {
 
 		require.NoError(t, mock.ExpectationsWereMet())
 	})
+
+	t.Run("create user slots - overlap rejected", func(t *testing.T) {
+		existing := user.Slot{StartTime: startTime.Add(-time.Hour), EndTime: startTime.Add(time.Hour)}
+		untouched := user.Slot{StartTime: startTime.Add(-3 * time.Hour), EndTime: startTime.Add(-time.Hour)}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`SELECT 1 FROM users WHERE id = $1 FOR UPDATE`)).
+			WithArgs(userID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT start_time, end_time FROM users_availability WHERE user_id = $1 ORDER BY start_time FOR UPDATE`)).
+			WithArgs(userID).
+			WillReturnRows(sqlmock.NewRows(slotColumns).
+				AddRow(untouched.StartTime, untouched.EndTime).
+				AddRow(existing.StartTime, existing.EndTime))
+		mock.ExpectRollback()
+
+		createdSlots, err := a.CreateUserSlots(t.Context(), userID, slots)
+		var conflictErr *user.SlotConflictError
+		require.ErrorAs(t, err, &conflictErr)
+		assert.Equal(t, []user.Slot{existing}, conflictErr.Conflicts)
+		assert.Nil(t, createdSlots)
+
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("create user slots - overlap merged", func(t *testing.T) {
+		existing := user.Slot{StartTime: startTime.Add(-time.Hour), EndTime: startTime.Add(time.Hour)}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`SELECT 1 FROM users WHERE id = $1 FOR UPDATE`)).
+			WithArgs(userID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT start_time, end_time FROM users_availability WHERE user_id = $1 ORDER BY start_time FOR UPDATE`)).
+			WithArgs(userID).
+			WillReturnRows(sqlmock.NewRows(slotColumns).AddRow(existing.StartTime, existing.EndTime))
+		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM users_availability WHERE user_id = $1 AND start_time = $2 AND end_time = $3`)).
+			WithArgs(userID, existing.StartTime, existing.EndTime).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		insertQuery := `INSERT INTO users_availability (user_id, start_time, end_time) VALUES ($1, $2, $3)`
+		mock.ExpectExec(regexp.QuoteMeta(insertQuery)).
+			WithArgs(userID, existing.StartTime, slots[0].EndTime).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta(insertQuery)).
+			WithArgs(userID, slots[1].StartTime, slots[1].EndTime).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		createdSlots, err := a.WithMergeSlots().CreateUserSlots(t.Context(), userID, slots)
+		require.NoError(t, err)
+		assert.Equal(t, []user.Slot{
+			{StartTime: existing.StartTime, EndTime: slots[0].EndTime},
+			slots[1],
+		}, createdSlots)
+
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	// A concurrent call stores the second slot between the overlap check and the insert.
+	t.Run("create user slots - concurrent duplicate rejected", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`SELECT 1 FROM users WHERE id = $1 FOR UPDATE`)).
+			WithArgs(userID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT start_time, end_time FROM users_availability WHERE user_id = $1 ORDER BY start_time FOR UPDATE`)).
+			WithArgs(userID).
+			WillReturnRows(sqlmock.NewRows(slotColumns))
+		insertQuery := `INSERT INTO users_availability (user_id, start_time, end_time) VALUES ($1, $2, $3)`
+		mock.ExpectExec(regexp.QuoteMeta(insertQuery)).
+			WithArgs(userID, slots[0].StartTime, slots[0].EndTime).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta(insertQuery)).
+			WithArgs(userID, slots[1].StartTime, slots[1].EndTime).
+			WillReturnError(&pq.Error{Code: "23505", Message: "duplicate key value violates unique constraint"})
+		mock.ExpectRollback()
+
+		createdSlots, err := a.CreateUserSlots(t.Context(), userID, slots)
+		var duplicateErr *user.DuplicateSlotError
+		require.ErrorAs(t, err, &duplicateErr)
+		assert.Equal(t, slots[1], duplicateErr.Slot)
+		assert.Nil(t, createdSlots)
+
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("create user slots - concurrent duplicate skipped", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`SELECT 1 FROM users WHERE id = $1 FOR UPDATE`)).
+			WithArgs(userID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT start_time, end_time FROM users_availability WHERE user_id = $1 ORDER BY start_time FOR UPDATE`)).
+			WithArgs(userID).
+			WillReturnRows(sqlmock.NewRows(slotColumns))
+		insertQuery := `INSERT INTO users_availability (user_id, start_time, end_time) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`
+		mock.ExpectExec(regexp.QuoteMeta(insertQuery)).
+			WithArgs(userID, slots[0].StartTime, slots[0].EndTime).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta(insertQuery)).
+			WithArgs(userID, slots[1].StartTime, slots[1].EndTime).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		createdSlots, err := a.WithSkipDuplicateSlots().CreateUserSlots(t.Context(), userID, slots)
+		require.NoError(t, err)
+		assert.Equal(t, []user.Slot{slots[0]}, createdSlots)
+
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGetNextAvailableSlot(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	a := user.NewAccessor(db)
+	userID := uuid.New()
+	after := time.Date(2030, 1, 7, 9, 0, 0, 0, time.UTC)
+	query := regexp.QuoteMeta(`SELECT start_time, end_time FROM users_availability
+	WHERE user_id = $1 AND start_time >= $2 AND end_time - start_time >= make_interval(hours => $3)
+	ORDER BY start_time
+	LIMIT 1`)
+
+	t.Run("earliest fitting slot", func(t *testing.T) {
+		slot := user.Slot{StartTime: after.Add(3 * time.Hour), EndTime: after.Add(5 * time.Hour)}
+		mock.ExpectQuery(query).
+			WithArgs(userID, after, 2).
+			WillReturnRows(sqlmock.NewRows([]string{"start_time", "end_time"}).AddRow(slot.StartTime, slot.EndTime))
+
+		next, err := a.GetNextAvailableSlot(t.Context(), userID, after, 2)
+		require.NoError(t, err)
+		assert.Equal(t, &slot, next)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("none", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs(userID, after, 2).
+			WillReturnRows(sqlmock.NewRows([]string{"start_time", "end_time"}))
+
+		next, err := a.GetNextAvailableSlot(t.Context(), userID, after, 2)
+		require.NoError(t, err)
+		assert.Nil(t, next)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestReplaceUserSlots(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	a := user.NewAccessor(db)
+	userID := uuid.New()
+	startTime := time.Date(2030, 1, 7, 9, 0, 0, 0, time.UTC)
+	slots := []user.Slot{
+		{StartTime: startTime, EndTime: startTime.Add(2 * time.Hour)},
+		{StartTime: startTime.Add(24 * time.Hour), EndTime: startTime.Add(26 * time.Hour)},
+	}
+	deleteQuery := regexp.QuoteMeta(`DELETE FROM users_availability WHERE user_id = $1`)
+	insertQuery := regexp.QuoteMeta(`INSERT INTO users_availability (user_id, start_time, end_time) VALUES ($1, $2, $3)`)
+
+	t.Run("deletes then inserts in one transaction", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(deleteQuery).WithArgs(userID).WillReturnResult(sqlmock.NewResult(0, 3))
+		mock.ExpectExec(insertQuery).WithArgs(userID, slots[0].StartTime, slots[0].EndTime).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(insertQuery).WithArgs(userID, slots[1].StartTime, slots[1].EndTime).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		replaced, err := a.ReplaceUserSlots(t.Context(), userID, slots)
+		require.NoError(t, err)
+		assert.Equal(t, slots, replaced)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("keeps the old slots when an insert fails", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(deleteQuery).WithArgs(userID).WillReturnResult(sqlmock.NewResult(0, 3))
+		mock.ExpectExec(insertQuery).WithArgs(userID, slots[0].StartTime, slots[0].EndTime).WillReturnError(sql.ErrConnDone)
+		mock.ExpectRollback()
+
+		replaced, err := a.ReplaceUserSlots(t.Context(), userID, slots)
+		require.ErrorIs(t, err, sql.ErrConnDone)
+		assert.Nil(t, replaced)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
 }
 
 func TestDeleteUserSlots(t *testing.T) {
@@ -157,6 +769,30 @@ func TestDeleteUserSlots(t *testing.T) {
 	})
 }
 
+func TestDeleteSlotsInRange(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	a := user.NewAccessor(db)
+	from := time.Date(2030, 12, 25, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM users_availability WHERE start_time >= $1 AND end_time <= $2`)).
+		WithArgs(from, to).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	n, err := a.DeleteSlotsInRange(t.Context(), from, to)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), n)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+const recurringQuery = `FROM users_recurring_availability r`
+
+var recurringColumns = []string{"id", "name", "email", "created_at", "updated_at", "weekday", "start_minute", "end_minute", "valid_from", "valid_until"}
+
 func TestGetUsersForSlot(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	require.NoError(t, err)
@@ -171,24 +807,27 @@ func TestGetUsersForSlot(t *testing.T) {
 
 	user1ID := uuid.New()
 	user2ID := uuid.New()
-	user1 := user.User{ID: user1ID, Name: "User 1", Email: "user1@example.com"}
-	user2 := user.User{ID: user2ID, Name: "User 2", Email: "user2@example.com"}
+	user1 := user.User{ID: user1ID, Name: "User 1", Email: "user1@example.com", CreatedAt: userCreatedAt, UpdatedAt: userCreatedAt}
+	user2 := user.User{ID: user2ID, Name: "User 2", Email: "user2@example.com", CreatedAt: userCreatedAt, UpdatedAt: userCreatedAt}
 
 	t.Run("get users for slot successfully", func(t *testing.T) {
 		// Verify the SQL query matches the implementation
-		query := `SELECT users.id, users.name, users.email
+		query := `SELECT users.id, users.name, users.email, users.created_at, users.updated_at
 	FROM users_availability
 	JOIN users ON users_availability.user_id = users.id
-	WHERE users_availability.start_time >= $1 AND users_availability.end_time <= $2 AND users_availability.end_time - users_availability.start_time > make_interval(hours => $3)
+	WHERE users_availability.start_time <= $1 AND users_availability.end_time >= $2 AND users_availability.end_time - users_availability.start_time >= make_interval(hours => $3) AND users.active
 	ORDER BY users.name`
 
-		rows := sqlmock.NewRows([]string{"id", "name", "email"}).
-			AddRow(user1ID, user1.Name, user1.Email).
-			AddRow(user2ID, user2.Name, user2.Email)
+		rows := sqlmock.NewRows(userColumns).
+			AddRow(user1ID, user1.Name, user1.Email, userCreatedAt, userCreatedAt).
+			AddRow(user2ID, user2.Name, user2.Email, userCreatedAt, userCreatedAt)
 
 		mock.ExpectQuery(regexp.QuoteMeta(query)).
 			WithArgs(startTime, endTime, durationHours).
 			WillReturnRows(rows)
+		mock.ExpectQuery(regexp.QuoteMeta(recurringQuery)).
+			WithArgs(startTime, endTime).
+			WillReturnRows(sqlmock.NewRows(recurringColumns))
 
 		users, err := a.GetUsersForSlot(t.Context(), slot, durationHours)
 		require.NoError(t, err)
@@ -204,17 +843,20 @@ func TestGetUsersForSlot(t *testing.T) {
 	})
 
 	t.Run("get users for slot - no users available", func(t *testing.T) {
-		query := `SELECT users.id, users.name, users.email
+		query := `SELECT users.id, users.name, users.email, users.created_at, users.updated_at
 	FROM users_availability
 	JOIN users ON users_availability.user_id = users.id
-	WHERE users_availability.start_time >= $1 AND users_availability.end_time <= $2 AND users_availability.end_time - users_availability.start_time > make_interval(hours => $3)
+	WHERE users_availability.start_time <= $1 AND users_availability.end_time >= $2 AND users_availability.end_time - users_availability.start_time >= make_interval(hours => $3) AND users.active
 	ORDER BY users.name`
 
-		rows := sqlmock.NewRows([]string{"id", "name", "email"})
+		rows := sqlmock.NewRows(userColumns)
 
 		mock.ExpectQuery(regexp.QuoteMeta(query)).
 			WithArgs(startTime, endTime, durationHours).
 			WillReturnRows(rows)
+		mock.ExpectQuery(regexp.QuoteMeta(recurringQuery)).
+			WithArgs(startTime, endTime).
+			WillReturnRows(sqlmock.NewRows(recurringColumns))
 
 		users, err := a.GetUsersForSlot(t.Context(), slot, durationHours)
 		require.NoError(t, err)
@@ -224,10 +866,10 @@ func TestGetUsersForSlot(t *testing.T) {
 	})
 
 	t.Run("get users for slot - query error", func(t *testing.T) {
-		query := `SELECT users.id, users.name, users.email
+		query := `SELECT users.id, users.name, users.email, users.created_at, users.updated_at
 	FROM users_availability
 	JOIN users ON users_availability.user_id = users.id
-	WHERE users_availability.start_time >= $1 AND users_availability.end_time <= $2 AND users_availability.end_time - users_availability.start_time > make_interval(hours => $3)
+	WHERE users_availability.start_time <= $1 AND users_availability.end_time >= $2 AND users_availability.end_time - users_availability.start_time >= make_interval(hours => $3) AND users.active
 	ORDER BY users.name`
 
 		mock.ExpectQuery(regexp.QuoteMeta(query)).
@@ -242,15 +884,15 @@ func TestGetUsersForSlot(t *testing.T) {
 	})
 
 	t.Run("get users for slot - scan error", func(t *testing.T) {
-		query := `SELECT users.id, users.name, users.email
+		query := `SELECT users.id, users.name, users.email, users.created_at, users.updated_at
 	FROM users_availability
 	JOIN users ON users_availability.user_id = users.id
-	WHERE users_availability.start_time >= $1 AND users_availability.end_time <= $2 AND users_availability.end_time - users_availability.start_time > make_interval(hours => $3)
+	WHERE users_availability.start_time <= $1 AND users_availability.end_time >= $2 AND users_availability.end_time - users_availability.start_time >= make_interval(hours => $3) AND users.active
 	ORDER BY users.name`
 
 		// Return invalid data that will cause scan error
-		rows := sqlmock.NewRows([]string{"id", "name", "email"}).
-			AddRow("invalid-uuid", user1.Name, user1.Email)
+		rows := sqlmock.NewRows(userColumns).
+			AddRow("invalid-uuid", user1.Name, user1.Email, userCreatedAt, userCreatedAt)
 
 		mock.ExpectQuery(regexp.QuoteMeta(query)).
 			WithArgs(startTime, endTime, durationHours).
@@ -262,4 +904,678 @@ func TestGetUsersForSlot(t *testing.T) {
 
 		require.NoError(t, mock.ExpectationsWereMet())
 	})
+
+	t.Run("availability exactly as long as the duration", func(t *testing.T) {
+		// A Monday 10:00-12:00 rule for a two hour slot at the same time: the bounds and the length are
+		// all equal, and each comparison is inclusive, like the >= in the one-off query.
+		validFrom := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+		slot := user.Slot{StartTime: time.Date(2030, 1, 14, 10, 0, 0, 0, time.UTC), EndTime: time.Date(2030, 1, 14, 12, 0, 0, 0, time.UTC)}
+		for _, tc := range []struct {
+			name          string
+			durationHours int
+			expected      []user.User
+		}{
+			{name: "equal", durationHours: 2, expected: []user.User{user1}},
+			{name: "one hour short", durationHours: 3, expected: nil},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				mock.ExpectQuery(regexp.QuoteMeta(`users_availability.end_time - users_availability.start_time >= make_interval(hours => $3)`)).
+					WithArgs(slot.StartTime, slot.EndTime, tc.durationHours).
+					WillReturnRows(sqlmock.NewRows(userColumns))
+				mock.ExpectQuery(regexp.QuoteMeta(recurringQuery)).
+					WithArgs(slot.StartTime, slot.EndTime).
+					WillReturnRows(sqlmock.NewRows(recurringColumns).
+						AddRow(user1ID, user1.Name, user1.Email, userCreatedAt, userCreatedAt, 1, 10*60, 12*60, validFrom, nil))
+
+				users, err := a.GetUsersForSlot(t.Context(), slot, tc.durationHours)
+				require.NoError(t, err)
+				assert.Equal(t, tc.expected, users)
+
+				require.NoError(t, mock.ExpectationsWereMet())
+			})
+		}
+	})
+}
+
+func TestInactiveUsers(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	a := user.NewAccessor(db)
+	now := time.Date(2030, 1, 1, 9, 0, 0, 0, time.UTC)
+	slot := user.Slot{StartTime: time.Date(2030, 1, 14, 10, 0, 0, 0, time.UTC), EndTime: time.Date(2030, 1, 14, 12, 0, 0, 0, time.UTC)}
+	inactive := user.User{ID: uuid.New(), Name: "Former", Email: "former@example.com", CreatedAt: userCreatedAt, UpdatedAt: now}
+
+	t.Run("deactivate", func(t *testing.T) {
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE users SET active = $1, updated_at = $2 WHERE id = $3`)).
+			WithArgs(false, now, inactive.ID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		require.NoError(t, a.SetUserActive(t.Context(), inactive.ID, false, now))
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("deactivate unknown user", func(t *testing.T) {
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE users SET active = $1`)).
+			WithArgs(false, now, inactive.ID).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		require.ErrorIs(t, a.SetUserActive(t.Context(), inactive.ID, false, now), user.ErrNotFound)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("omitted from availability", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta(`make_interval(hours => $3) AND users.active`)).
+			WithArgs(slot.StartTime, slot.EndTime, 2).
+			WillReturnRows(sqlmock.NewRows(userColumns))
+		mock.ExpectQuery(regexp.QuoteMeta(`r.valid_until >= $1) AND users.active`)).
+			WithArgs(slot.StartTime, slot.EndTime).
+			WillReturnRows(sqlmock.NewRows(recurringColumns))
+
+		users, err := a.GetUsersForSlot(t.Context(), slot, 2)
+		require.NoError(t, err)
+		assert.Empty(t, users)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("omitted from listings", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, created_at, updated_at FROM users WHERE users.active`)).
+			WillReturnRows(sqlmock.NewRows(userColumns))
+
+		users, err := a.GetUsers(t.Context())
+		require.NoError(t, err)
+		assert.Empty(t, users)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("omitted from users without availability", func(t *testing.T) {
+		mock.ExpectQuery(`WHERE NOT EXISTS[\s\S]*\) AND users\.active$`).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectQuery(`WHERE NOT EXISTS[\s\S]*users_recurring_availability\.user_id = users\.id\)$`).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+
+		count, err := a.CountUsersWithoutAvailability(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+		count, err = a.WithInactive().CountUsersWithoutAvailability(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("listed with WithInactive", func(t *testing.T) {
+		mock.ExpectQuery(`^SELECT id, name, email, created_at, updated_at FROM users ORDER BY name, id$`).
+			WillReturnRows(sqlmock.NewRows(userColumns).AddRow(inactive.ID, inactive.Name, inactive.Email, inactive.CreatedAt, inactive.UpdatedAt))
+
+		users, err := a.WithInactive().GetUsers(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, []user.User{inactive}, users)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("still fetchable by ID", func(t *testing.T) {
+		mock.ExpectQuery(`^SELECT id, name, email, created_at, updated_at FROM users WHERE id = \$1$`).
+			WithArgs(inactive.ID).
+			WillReturnRows(sqlmock.NewRows(userColumns).AddRow(inactive.ID, inactive.Name, inactive.Email, inactive.CreatedAt, inactive.UpdatedAt))
+
+		u, err := a.GetUser(t.Context(), inactive.ID)
+		require.NoError(t, err)
+		assert.Equal(t, &inactive, u)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRecurrenceExpand(t *testing.T) {
+	// Monday 2030-01-07 to Sunday 2030-01-20.
+	from := time.Date(2030, 1, 7, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2030, 1, 20, 23, 59, 0, 0, time.UTC)
+
+	t.Run("weekly rule across two weeks", func(t *testing.T) {
+		r := user.Recurrence{Weekday: time.Wednesday, StartMinute: 9 * 60, EndMinute: 17 * 60, ValidFrom: from}
+
+		slots := r.Expand(from, to)
+		require.Len(t, slots, 2)
+		assert.Equal(t, time.Date(2030, 1, 9, 9, 0, 0, 0, time.UTC), slots[0].StartTime)
+		assert.Equal(t, time.Date(2030, 1, 9, 17, 0, 0, 0, time.UTC), slots[0].EndTime)
+		assert.Equal(t, time.Date(2030, 1, 16, 9, 0, 0, 0, time.UTC), slots[1].StartTime)
+		for _, s := range slots {
+			assert.Equal(t, time.Wednesday, s.StartTime.Weekday())
+		}
+	})
+
+	t.Run("respects the validity window", func(t *testing.T) {
+		until := time.Date(2030, 1, 10, 0, 0, 0, 0, time.UTC)
+		r := user.Recurrence{Weekday: time.Monday, StartMinute: 9 * 60, EndMinute: 10 * 60, ValidFrom: from, ValidUntil: &until}
+
+		slots := r.Expand(from, to)
+		require.Len(t, slots, 1)
+		assert.Equal(t, 7, slots[0].StartTime.Day())
+	})
+
+	t.Run("invalid rule", func(t *testing.T) {
+		r := user.Recurrence{Weekday: time.Monday, StartMinute: 10 * 60, EndMinute: 9 * 60, ValidFrom: from}
+		require.Error(t, r.Validate())
+	})
+}
+
+func TestGetUsersRecurrences(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	a := user.NewAccessor(db)
+	alice, bob := uuid.New(), uuid.New()
+	validFrom := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	validUntil := time.Date(2030, 6, 30, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT user_id, weekday, start_minute, end_minute, valid_from, valid_until FROM users_recurring_availability WHERE user_id = ANY($1)`)).
+		WithArgs(pq.Array([]string{alice.String(), bob.String()})).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "weekday", "start_minute", "end_minute", "valid_from", "valid_until"}).
+			AddRow(alice, 1, 540, 1020, validFrom, nil).
+			AddRow(alice, 3, 540, 720, validFrom, validUntil))
+
+	recurrences, err := a.GetUsersRecurrences(t.Context(), []uuid.UUID{alice, bob})
+	require.NoError(t, err)
+	assert.Equal(t, map[uuid.UUID][]user.Recurrence{alice: {
+		{Weekday: time.Monday, StartMinute: 540, EndMinute: 1020, ValidFrom: validFrom},
+		{Weekday: time.Wednesday, StartMinute: 540, EndMinute: 720, ValidFrom: validFrom, ValidUntil: &validUntil},
+	}}, recurrences)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetUsersForSlotPrepared(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	stmts := database.NewStatements(db)
+	a := user.NewAccessor(db).WithStatements(stmts)
+	start := time.Date(2030, 1, 2, 9, 0, 0, 0, time.UTC)
+	slot := user.Slot{StartTime: start, EndTime: start.Add(2 * time.Hour)}
+	alice := user.User{ID: uuid.New(), Name: "Alice", Email: "alice@example.com", CreatedAt: userCreatedAt, UpdatedAt: userCreatedAt}
+
+	// The statement is prepared by the first lookup and reused by the second.
+	prepared := mock.ExpectPrepare(regexp.QuoteMeta(`SELECT users.id, users.name, users.email, users.created_at, users.updated_at
+	FROM users_availability`))
+	for range 2 {
+		prepared.ExpectQuery().
+			WithArgs(slot.StartTime, slot.EndTime, 2).
+			WillReturnRows(sqlmock.NewRows(userColumns).AddRow(alice.ID, alice.Name, alice.Email, userCreatedAt, userCreatedAt))
+		mock.ExpectQuery(regexp.QuoteMeta(recurringQuery)).
+			WithArgs(slot.StartTime, slot.EndTime).
+			WillReturnRows(sqlmock.NewRows(recurringColumns))
+	}
+	prepared.WillBeClosed()
+
+	for range 2 {
+		users, err := a.GetUsersForSlot(t.Context(), slot, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []user.User{alice}, users)
+	}
+	require.NoError(t, stmts.Close())
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetUsersForSlotRecurring(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	a := user.NewAccessor(db)
+	validFrom := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	oneOff := user.User{ID: uuid.New(), Name: "Bob", Email: "bob@example.com", CreatedAt: userCreatedAt, UpdatedAt: userCreatedAt}
+	monday := user.User{ID: uuid.New(), Name: "Alice", Email: "alice@example.com", CreatedAt: userCreatedAt, UpdatedAt: userCreatedAt}
+	tuesday := user.User{ID: uuid.New(), Name: "Carol", Email: "carol@example.com", CreatedAt: userCreatedAt, UpdatedAt: userCreatedAt}
+
+	for _, tc := range []struct {
+		name     string
+		slot     user.Slot
+		expected []user.User
+	}{
+		{
+			name:     "matches on the rule's weekday",
+			slot:     user.Slot{StartTime: time.Date(2030, 1, 14, 10, 0, 0, 0, time.UTC), EndTime: time.Date(2030, 1, 14, 12, 0, 0, 0, time.UTC)},
+			expected: []user.User{monday, oneOff},
+		},
+		{
+			name:     "ignores other weekdays",
+			slot:     user.Slot{StartTime: time.Date(2030, 1, 16, 10, 0, 0, 0, time.UTC), EndTime: time.Date(2030, 1, 16, 12, 0, 0, 0, time.UTC)},
+			expected: []user.User{oneOff},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mock.ExpectQuery(regexp.QuoteMeta(`FROM users_availability`)).
+				WithArgs(tc.slot.StartTime, tc.slot.EndTime, 2).
+				WillReturnRows(sqlmock.NewRows(userColumns).AddRow(oneOff.ID, oneOff.Name, oneOff.Email, userCreatedAt, userCreatedAt))
+			mock.ExpectQuery(regexp.QuoteMeta(recurringQuery)).
+				WithArgs(tc.slot.StartTime, tc.slot.EndTime).
+				WillReturnRows(sqlmock.NewRows(recurringColumns).
+					AddRow(monday.ID, monday.Name, monday.Email, userCreatedAt, userCreatedAt, 1, 9*60, 17*60, validFrom, nil).
+					AddRow(tuesday.ID, tuesday.Name, tuesday.Email, userCreatedAt, userCreatedAt, 2, 9*60, 17*60, validFrom, nil))
+
+			users, err := a.GetUsersForSlot(t.Context(), tc.slot, 2)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, users)
+
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetUsersForSlotOverlap(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	a := user.NewAccessor(db)
+	validFrom := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	alice := user.User{ID: uuid.New(), Name: "Alice", Email: "alice@example.com", CreatedAt: userCreatedAt, UpdatedAt: userCreatedAt}
+
+	// Alice is free on Mondays 09:00-11:00, the slot is Monday 10:00-12:00 and lasts one hour:
+	// her window does not contain the slot but shares a full hour with it.
+	slot := user.Slot{StartTime: time.Date(2030, 1, 14, 10, 0, 0, 0, time.UTC), EndTime: time.Date(2030, 1, 14, 12, 0, 0, 0, time.UTC)}
+	recurringRows := func() *sqlmock.Rows {
+		return sqlmock.NewRows(recurringColumns).AddRow(alice.ID, alice.Name, alice.Email, userCreatedAt, userCreatedAt, 1, 9*60, 11*60, validFrom, nil)
+	}
+
+	t.Run("contain", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta(`WHERE users_availability.start_time <= $1 AND users_availability.end_time >= $2`)).
+			WithArgs(slot.StartTime, slot.EndTime, 1).
+			WillReturnRows(sqlmock.NewRows(userColumns))
+		mock.ExpectQuery(regexp.QuoteMeta(recurringQuery)).
+			WithArgs(slot.StartTime, slot.EndTime).
+			WillReturnRows(recurringRows())
+
+		users, err := a.GetUsersForSlot(t.Context(), slot, 1)
+		require.NoError(t, err)
+		assert.Empty(t, users)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("overlap", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta(`WHERE LEAST(users_availability.end_time, $2) - GREATEST(users_availability.start_time, $1) >= make_interval(hours => $3)`)).
+			WithArgs(slot.StartTime, slot.EndTime, 1).
+			WillReturnRows(sqlmock.NewRows(userColumns))
+		mock.ExpectQuery(regexp.QuoteMeta(recurringQuery)).
+			WithArgs(slot.StartTime, slot.EndTime).
+			WillReturnRows(recurringRows())
+
+		users, err := a.GetUsersForSlotOverlap(t.Context(), slot, 1)
+		require.NoError(t, err)
+		assert.Equal(t, []user.User{alice}, users)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("overlap shorter than the duration", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta(`WHERE LEAST(`)).
+			WithArgs(slot.StartTime, slot.EndTime, 2).
+			WillReturnRows(sqlmock.NewRows(userColumns))
+		mock.ExpectQuery(regexp.QuoteMeta(recurringQuery)).
+			WithArgs(slot.StartTime, slot.EndTime).
+			WillReturnRows(recurringRows())
+
+		users, err := a.GetUsersForSlotOverlap(t.Context(), slot, 2)
+		require.NoError(t, err)
+		assert.Empty(t, users)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("overlap with several windows", func(t *testing.T) {
+		// Bob is free 09:00-11:00 and 14:00-16:00, both overlap a 09:00-17:00 slot.
+		bob := user.User{ID: uuid.New(), Name: "Bob", Email: "bob@example.com", CreatedAt: userCreatedAt, UpdatedAt: userCreatedAt}
+		day := user.Slot{StartTime: time.Date(2030, 1, 14, 9, 0, 0, 0, time.UTC), EndTime: time.Date(2030, 1, 14, 17, 0, 0, 0, time.UTC)}
+		mock.ExpectQuery(regexp.QuoteMeta(`WHERE LEAST(`)).
+			WithArgs(day.StartTime, day.EndTime, 1).
+			WillReturnRows(sqlmock.NewRows(userColumns).
+				AddRow(bob.ID, bob.Name, bob.Email, userCreatedAt, userCreatedAt).
+				AddRow(bob.ID, bob.Name, bob.Email, userCreatedAt, userCreatedAt))
+		mock.ExpectQuery(regexp.QuoteMeta(recurringQuery)).
+			WithArgs(day.StartTime, day.EndTime).
+			WillReturnRows(sqlmock.NewRows(recurringColumns))
+
+		users, err := a.GetUsersForSlotOverlap(t.Context(), day, 1)
+		require.NoError(t, err)
+		assert.Equal(t, []user.User{bob}, users)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGetUsersForSlotUserIDs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	a := user.NewAccessor(db)
+	slot := user.Slot{StartTime: time.Date(2030, 1, 14, 10, 0, 0, 0, time.UTC), EndTime: time.Date(2030, 1, 14, 12, 0, 0, 0, time.UTC)}
+	alice := user.User{ID: uuid.New(), Name: "Alice", Email: "alice@example.com", CreatedAt: userCreatedAt, UpdatedAt: userCreatedAt}
+	bob := uuid.New()
+
+	t.Run("unfiltered", func(t *testing.T) {
+		mock.ExpectQuery(`make_interval\(hours => \$3\) AND users\.active\s+ORDER BY users\.name`).
+			WithArgs(slot.StartTime, slot.EndTime, 2).
+			WillReturnRows(sqlmock.NewRows(userColumns).AddRow(alice.ID, alice.Name, alice.Email, userCreatedAt, userCreatedAt))
+		mock.ExpectQuery(`r\.valid_until >= \$1\) AND users\.active\s+ORDER BY users\.name`).
+			WithArgs(slot.StartTime, slot.EndTime).
+			WillReturnRows(sqlmock.NewRows(recurringColumns))
+
+		users, err := a.GetUsersForSlot(t.Context(), slot, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []user.User{alice}, users)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("filtered", func(t *testing.T) {
+		ids := pq.Array([]string{alice.ID.String(), bob.String()})
+		mock.ExpectQuery(regexp.QuoteMeta(`make_interval(hours => $3) AND users.active AND users.id = ANY($4)`)).
+			WithArgs(slot.StartTime, slot.EndTime, 2, ids).
+			WillReturnRows(sqlmock.NewRows(userColumns).AddRow(alice.ID, alice.Name, alice.Email, userCreatedAt, userCreatedAt))
+		mock.ExpectQuery(regexp.QuoteMeta(`r.valid_until >= $1) AND users.active AND users.id = ANY($3)`)).
+			WithArgs(slot.StartTime, slot.EndTime, ids).
+			WillReturnRows(sqlmock.NewRows(recurringColumns))
+
+		users, err := a.GetUsersForSlot(t.Context(), slot, 2, alice.ID, bob)
+		require.NoError(t, err)
+		assert.Equal(t, []user.User{alice}, users)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPageUsers(t *testing.T) {
+	alice := user.User{ID: uuid.New(), Name: "Alice"}
+	bob := user.User{ID: uuid.New(), Name: "Bob"}
+	carol := user.User{ID: uuid.New(), Name: "Carol"}
+	users := []user.User{alice, bob, carol}
+
+	for _, tc := range []struct {
+		name          string
+		limit, offset int
+		expected      []user.User
+	}{
+		{name: "first page", limit: 2, expected: []user.User{alice, bob}},
+		{name: "last page", limit: 2, offset: 2, expected: []user.User{carol}},
+		{name: "past the end", limit: 2, offset: 3, expected: []user.User{}},
+		{name: "no limit", offset: 1, expected: []user.User{bob, carol}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, user.PageUsers(users, tc.limit, tc.offset))
+		})
+	}
+}
+
+func TestUserValidate(t *testing.T) {
+	var errs validation.Errors
+	require.ErrorAs(t, (&user.User{Email: "not-an-email"}).Validate(), &errs)
+	require.Len(t, errs, 2)
+	assert.Equal(t, validation.FieldError{Field: "name", Message: "name is required"}, errs[0])
+	assert.Equal(t, "email", errs[1].Field)
+
+	empty := ""
+	require.ErrorAs(t, (&user.UserPatch{Name: &empty, Email: &empty}).Validate(), &errs)
+	assert.Equal(t, []string{"name", "email"}, []string{errs[0].Field, errs[1].Field})
+
+	require.NoError(t, (&user.User{Name: "Alice", Email: "alice@example.com"}).Validate())
+}
+
+func TestSlotJSON(t *testing.T) {
+	slot := user.Slot{
+		StartTime: time.Date(2030, 1, 2, 9, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2030, 1, 2, 11, 0, 0, 0, time.UTC),
+	}
+
+	b, err := json.Marshal(slot)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"start_time":1893574800,"end_time":1893582000}`, string(b))
+
+	var decoded user.Slot
+	require.NoError(t, json.Unmarshal(b, &decoded))
+	assert.Equal(t, slot, decoded)
+}
+
+func TestGaps(t *testing.T) {
+	at := func(hour int) time.Time { return time.Date(2030, 1, 1, hour, 0, 0, 0, time.UTC) }
+	slot := func(start, end int) user.Slot { return user.Slot{StartTime: at(start), EndTime: at(end)} }
+
+	for _, tc := range []struct {
+		name  string
+		slots []user.Slot
+		want  []user.Slot
+	}{
+		{
+			name:  "no slots leaves the whole window free",
+			slots: nil,
+			want:  []user.Slot{slot(9, 17)},
+		},
+		{
+			name:  "fully covered window has no gaps",
+			slots: []user.Slot{slot(8, 12), slot(12, 18)},
+			want:  []user.Slot{},
+		},
+		{
+			name:  "gaps between, before and after slots",
+			slots: []user.Slot{slot(13, 14), slot(10, 11)},
+			want:  []user.Slot{slot(9, 10), slot(11, 13), slot(14, 17)},
+		},
+		{
+			name:  "overlapping slots and slots outside the window",
+			slots: []user.Slot{slot(6, 8), slot(10, 12), slot(11, 13), slot(16, 20), slot(21, 22)},
+			want:  []user.Slot{slot(9, 10), slot(13, 16)},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, user.Gaps(tc.slots, at(9), at(17)))
+		})
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	// 2030-01-07 is a Monday.
+	at := func(day, hour int) time.Time { return time.Date(2030, 1, day, hour, 0, 0, 0, time.UTC) }
+	slot := func(day, start, end int) user.Slot {
+		return user.Slot{StartTime: at(day, start), EndTime: at(day, end)}
+	}
+	weekdays := func(hours map[time.Weekday]float64) []user.WeekdayHours {
+		res := make([]user.WeekdayHours, 7)
+		for day := range res {
+			res[day] = user.WeekdayHours{Weekday: time.Weekday(day), Hours: hours[time.Weekday(day)]}
+		}
+		return res
+	}
+
+	for _, tc := range []struct {
+		name  string
+		slots []user.Slot
+		want  user.AvailabilitySummary
+	}{
+		{
+			name: "no slots is all zeros",
+			want: user.AvailabilitySummary{Weekdays: weekdays(nil)},
+		},
+		{
+			name:  "slots across two weekdays",
+			slots: []user.Slot{slot(7, 9, 12), slot(9, 13, 17), slot(14, 10, 11)},
+			want:  user.AvailabilitySummary{TotalHours: 8, Weekdays: weekdays(map[time.Weekday]float64{time.Monday: 4, time.Wednesday: 4})},
+		},
+		{
+			name:  "overlapping slots count once",
+			slots: []user.Slot{slot(7, 9, 12), slot(7, 11, 13)},
+			want:  user.AvailabilitySummary{TotalHours: 4, Weekdays: weekdays(map[time.Weekday]float64{time.Monday: 4})},
+		},
+		{
+			name:  "a slot crossing midnight counts towards both days",
+			slots: []user.Slot{{StartTime: at(7, 22), EndTime: at(8, 1)}},
+			want:  user.AvailabilitySummary{TotalHours: 3, Weekdays: weekdays(map[time.Weekday]float64{time.Monday: 2, time.Tuesday: 1})},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, user.Summarize(tc.slots))
+		})
+	}
+}
+
+func TestCalendar(t *testing.T) {
+	// 2030-01-07 is a Monday.
+	at := func(day, hour, minute int) time.Time { return time.Date(2030, 1, day, hour, minute, 0, 0, time.UTC) }
+	weekStart := at(7, 0, 0)
+	// marked lists the cells expected to be set, as day index and hour.
+	grid := func(marked ...[2]int) []user.CalendarDay {
+		days := make([]user.CalendarDay, 7)
+		for i := range days {
+			days[i] = user.CalendarDay{Date: weekStart.AddDate(0, 0, i).Format(time.DateOnly), Hours: make([]bool, 24)}
+		}
+		for _, cell := range marked {
+			days[cell[0]].Hours[cell[1]] = true
+		}
+		return days
+	}
+
+	for _, tc := range []struct {
+		name  string
+		slots []user.Slot
+		want  []user.CalendarDay
+	}{
+		{
+			name: "no slots is an empty grid",
+			want: grid(),
+		},
+		{
+			name:  "a slot spanning several cells marks each of them",
+			slots: []user.Slot{{StartTime: at(8, 9, 0), EndTime: at(8, 12, 0)}},
+			want:  grid([2]int{1, 9}, [2]int{1, 10}, [2]int{1, 11}),
+		},
+		{
+			name:  "partially overlapped cells are marked",
+			slots: []user.Slot{{StartTime: at(9, 9, 30), EndTime: at(9, 11, 15)}},
+			want:  grid([2]int{2, 9}, [2]int{2, 10}, [2]int{2, 11}),
+		},
+		{
+			name:  "a slot crossing midnight marks both days",
+			slots: []user.Slot{{StartTime: at(10, 23, 0), EndTime: at(11, 1, 0)}},
+			want:  grid([2]int{3, 23}, [2]int{4, 0}),
+		},
+		{
+			name: "slots are clipped to the week",
+			slots: []user.Slot{
+				{StartTime: at(6, 22, 0), EndTime: at(7, 1, 0)},
+				{StartTime: at(13, 23, 0), EndTime: at(14, 2, 0)},
+				{StartTime: at(14, 9, 0), EndTime: at(14, 10, 0)},
+			},
+			want: grid([2]int{0, 0}, [2]int{6, 23}),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, user.WeekCalendar{WeekStart: "2030-01-07", Days: tc.want}, user.Calendar(tc.slots, weekStart))
+		})
+	}
+
+	t.Run("weeks start on Monday", func(t *testing.T) {
+		for day := 7; day <= 13; day++ {
+			assert.Equal(t, weekStart, user.WeekStart(at(day, 15, 0)))
+		}
+		assert.Equal(t, weekStart.AddDate(0, 0, 7), user.WeekStart(at(14, 0, 0)))
+	})
+}
+
+func TestCommonAvailability(t *testing.T) {
+	at := func(hour int) time.Time { return time.Date(2030, 1, 1, hour, 0, 0, 0, time.UTC) }
+	slot := func(start, end int) user.Slot { return user.Slot{StartTime: at(start), EndTime: at(end)} }
+
+	for _, tc := range []struct {
+		name        string
+		slotsByUser [][]user.Slot
+		want        []user.Slot
+	}{
+		{
+			name:        "full intersection",
+			slotsByUser: [][]user.Slot{{slot(9, 17)}, {slot(8, 18)}, {slot(9, 12), slot(12, 17)}},
+			want:        []user.Slot{slot(9, 17)},
+		},
+		{
+			name:        "partial intersection keeps windows long enough",
+			slotsByUser: [][]user.Slot{{slot(9, 13), slot(14, 17)}, {slot(10, 15)}, {slot(9, 17)}},
+			want:        []user.Slot{slot(10, 13)},
+		},
+		{
+			name:        "no common time",
+			slotsByUser: [][]user.Slot{{slot(9, 11)}, {slot(11, 13)}},
+			want:        []user.Slot{},
+		},
+		{
+			name:        "user without slots",
+			slotsByUser: [][]user.Slot{{slot(9, 17)}, nil},
+			want:        []user.Slot{},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, user.CommonAvailability(tc.slotsByUser, at(9), at(17), 2*time.Hour))
+		})
+	}
+}
+
+func TestValidateSlots(t *testing.T) {
+	at := func(hour int) time.Time { return time.Date(2030, 1, 1, hour, 0, 0, 0, time.UTC) }
+	slot := func(start, end int) user.Slot { return user.Slot{StartTime: at(start), EndTime: at(end)} }
+	now := at(8)
+	existing := []user.Slot{slot(18, 20)}
+
+	for _, tc := range []struct {
+		name   string
+		slots  []user.Slot
+		merge  bool
+		want   []user.Slot
+		errors []validation.FieldError
+	}{
+		{
+			name:  "valid slots are sorted",
+			slots: []user.Slot{slot(14, 16), slot(9, 12)},
+			want:  []user.Slot{slot(9, 12), slot(14, 16)},
+		},
+		{
+			name:  "no slots",
+			slots: []user.Slot{},
+			want:  []user.Slot{},
+		},
+		{
+			name:  "reversed and past slots",
+			slots: []user.Slot{slot(12, 9), slot(5, 7), slot(9, 10)},
+			errors: []validation.FieldError{
+				{Field: "[0].end_time", Message: "slot 0: end time must be after start time"},
+				{Field: "[1].end_time", Message: "slot 1: has already ended"},
+			},
+		},
+		{
+			name:  "overlapping slots",
+			slots: []user.Slot{slot(9, 12), slot(11, 13), slot(12, 13), slot(19, 21)},
+			errors: []validation.FieldError{
+				{Field: "[1]", Message: "slot 1: overlaps slot 0"},
+				{Field: "[2]", Message: "slot 2: overlaps slot 1"},
+				{Field: "[3]", Message: "slot 3: overlaps existing availability from 2030-01-01T18:00:00Z to 2030-01-01T20:00:00Z"},
+			},
+		},
+		{
+			name:  "merge joins overlapping slots with the existing ones",
+			slots: []user.Slot{slot(9, 12), slot(11, 13), slot(19, 21)},
+			merge: true,
+			want:  []user.Slot{slot(9, 13), slot(18, 21)},
+		},
+		{
+			name:  "merge still rejects reversed slots",
+			slots: []user.Slot{slot(9, 12), slot(13, 11)},
+			merge: true,
+			errors: []validation.FieldError{
+				{Field: "[1].end_time", Message: "slot 1: end time must be after start time"},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := user.ValidateSlots(tc.slots, existing, now, tc.merge)
+			if tc.errors != nil {
+				var errs validation.Errors
+				require.ErrorAs(t, err, &errs)
+				assert.Equal(t, tc.errors, errs.FieldErrors())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
 }
@@ -0,0 +1,74 @@
+package user
+
+import (
+	"events-system/validation"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// ValidateSlots checks proposed availability against the user's existing slots without storing
+// anything, for editors that want to point at every problem before saving. Each slot must end after
+// it starts and must not have ended by now. Slots overlapping one another or an existing slot are
+// problems too, unless merge is set, in which case they are all joined. It returns the slots that
+// would be stored, in order, or validation.Errors naming each slot by its index in the body, e.g.
+// "[2].end_time".
+//
+// It is stricter than CreateUserSlots, which only enforces the overlap with existing slots: create
+// stores reversed, ended and mutually overlapping slots as sent, and WithMergeSlots only joins the
+// new slots that overlap an existing one. Without merge, slots passing ValidateSlots are stored by
+// create as returned here.
+func ValidateSlots(slots, existing []Slot, now time.Time, merge bool) ([]Slot, error) {
+	var errs validation.Errors
+	// Only slots with a valid range are checked for overlaps, an invalid range means nothing.
+	valid := make([]bool, len(slots))
+	for i, s := range slots {
+		switch {
+		case !s.EndTime.After(s.StartTime):
+			errs.Add(fmt.Sprintf("[%d].end_time", i), fmt.Sprintf("slot %d: end time must be after start time", i))
+		case !s.EndTime.After(now):
+			errs.Add(fmt.Sprintf("[%d].end_time", i), fmt.Sprintf("slot %d: has already ended", i))
+		default:
+			valid[i] = true
+		}
+	}
+
+	normalized := []Slot{}
+	var conflicts []Slot
+	for i, s := range slots {
+		if !valid[i] {
+			continue
+		}
+		normalized = append(normalized, s)
+		for _, e := range existing {
+			if !s.overlaps(e) {
+				continue
+			}
+			if merge {
+				if !slices.Contains(conflicts, e) {
+					conflicts = append(conflicts, e)
+				}
+				continue
+			}
+			errs.Add(fmt.Sprintf("[%d]", i), fmt.Sprintf("slot %d: overlaps existing availability from %s to %s", i,
+				e.StartTime.UTC().Format(time.RFC3339), e.EndTime.UTC().Format(time.RFC3339)))
+		}
+		if merge {
+			continue
+		}
+		for j := range i {
+			if valid[j] && s.overlaps(slots[j]) {
+				errs.Add(fmt.Sprintf("[%d]", i), fmt.Sprintf("slot %d: overlaps slot %d", i, j))
+			}
+		}
+	}
+	if err := errs.Err(); err != nil {
+		return nil, err
+	}
+
+	if merge {
+		return mergeAll(append(conflicts, normalized...)), nil
+	}
+	slices.SortFunc(normalized, func(a, b Slot) int { return a.StartTime.Compare(b.StartTime) })
+	return normalized, nil
+}
@@ -0,0 +1,49 @@
+// Package validation collects every invalid field of a value, rather than stopping at the first one,
+// so that a client can point at all of them at once.
+package validation
+
+import "strings"
+
+// FieldError is one invalid field, named as in the JSON body, e.g. "title" or "slots[2].end_time".
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Errors is the error returned by the Validate methods of the domain types.
+type Errors []FieldError
+
+// Add records that field is invalid.
+func (e *Errors) Add(field, message string) {
+	*e = append(*e, FieldError{Field: field, Message: message})
+}
+
+// Nest records the failures of a nested value, such as one slot of an event, under field. Their
+// messages are prefixed with label, since they would be ambiguous on their own.
+func (e *Errors) Nest(field, label string, nested Errors) {
+	for _, fe := range nested {
+		e.Add(field+"."+fe.Field, label+": "+fe.Message)
+	}
+}
+
+// Err returns the recorded failures as an error, or nil if there are none.
+func (e Errors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Error joins the messages, so that a single failure reads as it did before fields were reported.
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, fe := range e {
+		messages[i] = fe.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// FieldErrors returns the failures, one per invalid field.
+func (e Errors) FieldErrors() []FieldError {
+	return e
+}